@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// activityPageSize is the largest page the activity feed returns
+const activityPageSize = 100
+
+// ActivityLogs returns the latest 100 activity entries, newest first. Use
+// ActivityLogsAfter or FollowActivity to read every entry of a busy feed.
+func (c *Client) ActivityLogs(ctx context.Context) ([]ActivityLog, error) {
+	var logs []ActivityLog
+	if err := c.do(ctx, http.MethodGet, "/api/logs/", nil, nil, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ActivityLogsAfter returns up to limit entries with an id above afterID, oldest
+// first. A page of limit entries means more may be waiting. A limit outside 1..100
+// asks for the largest page.
+func (c *Client) ActivityLogsAfter(ctx context.Context, afterID, limit int) ([]ActivityLog, error) {
+	query := url.Values{"after_id": {strconv.Itoa(afterID)}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	var logs []ActivityLog
+	if err := c.do(ctx, http.MethodGet, "/api/logs/", query, nil, &logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// FollowActivity polls the activity feed every interval and yields entries with an
+// id above afterID, oldest first, until ctx is done or the loop stops. Each poll
+// pages through everything written since the last one, so bursts are not skipped.
+// Pass 0 to start with the latest entries instead of the whole history. A failed
+// poll is yielded as an error and polling continues if the loop does.
+func (c *Client) FollowActivity(ctx context.Context, afterID int, interval time.Duration) iter.Seq2[ActivityLog, error] {
+	return func(yield func(ActivityLog, error) bool) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		started := afterID != 0
+		for {
+			var err error
+			if started {
+				afterID, err = c.followPages(ctx, afterID, yield)
+			} else {
+				var logs []ActivityLog
+				if logs, err = c.ActivityLogs(ctx); err == nil {
+					started = true
+					slices.Reverse(logs)
+					afterID, err = yieldLogs(logs, afterID, yield)
+				}
+			}
+			if err == errStopped {
+				return
+			}
+			if err != nil && (ctx.Err() != nil || !yield(ActivityLog{}, err)) {
+				return
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}
+}
+
+// errStopped reports that the caller's loop stopped while yielding
+var errStopped = errors.New("client: iteration stopped")
+
+// followPages yields every entry after afterID a page at a time and returns the id of
+// the last one yielded
+func (c *Client) followPages(ctx context.Context, afterID int, yield func(ActivityLog, error) bool) (int, error) {
+	for {
+		logs, err := c.ActivityLogsAfter(ctx, afterID, activityPageSize)
+		if err != nil {
+			return afterID, err
+		}
+		if afterID, err = yieldLogs(logs, afterID, yield); err != nil {
+			return afterID, err
+		}
+		if len(logs) < activityPageSize {
+			return afterID, nil
+		}
+	}
+}
+
+func yieldLogs(logs []ActivityLog, afterID int, yield func(ActivityLog, error) bool) (int, error) {
+	for _, log := range logs {
+		afterID = log.ID
+		if !yield(log, nil) {
+			return afterID, errStopped
+		}
+	}
+	return afterID, nil
+}
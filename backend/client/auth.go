@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+type credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type authResponse struct {
+	Token string `json:"token"`
+	User  User   `json:"user"`
+}
+
+// Signup creates an account and authenticates the client as the new user
+func (c *Client) Signup(ctx context.Context, username, password string) (*User, error) {
+	return c.authenticate(ctx, "/api/auth/signup", username, password)
+}
+
+// Login authenticates the client, the token is sent with every following request
+func (c *Client) Login(ctx context.Context, username, password string) (*User, error) {
+	return c.authenticate(ctx, "/api/auth/login", username, password)
+}
+
+func (c *Client) authenticate(ctx context.Context, path, username, password string) (*User, error) {
+	var res authResponse
+	if err := c.do(ctx, http.MethodPost, path, nil, credentials{Username: username, Password: password}, &res); err != nil {
+		return nil, err
+	}
+
+	c.SetToken(res.Token)
+	return &res.User, nil
+}
+
+// Me returns the authenticated user
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var user User
+	if err := c.do(ctx, http.MethodGet, "/api/auth/me", nil, nil, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// Users lists every user sorted by username
+func (c *Client) Users(ctx context.Context) ([]User, error) {
+	var users []User
+	if err := c.do(ctx, http.MethodGet, "/api/users", nil, nil, &users); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// UpdateProfile changes the username and profile photo of the authenticated user
+func (c *Client) UpdateProfile(ctx context.Context, username, profilePhoto string) error {
+	body := map[string]string{"username": username, "profile_photo": profilePhoto}
+	return c.do(ctx, http.MethodPut, "/api/profile/", nil, body, nil)
+}
+
+// UpdatePassword changes the password of the authenticated user
+func (c *Client) UpdatePassword(ctx context.Context, password string) error {
+	return c.do(ctx, http.MethodPut, "/api/profile/password", nil, map[string]string{"password": password}, nil)
+}
@@ -0,0 +1,194 @@
+// Package client is a Go SDK for the todolist HTTP API.
+//
+//	c := client.New("http://localhost:8000")
+//	if _, err := c.Login(ctx, "alice", "secret"); err != nil {
+//		return err
+//	}
+//	tickets, err := c.ListTickets(ctx, &client.TicketFilter{Statuses: []string{client.StatusTodo}})
+//
+// Login and Signup remember the returned token and send it with every following
+// request. API failures are returned as *Error values carrying the status code and
+// the machine-readable code of the response envelope.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Client talks to one API server. It is safe for concurrent use.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+
+	mu    sync.RWMutex
+	token string
+}
+
+// Option customizes a Client
+type Option func(*Client)
+
+// WithHTTPClient sends requests through hc instead of a client with a 30s timeout
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithToken authenticates requests with a token obtained earlier
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the server at baseURL, e.g. http://localhost:8000
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		userAgent:  "todolist-go-client",
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token returns the bearer token sent with requests, empty when logged out
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken replaces the bearer token sent with requests
+func (c *Client) SetToken(token string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+}
+
+// Logout forgets the bearer token
+func (c *Client) Logout() {
+	c.SetToken("")
+}
+
+// envelope mirrors the response wrapper every endpoint uses
+type envelope struct {
+	Success bool            `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+	Code    string          `json:"code"`
+	Errors  []FieldError    `json:"errors"`
+}
+
+// do sends body encoded as JSON and decodes the data member of the response into
+// out when out is not nil
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, out any) error {
+	return c.doWithType(ctx, method, path, query, "application/json", body, out)
+}
+
+// doWithType is do with a custom content type for the JSON body
+func (c *Client) doWithType(ctx context.Context, method, path string, query url.Values, contentType string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req, err := c.newRequest(ctx, method, path, query, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return responseError(resp, raw)
+	}
+
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("decode response of %s %s: %w", method, path, err)
+	}
+	if !env.Success {
+		return responseError(resp, raw)
+	}
+
+	if out == nil || len(env.Data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return fmt.Errorf("decode data of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// newRequest builds an authenticated request for path on the server
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body io.Reader) (*http.Request, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if token := c.Token(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return req, nil
+}
+
+// responseError turns the envelope of a failed response into an *Error, bodies that are
+// not an envelope (a proxy error page, say) become the message
+func responseError(resp *http.Response, raw []byte) error {
+	var env envelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(raw))}
+	}
+
+	return &Error{
+		StatusCode: resp.StatusCode,
+		Code:       env.Code,
+		Message:    env.Error,
+		Fields:     env.Errors,
+		RequestID:  resp.Header.Get("X-Request-ID"),
+	}
+}
@@ -0,0 +1,332 @@
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-todolist/client"
+	"go-todolist/internal/apitest"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newClient starts the real router on in-memory storage
+func newClient(t *testing.T) (*client.Client, string) {
+	t.Helper()
+
+	h := apitest.New(t)
+	srv := httptest.NewServer(h.Handler)
+	t.Cleanup(srv.Close)
+
+	return client.New(srv.URL), srv.URL
+}
+
+func TestAuthTokenHandling(t *testing.T) {
+	ctx := context.Background()
+	c, url := newClient(t)
+
+	if _, err := c.Me(ctx); !client.IsUnauthorized(err) {
+		t.Fatalf("Me without token: got %v, want unauthorized", err)
+	}
+
+	alice, err := c.Signup(ctx, "alice", "password-alice")
+	if err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+	if c.Token() == "" {
+		t.Fatal("token not stored after signup")
+	}
+
+	me, err := c.Me(ctx)
+	if err != nil || me.ID != alice.ID {
+		t.Fatalf("me = %+v, %v", me, err)
+	}
+
+	_, err = c.Signup(ctx, "alice", "password-alice")
+	if !client.IsConflict(err) || !client.HasCode(err, "username_taken") {
+		t.Fatalf("duplicate signup: got %v", err)
+	}
+
+	other := client.New(url, client.WithToken(c.Token()))
+	if _, err := other.Me(ctx); err != nil {
+		t.Fatalf("reusing token: %v", err)
+	}
+
+	c.Logout()
+	if _, err := c.Me(ctx); !client.IsUnauthorized(err) {
+		t.Fatalf("Me after logout: got %v", err)
+	}
+
+	_, err = c.Login(ctx, "alice", "wrong-password")
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 401 || apiErr.Code != "invalid_credentials" {
+		t.Fatalf("wrong password: got %v", err)
+	}
+	if c.Token() != "" {
+		t.Fatal("failed login stored a token")
+	}
+
+	if _, err := c.Login(ctx, "alice", "password-alice"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if err := c.UpdateProfile(ctx, "alice2", ""); err != nil {
+		t.Fatalf("update profile: %v", err)
+	}
+	users, err := c.Users(ctx)
+	if err != nil || len(users) != 1 || users[0].Username != "alice2" {
+		t.Fatalf("users = %+v, %v", users, err)
+	}
+}
+
+func TestTickets(t *testing.T) {
+	ctx := context.Background()
+	c, url := newClient(t)
+
+	if _, err := c.Signup(ctx, "alice", "password-alice"); err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+	bobClient := client.New(url)
+	bob, err := bobClient.Signup(ctx, "bob", "password-bob")
+	if err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+
+	due := time.Now().UTC().Add(48 * time.Hour).Truncate(time.Second)
+	ticket, err := c.CreateTicket(ctx, client.TicketInput{
+		Title:    "Fix login bug",
+		Priority: client.PriorityHigh,
+		DueDate:  &due,
+	})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if ticket.Status != client.StatusBacklog || ticket.CreatorUsername != "alice" || ticket.DueDate == nil {
+		t.Fatalf("created %+v", ticket)
+	}
+
+	_, err = c.CreateTicket(ctx, client.TicketInput{Status: "Someday"})
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || !client.IsValidation(err) || len(apiErr.Fields) != 2 {
+		t.Fatalf("invalid create: got %v", err)
+	}
+
+	assigned, err := c.AssignTicket(ctx, ticket.ID, bob.ID)
+	if err != nil || assigned.AssigneeUsername == nil || *assigned.AssigneeUsername != "bob" {
+		t.Fatalf("assign = %+v, %v", assigned, err)
+	}
+
+	if err := bobClient.UpdateStatus(ctx, ticket.ID, client.StatusInProgress); err != nil {
+		t.Fatalf("update status: %v", err)
+	}
+
+	mine, err := bobClient.ListTickets(ctx, &client.TicketFilter{AssignedToMe: true, Statuses: []string{client.StatusInProgress}})
+	if err != nil || len(mine) != 1 || mine[0].ID != ticket.ID {
+		t.Fatalf("assigned to bob = %+v, %v", mine, err)
+	}
+	soon, err := c.ListTickets(ctx, &client.TicketFilter{DueWithinDays: client.Int(3)})
+	if err != nil || len(soon) != 1 {
+		t.Fatalf("due soon = %+v, %v", soon, err)
+	}
+
+	unassigned, err := c.UnassignTicket(ctx, ticket.ID)
+	if err != nil || unassigned.AssigneeID != nil {
+		t.Fatalf("unassign = %+v, %v", unassigned, err)
+	}
+
+	updated, err := c.UpdateTicket(ctx, ticket.ID, client.TicketInput{Title: "Fix login bug on Safari", Status: client.StatusDone, Priority: client.PriorityLow})
+	if err != nil || updated.Title != "Fix login bug on Safari" || updated.Status != client.StatusDone {
+		t.Fatalf("update = %+v, %v", updated, err)
+	}
+
+	results, err := c.Search(ctx, "safari", client.SearchOptions{Status: client.StatusDone})
+	if err != nil || len(results) != 1 || results[0].ID != ticket.ID {
+		t.Fatalf("search = %+v, %v", results, err)
+	}
+
+	if err := c.DeleteTicket(ctx, ticket.ID); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := c.GetTicket(ctx, ticket.ID); !client.IsNotFound(err) || !client.HasCode(err, "ticket_not_found") {
+		t.Fatalf("get trashed: got %v", err)
+	}
+	trash, err := c.Trash(ctx)
+	if err != nil || len(trash) != 1 {
+		t.Fatalf("trash = %+v, %v", trash, err)
+	}
+	if _, err := c.RestoreTicket(ctx, ticket.ID); err != nil {
+		t.Fatalf("restore: %v", err)
+	}
+
+	logs, err := c.ActivityLogs(ctx)
+	if err != nil || len(logs) == 0 || logs[0].Action != "restored ticket: Fix login bug on Safari" {
+		t.Fatalf("logs = %+v, %v", logs, err)
+	}
+}
+
+func TestPaginationHelpers(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newClient(t)
+
+	if _, err := c.Signup(ctx, "alice", "password-alice"); err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+
+	var ids []int
+	for i := 0; i < client.MaxBulkTickets+20; i++ {
+		ticket, err := c.CreateTicket(ctx, client.TicketInput{Title: "Bulk"})
+		if err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		ids = append(ids, ticket.ID)
+	}
+	ids = append(ids, 99999)
+
+	res, err := c.BulkUpdate(ctx, ids, client.BulkChanges{Status: client.String(client.StatusDone)})
+	if err != nil {
+		t.Fatalf("bulk update: %v", err)
+	}
+	if res.Updated != len(ids)-1 || res.Failed != 1 || len(res.Results) != len(ids) {
+		t.Fatalf("got updated %d failed %d results %d", res.Updated, res.Failed, len(res.Results))
+	}
+
+	seen := 0
+	for ticket, err := range c.Tickets(ctx, &client.TicketFilter{Statuses: []string{client.StatusDone}}) {
+		if err != nil {
+			t.Fatalf("iterate: %v", err)
+		}
+		if ticket.Status != client.StatusDone {
+			t.Fatalf("ticket %d has status %q", ticket.ID, ticket.Status)
+		}
+		seen++
+		if seen == 10 {
+			break
+		}
+	}
+	if seen != 10 {
+		t.Fatalf("iterated %d tickets, want to stop at 10", seen)
+	}
+}
+
+func TestFollowActivity(t *testing.T) {
+	c, _ := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Signup(ctx, "alice", "password-alice"); err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+	first, err := c.CreateTicket(ctx, client.TicketInput{Title: "Before"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	logs, err := c.ActivityLogs(ctx)
+	if err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		c.UpdateStatus(ctx, first.ID, client.StatusTodo)
+		c.UpdateStatus(ctx, first.ID, client.StatusDone)
+	}()
+
+	var actions []string
+	for log, err := range c.FollowActivity(ctx, logs[0].ID, 20*time.Millisecond) {
+		if err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+		actions = append(actions, log.Action)
+		if len(actions) == 2 {
+			break
+		}
+	}
+	if len(actions) != 2 || actions[0] != "changed status to Todo" || actions[1] != "changed status to Done" {
+		t.Fatalf("got %v", actions)
+	}
+}
+
+// TestFollowActivityBurst writes more entries between two polls than fit in a page
+func TestFollowActivityBurst(t *testing.T) {
+	c, _ := newClient(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := c.Signup(ctx, "alice", "password-alice"); err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+	first, err := c.CreateTicket(ctx, client.TicketInput{Title: "Before"})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	logs, err := c.ActivityLogs(ctx)
+	if err != nil {
+		t.Fatalf("logs: %v", err)
+	}
+	for i := 0; i < 150; i++ {
+		if err := c.UpdateStatus(ctx, first.ID, client.StatusTodo); err != nil {
+			t.Fatalf("status: %v", err)
+		}
+	}
+
+	seen := 0
+	lastID := logs[0].ID
+	for log, err := range c.FollowActivity(ctx, logs[0].ID, time.Hour) {
+		if err != nil {
+			t.Fatalf("follow: %v", err)
+		}
+		if log.ID <= lastID {
+			t.Fatalf("entry %d after %d, want ascending ids", log.ID, lastID)
+		}
+		lastID = log.ID
+		if seen++; seen == 150 {
+			break
+		}
+	}
+	if seen != 150 {
+		t.Fatalf("followed %d entries, want 150", seen)
+	}
+}
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newClient(t)
+
+	alice, err := c.Signup(ctx, "alice", "password-alice")
+	if err != nil {
+		t.Fatalf("signup: %v", err)
+	}
+	for _, title := range []string{"First", `Second, "quoted"`} {
+		if _, err := c.CreateTicket(ctx, client.TicketInput{Title: title, Status: client.StatusTodo}); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+	}
+	if _, err := c.CreateTicket(ctx, client.TicketInput{Title: "Shipped", Status: client.StatusDone}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	var buf bytes.Buffer
+	filter := &client.TicketFilter{Statuses: []string{client.StatusTodo}}
+	n, err := c.ExportTickets(ctx, filter, client.ExportOptions{Columns: []string{"title", "status"}}, &buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatalf("export tickets: %d bytes, %v", n, err)
+	}
+	if want := "title,status\n\"Second, \"\"quoted\"\"\",Todo\nFirst,Todo\n"; buf.String() != want {
+		t.Fatalf("got %q, want %q", buf.String(), want)
+	}
+
+	buf.Reset()
+	activity := &client.ActivityFilter{UserID: client.Int(alice.ID)}
+	if _, err := c.ExportActivity(ctx, activity, client.ExportOptions{Format: client.ExportNDJSON, Columns: []string{"username"}}, &buf); err != nil {
+		t.Fatalf("export activity: %v", err)
+	}
+	if got := strings.Count(buf.String(), `{"username":"alice"}`); got != 3 {
+		t.Fatalf("got %q", buf.String())
+	}
+
+	_, err = c.ExportTickets(ctx, nil, client.ExportOptions{Format: "xlsx"}, &bytes.Buffer{})
+	if !client.IsValidation(err) {
+		t.Fatalf("invalid format: got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// MaxBulkTickets is the number of tickets the server accepts in one bulk update,
+// BulkUpdate splits larger batches
+const MaxBulkTickets = 500
+
+func ticketPath(id int) string {
+	return "/api/tickets/" + strconv.Itoa(id)
+}
+
+// values encodes the filter as the query string of the ticket listing
+func (f *TicketFilter) values() url.Values {
+	q := url.Values{}
+	if f == nil {
+		return q
+	}
+
+	for _, s := range f.Statuses {
+		q.Add("status", s)
+	}
+	for _, p := range f.Priorities {
+		q.Add("priority", p)
+	}
+	if f.AssigneeID != nil {
+		q.Set("assignee_id", strconv.Itoa(*f.AssigneeID))
+	}
+	if f.CreatorID != nil {
+		q.Set("creator_id", strconv.Itoa(*f.CreatorID))
+	}
+	if f.AssignedToMe {
+		q.Set("assigned_to_me", "true")
+	}
+	if f.DueWithinDays != nil {
+		q.Set("due_within_days", strconv.Itoa(*f.DueWithinDays))
+	}
+	if f.DueAfter != nil {
+		q.Set("due_after", f.DueAfter.Format(time.RFC3339))
+	}
+	if f.DueBefore != nil {
+		q.Set("due_before", f.DueBefore.Format(time.RFC3339))
+	}
+	return q
+}
+
+// ListTickets returns the live tickets matching filter, newest first. A nil filter
+// lists every ticket.
+func (c *Client) ListTickets(ctx context.Context, filter *TicketFilter) ([]Ticket, error) {
+	var tickets []Ticket
+	if err := c.do(ctx, http.MethodGet, "/api/tickets/", filter.values(), nil, &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// Tickets iterates over the tickets matching filter. The listing endpoint is not
+// paginated and returns every match in one response, so this is ListTickets behind an
+// iterator: it makes a single request and holds the whole result in memory.
+func (c *Client) Tickets(ctx context.Context, filter *TicketFilter) iter.Seq2[Ticket, error] {
+	return func(yield func(Ticket, error) bool) {
+		tickets, err := c.ListTickets(ctx, filter)
+		if err != nil {
+			yield(Ticket{}, err)
+			return
+		}
+		for _, t := range tickets {
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Trash lists the soft-deleted tickets, most recently deleted first
+func (c *Client) Trash(ctx context.Context) ([]Ticket, error) {
+	var tickets []Ticket
+	if err := c.do(ctx, http.MethodGet, "/api/tickets/trash", nil, nil, &tickets); err != nil {
+		return nil, err
+	}
+	return tickets, nil
+}
+
+// GetTicket returns one live ticket
+func (c *Client) GetTicket(ctx context.Context, id int) (*Ticket, error) {
+	return c.ticket(ctx, http.MethodGet, ticketPath(id), nil)
+}
+
+// CreateTicket creates a ticket owned by the authenticated user
+func (c *Client) CreateTicket(ctx context.Context, input TicketInput) (*Ticket, error) {
+	return c.ticket(ctx, http.MethodPost, "/api/tickets/", input)
+}
+
+// UpdateTicket replaces the editable fields of a ticket, an empty status or priority
+// keeps the stored value
+func (c *Client) UpdateTicket(ctx context.Context, id int, input TicketInput) (*Ticket, error) {
+	return c.ticket(ctx, http.MethodPut, ticketPath(id), input)
+}
+
+// PatchTicket changes only the members present in patch
+func (c *Client) PatchTicket(ctx context.Context, id int, patch TicketPatch) (*Ticket, error) {
+	var ticket Ticket
+	if err := c.doWithType(ctx, http.MethodPatch, ticketPath(id), nil, "application/merge-patch+json", patch, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+// AssignTicket assigns a ticket to a user
+func (c *Client) AssignTicket(ctx context.Context, id, userID int) (*Ticket, error) {
+	return c.PatchTicket(ctx, id, TicketPatch{"assignee_id": userID})
+}
+
+// UnassignTicket removes the assignee of a ticket
+func (c *Client) UnassignTicket(ctx context.Context, id int) (*Ticket, error) {
+	return c.PatchTicket(ctx, id, TicketPatch{"assignee_id": nil})
+}
+
+// UpdateStatus moves a ticket to another column
+func (c *Client) UpdateStatus(ctx context.Context, id int, status string) error {
+	return c.do(ctx, http.MethodPatch, ticketPath(id)+"/status", nil, map[string]string{"status": status}, nil)
+}
+
+// DeleteTicket moves a ticket to the trash
+func (c *Client) DeleteTicket(ctx context.Context, id int) error {
+	return c.do(ctx, http.MethodDelete, ticketPath(id), nil, nil, nil)
+}
+
+// RestoreTicket brings a ticket back from the trash
+func (c *Client) RestoreTicket(ctx context.Context, id int) (*Ticket, error) {
+	return c.ticket(ctx, http.MethodPost, ticketPath(id)+"/restore", nil)
+}
+
+// BulkUpdate applies changes to every ticket in ids. Batches larger than
+// MaxBulkTickets are sent in several requests and their results merged; a failed
+// request stops the update and returns the results gathered so far.
+func (c *Client) BulkUpdate(ctx context.Context, ids []int, changes BulkChanges) (*BulkResult, error) {
+	merged := &BulkResult{}
+	for start := 0; start < len(ids); start += MaxBulkTickets {
+		end := min(start+MaxBulkTickets, len(ids))
+
+		body := struct {
+			TicketIDs []int       `json:"ticket_ids"`
+			Changes   BulkChanges `json:"changes"`
+		}{ids[start:end], changes}
+
+		var res BulkResult
+		if err := c.do(ctx, http.MethodPost, "/api/tickets/bulk", nil, body, &res); err != nil {
+			return merged, err
+		}
+		merged.Updated += res.Updated
+		merged.Failed += res.Failed
+		merged.Results = append(merged.Results, res.Results...)
+	}
+	return merged, nil
+}
+
+// Search runs a full-text search over live tickets, best match first
+func (c *Client) Search(ctx context.Context, query string, opts SearchOptions) ([]SearchResult, error) {
+	q := url.Values{"q": {query}}
+	if opts.Status != "" {
+		q.Set("status", opts.Status)
+	}
+	if opts.AssigneeID != nil {
+		q.Set("assignee_id", strconv.Itoa(*opts.AssigneeID))
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+
+	var results []SearchResult
+	if err := c.do(ctx, http.MethodGet, "/api/search", q, nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (c *Client) ticket(ctx context.Context, method, path string, body any) (*Ticket, error) {
+	var ticket Ticket
+	if err := c.do(ctx, method, path, nil, body, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,128 @@
+package client
+
+import "time"
+
+// Ticket statuses, in board order
+const (
+	StatusBacklog    = "Backlog"
+	StatusTodo       = "Todo"
+	StatusInProgress = "In Progress"
+	StatusDone       = "Done"
+)
+
+// Ticket priorities
+const (
+	PriorityLow    = "Low"
+	PriorityMedium = "Medium"
+	PriorityHigh   = "High"
+)
+
+// User is an account, the password is never returned
+type User struct {
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	ProfilePhoto string     `json:"profile_photo,omitempty"`
+	Role         string     `json:"role,omitempty"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Ticket is a card on the board
+type Ticket struct {
+	ID               int        `json:"id"`
+	Title            string     `json:"title"`
+	Description      string     `json:"description"`
+	Status           string     `json:"status"`
+	Priority         string     `json:"priority"`
+	DueDate          *time.Time `json:"due_date,omitempty"`
+	CreatorID        int        `json:"creator_id"`
+	CreatorUsername  string     `json:"creator_username"`
+	AssigneeID       *int       `json:"assignee_id"`
+	AssigneeUsername *string    `json:"assignee_username,omitempty"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+}
+
+// TicketInput is the body of a create or full update. Empty status and priority
+// default to Backlog and Medium on create and keep the stored values on update.
+type TicketInput struct {
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Priority    string     `json:"priority,omitempty"`
+	DueDate     *time.Time `json:"due_date,omitempty"`
+	AssigneeID  *int       `json:"assignee_id,omitempty"`
+}
+
+// TicketPatch is a JSON merge patch: only the members present are changed and a
+// nil value clears a nullable member, e.g. {"assignee_id": nil} unassigns
+type TicketPatch map[string]any
+
+// TicketFilter narrows a ticket listing, zero fields are ignored
+type TicketFilter struct {
+	Statuses      []string   `json:"status,omitempty"`
+	Priorities    []string   `json:"priority,omitempty"`
+	AssigneeID    *int       `json:"assignee_id,omitempty"`
+	CreatorID     *int       `json:"creator_id,omitempty"`
+	AssignedToMe  bool       `json:"assigned_to_me,omitempty"`
+	DueWithinDays *int       `json:"due_within_days,omitempty"`
+	DueAfter      *time.Time `json:"due_after,omitempty"`
+	DueBefore     *time.Time `json:"due_before,omitempty"`
+}
+
+// BulkChanges is applied to every ticket of a bulk update, nil fields are left untouched
+type BulkChanges struct {
+	Status     *string `json:"status,omitempty"`
+	Priority   *string `json:"priority,omitempty"`
+	AssigneeID *int    `json:"assignee_id,omitempty"`
+	Unassign   bool    `json:"unassign,omitempty"`
+}
+
+// BulkResult reports the outcome of a bulk update
+type BulkResult struct {
+	Updated int              `json:"updated"`
+	Failed  int              `json:"failed"`
+	Results []BulkItemResult `json:"results"`
+}
+
+// BulkItemResult is the outcome for one ticket of a bulk update
+type BulkItemResult struct {
+	TicketID int    `json:"ticket_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SearchResult is a ticket matching a full-text search
+type SearchResult struct {
+	Ticket
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+// SearchOptions narrows a full-text search, zero fields are ignored
+type SearchOptions struct {
+	Status     string
+	AssigneeID *int
+	Limit      int
+}
+
+// ActivityLog is an entry of the activity feed
+type ActivityLog struct {
+	ID        int       `json:"id"`
+	TicketID  *int      `json:"ticket_id"`
+	UserID    int       `json:"user_id"`
+	Username  string    `json:"username"`
+	Action    string    `json:"action"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Int returns a pointer to v, handy for the optional ids of filters and inputs
+func Int(v int) *int {
+	return &v
+}
+
+// String returns a pointer to v, handy for the optional fields of BulkChanges
+func String(v string) *string {
+	return &v
+}
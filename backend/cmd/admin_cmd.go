@@ -0,0 +1,298 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"go-todolist/internal/config"
+	"go-todolist/internal/database"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/service"
+	"go-todolist/internal/utils"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+const adminUsage = `Usage: server admin <command> [flags] [args]
+
+Commands:
+  create-user [-admin] [-password-stdin] <username>
+  reset-password [-password-stdin] <username>
+  promote <username>         grant the admin role
+  demote <username>          revoke the admin role
+  disable <username>         block logins and refuse the tokens the user already holds
+  enable <username>          allow a disabled user to log in again
+  reassign -by <username> <from> <to>
+                             move every live ticket assigned to one user to another,
+                             recording the change as made by -by
+  stats [-json]              print user, ticket and database statistics
+
+Without -password-stdin a random password is generated and printed.
+`
+
+// adminCommand runs one admin subcommand against the opened storage
+type adminCommand func(ctx context.Context, admin *service.AdminService, repos *repositories, args []string) error
+
+// runAdmin handles "admin ..."
+func runAdmin(cfg *config.Config, args []string) {
+	commands := map[string]adminCommand{
+		"create-user":    adminCreateUser,
+		"reset-password": adminResetPassword,
+		"promote":        adminSetRole(domain.RoleAdmin),
+		"demote":         adminSetRole(domain.RoleUser),
+		"disable":        adminSetDisabled(true),
+		"enable":         adminSetDisabled(false),
+		"reassign":       adminReassign,
+		"stats":          adminStats,
+	}
+
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, adminUsage)
+		os.Exit(2)
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown admin command %q\n\n%s", args[0], adminUsage)
+		os.Exit(2)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	utils.SetJWTSecret(cfg.JWT.Secret)
+
+	repos, err := openRepositories(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open storage: %v\n", err)
+		os.Exit(1)
+	}
+
+	authService := service.NewAuthService(repos.users)
+	admin := service.NewAdminService(repos.users, repos.tickets, authService)
+
+	err = cmd(context.Background(), admin, repos, args[1:])
+	repos.close()
+	if err == errAdminUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// errAdminUsage means the usage was already printed, the command exits with 2
+var errAdminUsage = errors.New("usage")
+
+// parseAdminFlags parses flags and expects exactly nargs positional arguments
+func parseAdminFlags(flags *flag.FlagSet, args []string, synopsis string, nargs int) error {
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: server admin %s %s\n", flags.Name(), synopsis)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return errAdminUsage
+	}
+	if flags.NArg() != nargs {
+		flags.Usage()
+		return errAdminUsage
+	}
+	return nil
+}
+
+// readPassword reads a password from stdin or generates one. The bool reports
+// whether it was generated and must be shown to the operator.
+func readPassword(fromStdin bool) (string, bool, error) {
+	if fromStdin {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" && err != nil {
+			return "", false, fmt.Errorf("failed to read password: %w", err)
+		}
+		return line, false, nil
+	}
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", false, fmt.Errorf("failed to generate password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), true, nil
+}
+
+func adminCreateUser(ctx context.Context, admin *service.AdminService, _ *repositories, args []string) error {
+	flags := flag.NewFlagSet("create-user", flag.ContinueOnError)
+	isAdmin := flags.Bool("admin", false, "grant the admin role")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from stdin")
+	if err := parseAdminFlags(flags, args, "[-admin] [-password-stdin] <username>", 1); err != nil {
+		return err
+	}
+
+	password, generated, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+
+	role := domain.RoleUser
+	if *isAdmin {
+		role = domain.RoleAdmin
+	}
+	user, err := admin.CreateUser(ctx, flags.Arg(0), password, role)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s %q (id %d)\n", user.Role, user.Username, user.ID)
+	if generated {
+		fmt.Printf("Password: %s\n", password)
+	}
+	return nil
+}
+
+func adminResetPassword(ctx context.Context, admin *service.AdminService, _ *repositories, args []string) error {
+	flags := flag.NewFlagSet("reset-password", flag.ContinueOnError)
+	passwordStdin := flags.Bool("password-stdin", false, "read the new password from stdin")
+	if err := parseAdminFlags(flags, args, "[-password-stdin] <username>", 1); err != nil {
+		return err
+	}
+
+	password, generated, err := readPassword(*passwordStdin)
+	if err != nil {
+		return err
+	}
+	if err := admin.ResetPassword(ctx, flags.Arg(0), password); err != nil {
+		return err
+	}
+
+	fmt.Printf("Reset the password of %q\n", flags.Arg(0))
+	if generated {
+		fmt.Printf("Password: %s\n", password)
+	}
+	return nil
+}
+
+func adminSetRole(role string) adminCommand {
+	name := "promote"
+	if role == domain.RoleUser {
+		name = "demote"
+	}
+
+	return func(ctx context.Context, admin *service.AdminService, _ *repositories, args []string) error {
+		flags := flag.NewFlagSet(name, flag.ContinueOnError)
+		if err := parseAdminFlags(flags, args, "<username>", 1); err != nil {
+			return err
+		}
+
+		user, err := admin.SetRole(ctx, flags.Arg(0), role)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%q is now %s\n", user.Username, user.Role)
+		return nil
+	}
+}
+
+func adminSetDisabled(disabled bool) adminCommand {
+	name := "enable"
+	if disabled {
+		name = "disable"
+	}
+
+	return func(ctx context.Context, admin *service.AdminService, _ *repositories, args []string) error {
+		flags := flag.NewFlagSet(name, flag.ContinueOnError)
+		if err := parseAdminFlags(flags, args, "<username>", 1); err != nil {
+			return err
+		}
+
+		user, err := admin.SetDisabled(ctx, flags.Arg(0), disabled)
+		if err != nil {
+			return err
+		}
+		if user.Disabled() {
+			fmt.Printf("Disabled %q\n", user.Username)
+		} else {
+			fmt.Printf("Enabled %q\n", user.Username)
+		}
+		return nil
+	}
+}
+
+func adminReassign(ctx context.Context, admin *service.AdminService, _ *repositories, args []string) error {
+	flags := flag.NewFlagSet("reassign", flag.ContinueOnError)
+	actor := flags.String("by", "", "username the activity entries are attributed to, required")
+	if err := parseAdminFlags(flags, args, "-by <username> <from> <to>", 2); err != nil {
+		return err
+	}
+	if *actor == "" {
+		flags.Usage()
+		return errAdminUsage
+	}
+
+	n, err := admin.ReassignTickets(ctx, *actor, flags.Arg(0), flags.Arg(1))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Reassigned %d tickets from %q to %q\n", n, flags.Arg(0), flags.Arg(1))
+	return nil
+}
+
+func adminStats(ctx context.Context, admin *service.AdminService, repos *repositories, args []string) error {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	asJSON := flags.Bool("json", false, "print JSON")
+	if err := parseAdminFlags(flags, args, "[-json]", 0); err != nil {
+		return err
+	}
+
+	stats, err := admin.Stats(ctx)
+	if err != nil {
+		return err
+	}
+
+	// table sizes are only available from PostgreSQL
+	var dbStats *database.Stats
+	if repos.pool != nil {
+		if dbStats, err = database.CollectStats(ctx, repos.pool); err != nil {
+			return err
+		}
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(struct {
+			*service.AdminStats
+			Database *database.Stats `json:"database,omitempty"`
+		}{stats, dbStats})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "Users\t%d\t(%d admins, %d disabled)\n", stats.Users, stats.Admins, stats.DisabledUsers)
+	fmt.Fprintf(w, "Tickets\t%d\t\n", stats.Tickets)
+	statuses := make([]string, 0, len(stats.TicketsByStatus))
+	for _, s := range domain.TicketStatuses {
+		statuses = append(statuses, s)
+	}
+	for s := range stats.TicketsByStatus {
+		if !domain.IsValidTicketStatus(s) {
+			statuses = append(statuses, s)
+		}
+	}
+	for _, s := range statuses {
+		fmt.Fprintf(w, "  %s\t%d\t\n", s, stats.TicketsByStatus[s])
+	}
+	fmt.Fprintf(w, "Trash\t%d\t\n", stats.TrashedTickets)
+
+	if dbStats != nil {
+		fmt.Fprintf(w, "\nDatabase\t%s\t%s\n", dbStats.Database, dbStats.Size)
+		for _, t := range dbStats.Tables {
+			fmt.Fprintf(w, "  %s\t~%d rows\t%s\n", t.Name, t.LiveRows, t.TotalSize)
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"go-todolist/internal/config"
+	"os"
+
+	"github.com/goccy/go-yaml"
+)
+
+// runConfig handles "config print"
+func runConfig(cfg *config.Config, args []string) {
+	if len(args) != 1 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: server config print")
+		os.Exit(2)
+	}
+
+	out, err := yaml.Marshal(cfg.Redacted())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to encode configuration: %v\n", err)
+		os.Exit(1)
+	}
+	os.Stdout.Write(out)
+
+	// still print an invalid config, it is usually why someone is looking at it
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -1,58 +1,129 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"go-todolist/internal/app"
 	"go-todolist/internal/config"
-	"go-todolist/internal/database"
-	"go-todolist/internal/handler"
-	"go-todolist/internal/repository"
-	"go-todolist/internal/service"
+	"go-todolist/internal/health"
+	"go-todolist/internal/logging"
+	"go-todolist/internal/metrics"
+	"go-todolist/internal/tracing"
 	"go-todolist/internal/utils"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 )
 
+const usage = `Usage: server [-config file] [command]
+
+Commands:
+  serve          run the HTTP server (default)
+  config print   print the effective configuration with secrets redacted
+  admin ...      manage users and data, run "server admin" for details
+  seed           fill the database with generated demo data, see "server seed -h"
+`
+
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
 	// load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	args := flag.Args()
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	switch args[0] {
+	case "serve":
+		runServer(cfg)
+	case "config":
+		runConfig(cfg, args[1:])
+	case "admin":
+		runAdmin(cfg, args[1:])
+	case "seed":
+		runSeed(cfg, args[1:])
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// runServer starts the API and blocks until it shuts down
+func runServer(cfg *config.Config) {
+	// refuse to start with an invalid or insecure configuration
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
+	// setup structured logging
+	if _, err := logging.Setup(os.Stdout, cfg.Log.Level, cfg.Log.Format); err != nil {
+		log.Fatalf("Failed to setup logging: %v", err)
+	}
+
+	// setup tracing, flushing buffered spans on exit
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing.Exporter, cfg.Tracing.OTLPEndpoint, cfg.Tracing.ServiceName)
+	if err != nil {
+		log.Fatalf("Failed to setup tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("failed to flush spans", "error", err)
+		}
+	}()
+
 	// set JWT secret
 	utils.SetJWTSecret(cfg.JWT.Secret)
 
-	// Connect to database
-	db, err := database.Connect(cfg.GetDSN())
+	// Open the configured storage backend
+	repos, err := openRepositories(cfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		slog.Error("failed to open storage", "storage", cfg.Storage, "error", err)
+		os.Exit(1)
 	}
-	defer database.Close(db)
+	defer repos.close()
 
-	// Initialize repositories
-	userRepo := repository.NewUserRepository(db)
-	ticketRepo := repository.NewTicketRepository(db)
-	activityLogRepo := repository.NewActivityLogRepository(db)
+	// Expose pool and ticket metrics
+	if repos.pool != nil {
+		metrics.RegisterPoolStats(repos.pool)
+	}
+	metrics.RegisterTicketStatusGauge(repos.tickets)
 
 	// Initialize services
-	authService := service.NewAuthService(userRepo)
-	activityLogService := service.NewActivityLogService(activityLogRepo)
-	ticketService := service.NewTicketService(ticketRepo, activityLogRepo)
+	services := app.NewServices(app.Repositories{
+		Users:        repos.users,
+		Tickets:      repos.tickets,
+		ActivityLogs: repos.activityLogs,
+		SavedFilters: repos.savedFilters,
+	})
 
-	// Initialize handlers
-	authHanler := handler.NewAuthHandler(authService)
-	ticketHandler := handler.NewTicketHandler(ticketService)
-	activityLogHandler := handler.NewActivityLogHandler(activityLogService)
+	// Purge expired tickets from the trash in the background until shutdown
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	defer stopPurge()
+	go services.Tickets.RunTrashPurger(purgeCtx, cfg.Trash.Retention, time.Hour)
 
 	// Setup router
-	router := app.NewRouter(
-		authHanler,
-		ticketHandler,
-		activityLogHandler,
-	)
+	healthChecker := health.NewChecker(repos.probe)
+	router := app.NewRouterFromServices(cfg, services, healthChecker)
 
 	// Create and start server
-	server := app.NewServer(router.Setup(), cfg.Server.Port)
+	server := app.NewServer(router.Setup(), cfg.Server, healthChecker)
 	if err := server.Start(); err != nil {
-		log.Fatalf("Server error: %v", err)
+		slog.Error("server error", "error", err)
+		os.Exit(1)
 	}
 }
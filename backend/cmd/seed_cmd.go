@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-todolist/internal/config"
+	"go-todolist/internal/seed"
+	"os"
+	"time"
+)
+
+// runSeed handles "seed", it fills the configured storage with generated data
+func runSeed(cfg *config.Config, args []string) {
+	opts := seed.DefaultOptions()
+
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Usage: server seed [flags]\n\nThe same -seed and -now always generate the same data.\n\nFlags:")
+		flags.PrintDefaults()
+	}
+	flags.Uint64Var(&opts.Seed, "seed", opts.Seed, "random seed")
+	flags.IntVar(&opts.Users, "users", opts.Users, "number of users, existing users with the same name are reused")
+	flags.IntVar(&opts.Tickets, "tickets", opts.Tickets, "number of tickets")
+	flags.IntVar(&opts.Days, "days", opts.Days, "days of history before -now")
+	flags.IntVar(&opts.BatchSize, "batch", opts.BatchSize, "tickets per transaction")
+	flags.StringVar(&opts.Password, "password", opts.Password, "password of every seeded user")
+	now := flags.String("now", opts.Now.Format(time.DateOnly), "reference date for history and due dates")
+	force := flags.Bool("force", false, "seed even though APP_ENV is production, -password is then required")
+	flags.Parse(args)
+
+	if flags.NArg() != 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	if opts.Now, err = time.Parse(time.DateOnly, *now); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -now %q, use YYYY-MM-DD\n", *now)
+		os.Exit(2)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if err := checkSeedTarget(cfg, flags, *force); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if cfg.Storage == config.StorageMemory {
+		fmt.Fprintln(os.Stderr, "seeding in-memory storage has no effect, the data is gone when the command exits")
+		os.Exit(1)
+	}
+
+	repos, err := openRepositories(cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open storage: %v\n", err)
+		os.Exit(1)
+	}
+	defer repos.close()
+
+	start := time.Now()
+	opts.Progress = func(n int) {
+		fmt.Fprintf(os.Stderr, "\r%d/%d tickets", n, opts.Tickets)
+	}
+
+	result, err := seed.Run(context.Background(), repos.users, repos.tickets, opts)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		repos.close()
+		os.Exit(1)
+	}
+
+	fmt.Printf("Seeded %d users (%d new), %d tickets and %d activity entries in %s\n",
+		result.Users, result.CreatedUsers, result.Tickets, result.ActivityLogs, time.Since(start).Round(time.Millisecond))
+	fmt.Printf("Every seeded user logs in with the password %q, the admin account is \"admin\"\n", opts.Password)
+}
+
+// checkSeedTarget refuses to fill a production database with generated accounts,
+// including an admin, unless -force is given. Even then the well-known default
+// password is not accepted.
+func checkSeedTarget(cfg *config.Config, flags *flag.FlagSet, force bool) error {
+	if cfg.Env != config.EnvProduction {
+		return nil
+	}
+	if !force {
+		return errors.New("refusing to seed a production environment, pass -force if this is intended")
+	}
+
+	passwordSet := false
+	flags.Visit(func(f *flag.Flag) {
+		passwordSet = passwordSet || f.Name == "password"
+	})
+	if !passwordSet {
+		return errors.New("seeding a production environment needs an explicit -password")
+	}
+	return nil
+}
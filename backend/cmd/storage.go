@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go-todolist/internal/config"
+	"go-todolist/internal/database"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/health"
+	"go-todolist/internal/repository"
+	"go-todolist/internal/repository/memory"
+	"go-todolist/internal/repository/sqlite"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// repositories is the storage backend selected by config.Config.Storage
+type repositories struct {
+	users        domain.UserRepository
+	tickets      domain.TicketRepository
+	activityLogs domain.ActivityLogRepository
+	savedFilters domain.SavedFilterRepository
+
+	// pool is nil unless the backend is PostgreSQL
+	pool *pgxpool.Pool
+	// probe is what readiness checks ping, nil for the memory backend
+	probe health.Database
+	close func()
+}
+
+// openRepositories connects the configured backend, running migrations first when
+// AutoMigrate is on
+func openRepositories(cfg *config.Config) (*repositories, error) {
+	switch cfg.Storage {
+	case config.StorageMemory:
+		slog.Warn("using in-memory storage, data is lost on restart")
+
+		store := memory.NewStore()
+		return &repositories{
+			users:        memory.NewUserRepository(store),
+			tickets:      memory.NewTicketRepository(store),
+			activityLogs: memory.NewActivityLogRepository(store),
+			savedFilters: memory.NewSavedFilterRepository(store),
+			close:        func() {},
+		}, nil
+
+	case config.StorageSQLite:
+		db, err := sqlite.Open(context.Background(), cfg.Database.SQLitePath)
+		if err != nil {
+			return nil, err
+		}
+
+		return &repositories{
+			users:        sqlite.NewUserRepository(db),
+			tickets:      sqlite.NewTicketRepository(db),
+			activityLogs: sqlite.NewActivityLogRepository(db),
+			savedFilters: sqlite.NewSavedFilterRepository(db),
+			probe:        health.SQLite(db),
+			close:        func() { db.Close() },
+		}, nil
+
+	case config.StoragePostgres:
+		db, err := database.Connect(cfg.GetDSN(), cfg.Database)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+
+		// Bring the schema up to date
+		if cfg.Database.AutoMigrate {
+			if err := database.Migrate(context.Background(), db); err != nil {
+				database.Close(db)
+				return nil, fmt.Errorf("failed to run migrations: %w", err)
+			}
+		}
+
+		return &repositories{
+			users:        repository.NewUserRepository(db),
+			tickets:      repository.NewTicketRepository(db),
+			activityLogs: repository.NewActivityLogRepository(db),
+			savedFilters: repository.NewSavedFilterRepository(db),
+			pool:         db,
+			probe:        health.Postgres(db),
+			close:        func() { database.Close(db) },
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+func (c *cli) login(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("login", flag.ContinueOnError)
+	username := flags.String("u", c.config.Username, "username")
+	passwordStdin := flags.Bool("password-stdin", false, "read the password from stdin instead of prompting")
+	if err := c.parseFlags(flags, args, "[-u username] [-password-stdin]", 0); err != nil {
+		return err
+	}
+
+	in := bufio.NewReader(c.stdin)
+	if *username == "" {
+		name, err := c.prompt(in, "Username: ")
+		if err != nil {
+			return err
+		}
+		*username = name
+	}
+
+	var password string
+	var err error
+	if *passwordStdin {
+		password, err = readLine(in)
+	} else {
+		password, err = c.promptPassword(in, "Password: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	user, err := c.client.Login(ctx, *username, password)
+	if err != nil {
+		return err
+	}
+
+	c.config.Token = c.client.Token()
+	c.config.Username = user.Username
+	if err := saveConfig(c.configPath, c.config); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(c.stderr, "Logged in to %s as %s\n", c.config.Server, user.Username)
+	return nil
+}
+
+func (c *cli) logout(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("logout", flag.ContinueOnError)
+	if err := c.parseFlags(flags, args, "", 0); err != nil {
+		return err
+	}
+
+	c.config.Token = ""
+	return saveConfig(c.configPath, c.config)
+}
+
+func (c *cli) prompt(in *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(c.stderr, label)
+	return readLine(in)
+}
+
+// promptPassword turns off terminal echo while the password is typed
+func (c *cli) promptPassword(in *bufio.Reader, label string) (string, error) {
+	fmt.Fprint(c.stderr, label)
+
+	if f, ok := c.stdin.(*os.File); ok && isTerminal(f) {
+		if err := stty(f, "-echo"); err == nil {
+			defer func() {
+				stty(f, "echo")
+				fmt.Fprintln(c.stderr)
+			}()
+		}
+	}
+	return readLine(in)
+}
+
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && line != "") {
+		return "", fmt.Errorf("read input: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func stty(tty *os.File, setting string) error {
+	cmd := exec.Command("stty", setting)
+	cmd.Stdin = tty
+	return cmd.Run()
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"go-todolist/client"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const formHelp = `# Edit the ticket, lines starting with # are ignored.
+# Everything below the --- line is the description.
+# Clear the title to abort.
+`
+
+// ticketForm is the text representation of a ticket edited in $EDITOR
+type ticketForm struct {
+	Title       string
+	Status      string
+	Priority    string
+	Due         string
+	Assignee    string
+	Description string
+}
+
+func formFromTicket(t *client.Ticket) ticketForm {
+	form := ticketForm{
+		Title:       t.Title,
+		Status:      t.Status,
+		Priority:    t.Priority,
+		Description: t.Description,
+	}
+	if t.DueDate != nil {
+		form.Due = t.DueDate.Format(dateLayout)
+	}
+	if t.AssigneeUsername != nil {
+		form.Assignee = *t.AssigneeUsername
+	}
+	return form
+}
+
+func (f ticketForm) String() string {
+	var b strings.Builder
+	b.WriteString(formHelp)
+	fmt.Fprintf(&b, "Title: %s\n", f.Title)
+	fmt.Fprintf(&b, "Status: %s\n", f.Status)
+	fmt.Fprintf(&b, "Priority: %s\n", f.Priority)
+	fmt.Fprintf(&b, "Due: %s\n", f.Due)
+	fmt.Fprintf(&b, "Assignee: %s\n", f.Assignee)
+	b.WriteString("---\n")
+	if f.Description != "" {
+		b.WriteString(f.Description + "\n")
+	}
+	return b.String()
+}
+
+// parseForm reads the text written by ticketForm.String
+func parseForm(text string) (*ticketForm, error) {
+	var form ticketForm
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "---" {
+			break
+		}
+		if strings.HasPrefix(line, "#") || strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid line %q, want Key: value", line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			form.Title = value
+		case "status":
+			form.Status = value
+		case "priority":
+			form.Priority = value
+		case "due":
+			form.Due = value
+		case "assignee":
+			form.Assignee = value
+		default:
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+	}
+
+	var description []string
+	for scanner.Scan() {
+		description = append(description, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	form.Description = strings.TrimSpace(strings.Join(description, "\n"))
+
+	return &form, nil
+}
+
+// normalize validates the enum fields and returns them in their canonical spelling
+func (f ticketForm) normalize() (ticketForm, error) {
+	var err error
+	if f.Status != "" {
+		if f.Status, err = normalizeStatus(f.Status); err != nil {
+			return f, err
+		}
+	}
+	if f.Priority != "" {
+		if f.Priority, err = normalizePriority(f.Priority); err != nil {
+			return f, err
+		}
+	}
+	if _, err := parseDate(f.Due); err != nil {
+		return f, err
+	}
+	return f, nil
+}
+
+// input converts a form for a new ticket
+func (f ticketForm) input(ctx context.Context, users *userDirectory) (client.TicketInput, error) {
+	f, err := f.normalize()
+	if err != nil {
+		return client.TicketInput{}, err
+	}
+
+	input := client.TicketInput{
+		Title:       f.Title,
+		Description: f.Description,
+		Status:      f.Status,
+		Priority:    f.Priority,
+	}
+	input.DueDate, _ = parseDate(f.Due)
+	if f.Assignee != "" {
+		id, err := users.lookup(ctx, f.Assignee)
+		if err != nil {
+			return input, err
+		}
+		input.AssigneeID = &id
+	}
+	return input, nil
+}
+
+// diff returns a merge patch with the fields that changed between f and edited
+func (f ticketForm) diff(ctx context.Context, edited ticketForm, users *userDirectory) (client.TicketPatch, error) {
+	edited, err := edited.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	patch := client.TicketPatch{}
+	if edited.Title != f.Title {
+		patch["title"] = edited.Title
+	}
+	if edited.Description != strings.TrimSpace(f.Description) {
+		patch["description"] = edited.Description
+	}
+	if edited.Status != f.Status {
+		if edited.Status == "" {
+			return nil, errors.New("status cannot be empty")
+		}
+		patch["status"] = edited.Status
+	}
+	if edited.Priority != f.Priority {
+		if edited.Priority == "" {
+			return nil, errors.New("priority cannot be empty")
+		}
+		patch["priority"] = edited.Priority
+	}
+	if edited.Due != f.Due {
+		due, _ := parseDate(edited.Due)
+		if due == nil {
+			patch["due_date"] = nil
+		} else {
+			patch["due_date"] = due
+		}
+	}
+	if edited.Assignee != f.Assignee {
+		if edited.Assignee == "" {
+			patch["assignee_id"] = nil
+		} else {
+			id, err := users.lookup(ctx, edited.Assignee)
+			if err != nil {
+				return nil, err
+			}
+			patch["assignee_id"] = id
+		}
+	}
+	return patch, nil
+}
+
+// editForm opens the form in $VISUAL or $EDITOR and parses the saved result. An
+// empty title aborts.
+func editForm(ctx context.Context, form ticketForm) (*ticketForm, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	file, err := os.CreateTemp("", "todoctl-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(form.String()); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+
+	// the editor may carry arguments, e.g. EDITOR="code --wait"
+	argv := append(strings.Fields(editor), file.Name())
+	cmd := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("run editor %q: %w", editor, err)
+	}
+
+	raw, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, err
+	}
+	edited, err := parseForm(string(raw))
+	if err != nil {
+		return nil, err
+	}
+	if edited.Title == "" {
+		return nil, errors.New("empty title, aborting")
+	}
+	return edited, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-todolist/client"
+	"slices"
+	"time"
+)
+
+func (c *cli) logs(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("logs", flag.ContinueOnError)
+	follow := flags.Bool("f", false, "keep printing new entries as they happen")
+	interval := flags.Duration("interval", 2*time.Second, "poll interval while following")
+	limit := flags.Int("n", 20, "number of recent entries to print first")
+	if err := c.parseFlags(flags, args, "[-f] [-n count] [-interval 2s]", 0); err != nil {
+		return err
+	}
+
+	logs, err := c.client.ActivityLogs(ctx)
+	if err != nil {
+		return err
+	}
+
+	lastID := 0
+	if len(logs) > 0 {
+		lastID = logs[0].ID
+	}
+
+	// the feed is newest first, print the tail oldest first like tail(1)
+	logs = logs[:min(max(*limit, 0), len(logs))]
+	slices.Reverse(logs)
+	if err := printLogs(c.stdout, c.format, logs); err != nil {
+		return err
+	}
+	if !*follow {
+		return nil
+	}
+
+	for log, err := range c.client.FollowActivity(ctx, lastID, *interval) {
+		if err != nil {
+			fmt.Fprintln(c.stderr, "todoctl:", err)
+			continue
+		}
+		if err := printLogs(c.stdout, c.format, []client.ActivityLog{log}); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
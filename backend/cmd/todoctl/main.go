@@ -0,0 +1,183 @@
+// Command todoctl manages tickets from the terminal.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"go-todolist/client"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+)
+
+const usage = `Usage: todoctl [-server url] [-o table|json] <command> [flags] [args]
+
+Commands:
+  login                 log in and store the token in the config file
+  logout                forget the stored token
+  list                  list tickets, filtered by flags
+  show <id>             show one ticket
+  create                create a ticket from flags or in $EDITOR
+  edit <id>             edit a ticket in $EDITOR
+  status <id> <status>  move a ticket to Backlog, Todo, "In Progress" or Done
+  assign <id> <user>    assign a ticket to a username, "-" unassigns
+  logs                  print the activity log, -f keeps following it
+
+Run "todoctl <command> -h" for the flags of a command.
+`
+
+// errUsage reports a malformed command line, the message has already been printed
+var errUsage = errors.New("usage")
+
+// cli holds what every command needs
+type cli struct {
+	stdin      io.Reader
+	stdout     io.Writer
+	stderr     io.Writer
+	config     *Config
+	configPath string
+	format     string
+	client     *client.Client
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	case errors.Is(err, context.Canceled):
+		os.Exit(130)
+	default:
+		fmt.Fprintln(os.Stderr, "todoctl:", err)
+		if client.IsUnauthorized(err) {
+			fmt.Fprintln(os.Stderr, `run "todoctl login" first`)
+		}
+		os.Exit(1)
+	}
+}
+
+// run parses the global flags and dispatches to a command
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("todoctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		flags.PrintDefaults()
+	}
+	configPath := flags.String("config", os.Getenv("TODOCTL_CONFIG"), "config file (default $XDG_CONFIG_HOME/todoctl/config.json)")
+	server := flags.String("server", os.Getenv("TODOCTL_SERVER"), "API base URL, overrides the config file")
+	format := flags.String("o", "table", "output format: table or json")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return errUsage
+	}
+	if *format != "table" && *format != "json" {
+		fmt.Fprintf(stderr, "unknown output format %q\n", *format)
+		return errUsage
+	}
+
+	if *configPath == "" {
+		path, err := defaultConfigPath()
+		if err != nil {
+			return err
+		}
+		*configPath = path
+	}
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *server != "" {
+		cfg.Server = *server
+	}
+	if token := os.Getenv("TODOCTL_TOKEN"); token != "" {
+		cfg.Token = token
+	}
+
+	c := &cli{
+		stdin:      stdin,
+		stdout:     stdout,
+		stderr:     stderr,
+		config:     cfg,
+		configPath: *configPath,
+		format:     *format,
+		client:     client.New(cfg.Server, client.WithToken(cfg.Token), client.WithUserAgent("todoctl")),
+	}
+
+	rest := flags.Args()
+	if len(rest) == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	commands := map[string]func(context.Context, []string) error{
+		"login":  c.login,
+		"logout": c.logout,
+		"list":   c.list,
+		"show":   c.show,
+		"create": c.create,
+		"edit":   c.edit,
+		"status": c.status,
+		"assign": c.assign,
+		"logs":   c.logs,
+	}
+	cmd, ok := commands[rest[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", rest[0])
+		flags.Usage()
+		return errUsage
+	}
+	return cmd(ctx, rest[1:])
+}
+
+// parseFlags parses the flags of a command and checks its positional arguments
+func (c *cli) parseFlags(flags *flag.FlagSet, args []string, synopsis string, nargs int) error {
+	flags.SetOutput(c.stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: todoctl %s %s\n", flags.Name(), synopsis)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if flags.NArg() != nargs {
+		flags.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// normalizeStatus accepts statuses in any case and "progress" for "In Progress"
+func normalizeStatus(s string) (string, error) {
+	for _, status := range []string{client.StatusBacklog, client.StatusTodo, client.StatusInProgress, client.StatusDone} {
+		if strings.EqualFold(s, status) || strings.EqualFold(strings.ReplaceAll(s, "-", " "), status) {
+			return status, nil
+		}
+	}
+	if strings.EqualFold(s, "progress") || strings.EqualFold(s, "inprogress") {
+		return client.StatusInProgress, nil
+	}
+	return "", fmt.Errorf("unknown status %q, want Backlog, Todo, In Progress or Done", s)
+}
+
+// normalizePriority accepts priorities in any case
+func normalizePriority(p string) (string, error) {
+	for _, priority := range []string{client.PriorityLow, client.PriorityMedium, client.PriorityHigh} {
+		if strings.EqualFold(p, priority) {
+			return priority, nil
+		}
+	}
+	return "", fmt.Errorf("unknown priority %q, want Low, Medium or High", p)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"go-todolist/client"
+	"go-todolist/internal/apitest"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type session struct {
+	t      *testing.T
+	server string
+	config string
+}
+
+func newSession(t *testing.T) *session {
+	h := apitest.New(t)
+	srv := httptest.NewServer(h.Handler)
+	t.Cleanup(srv.Close)
+
+	h.Signup("alice", "password-alice")
+	h.Signup("bob", "password-bob")
+
+	return &session{t: t, server: srv.URL, config: filepath.Join(t.TempDir(), "config.json")}
+}
+
+// run executes todoctl and returns its stdout
+func (s *session) run(stdin string, args ...string) string {
+	s.t.Helper()
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-config", s.config, "-server", s.server}, args...)
+	if err := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr); err != nil {
+		s.t.Fatalf("todoctl %s: %v\nstderr: %s", strings.Join(args[4:], " "), err, stderr.String())
+	}
+	return stdout.String()
+}
+
+func TestCommands(t *testing.T) {
+	s := newSession(t)
+
+	s.run("password-alice\n", "login", "-u", "alice", "-password-stdin")
+	info, err := os.Stat(s.config)
+	if err != nil {
+		t.Fatalf("config not written: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("config mode %v, want 0600", info.Mode().Perm())
+	}
+
+	var created client.Ticket
+	out := s.run("", "-o", "json", "create", "-title", "Fix login bug", "-priority", "high", "-due", "2030-05-17", "-assignee", "bob")
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("decode %q: %v", out, err)
+	}
+	if created.Priority != client.PriorityHigh || created.AssigneeUsername == nil || *created.AssigneeUsername != "bob" {
+		t.Fatalf("created %+v", created)
+	}
+	id := "#" + jsonInt(created.ID)
+
+	s.run("", "status", id, "in-progress")
+	s.run("", "assign", id, "me")
+
+	out = s.run("", "list", "-status", "in progress", "-assignee", "me")
+	if !strings.Contains(out, "Fix login bug") || !strings.Contains(out, "2030-05-17") || !strings.HasPrefix(out, "ID") {
+		t.Fatalf("list output:\n%s", out)
+	}
+	if out := s.run("", "list", "-status", "done"); strings.Contains(out, "Fix login bug") {
+		t.Fatalf("status filter ignored:\n%s", out)
+	}
+
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "sed -i -e s/^Priority:.*/Priority:Low/ -e s/^Assignee:.*/Assignee:/ -e $aSteps-to-reproduce")
+	out = s.run("", "edit", id)
+	if !strings.Contains(out, "Priority:  Low") || !strings.Contains(out, "Assignee:  -") || !strings.Contains(out, "Steps-to-reproduce") {
+		t.Fatalf("edit output:\n%s", out)
+	}
+
+	out = s.run("", "logs", "-n", "10")
+	for _, want := range []string{"created ticket: Fix login bug", "changed status to In Progress", "updated description, priority, assignee_id"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("logs missing %q:\n%s", want, out)
+		}
+	}
+
+	s.run("", "logout")
+	var stderr bytes.Buffer
+	err = run(context.Background(), []string{"-config", s.config, "-server", s.server, "list"}, strings.NewReader(""), &bytes.Buffer{}, &stderr)
+	if !client.IsUnauthorized(err) {
+		t.Fatalf("list after logout: got %v", err)
+	}
+}
+
+func TestFormRoundTrip(t *testing.T) {
+	form := ticketForm{
+		Title:       "Write docs",
+		Status:      client.StatusTodo,
+		Priority:    client.PriorityMedium,
+		Due:         "2030-01-02",
+		Assignee:    "bob",
+		Description: "First line\n\nSecond: paragraph\n---\nnot a separator",
+	}
+
+	parsed, err := parseForm(form.String())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if *parsed != form {
+		t.Fatalf("got %+v, want %+v", *parsed, form)
+	}
+
+	if _, err := parseForm("Title: x\nColour: blue\n---\n"); err == nil {
+		t.Fatal("unknown field accepted")
+	}
+}
+
+func jsonInt(v int) string {
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-todolist/client"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+func (c *cli) printJSON(v any) error {
+	enc := json.NewEncoder(c.stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (c *cli) printTickets(tickets []client.Ticket) error {
+	if c.format == "json" {
+		if tickets == nil {
+			tickets = []client.Ticket{}
+		}
+		return c.printJSON(tickets)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tPRIORITY\tDUE\tASSIGNEE\tTITLE")
+	for _, t := range tickets {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", t.ID, t.Status, t.Priority, formatDue(t.DueDate), assignee(t), truncate(t.Title, 60))
+	}
+	return w.Flush()
+}
+
+func (c *cli) printTicket(t *client.Ticket) error {
+	if c.format == "json" {
+		return c.printJSON(t)
+	}
+
+	w := tabwriter.NewWriter(c.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%d\n", t.ID)
+	fmt.Fprintf(w, "Title:\t%s\n", t.Title)
+	fmt.Fprintf(w, "Status:\t%s\n", t.Status)
+	fmt.Fprintf(w, "Priority:\t%s\n", t.Priority)
+	fmt.Fprintf(w, "Due:\t%s\n", formatDue(t.DueDate))
+	fmt.Fprintf(w, "Creator:\t%s\n", t.CreatorUsername)
+	fmt.Fprintf(w, "Assignee:\t%s\n", assignee(*t))
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if t.Description != "" {
+		fmt.Fprintf(c.stdout, "\n%s\n", t.Description)
+	}
+	return nil
+}
+
+// printLogs writes activity entries, JSON output is one object per line so a
+// followed feed can be piped into jq
+func printLogs(w io.Writer, format string, logs []client.ActivityLog) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		for _, log := range logs {
+			if err := enc.Encode(log); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, log := range logs {
+		ticket := "-"
+		if log.TicketID != nil {
+			ticket = "#" + strconv.Itoa(*log.TicketID)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", log.CreatedAt.Local().Format(time.DateTime), log.Username, ticket, log.Action)
+	}
+	return tw.Flush()
+}
+
+func formatDue(due *time.Time) string {
+	if due == nil {
+		return "-"
+	}
+	return due.Format(dateLayout)
+}
+
+func assignee(t client.Ticket) string {
+	if t.AssigneeUsername == nil {
+		return "-"
+	}
+	return *t.AssigneeUsername
+}
+
+func truncate(s string, n int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if len([]rune(s)) <= n {
+		return s
+	}
+	return string([]rune(s)[:n-1]) + "…"
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"go-todolist/client"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func (c *cli) list(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ContinueOnError)
+	status := flags.String("status", "", "comma separated statuses")
+	priority := flags.String("priority", "", "comma separated priorities")
+	assignee := flags.String("assignee", "", `assignee username, "me" for your tickets`)
+	creator := flags.String("creator", "", "creator username")
+	dueWithin := flags.Int("due-within", -1, "only tickets due in the next N days")
+	dueAfter := flags.String("due-after", "", "only tickets due on or after YYYY-MM-DD")
+	dueBefore := flags.String("due-before", "", "only tickets due on or before YYYY-MM-DD")
+	if err := c.parseFlags(flags, args, "[flags]", 0); err != nil {
+		return err
+	}
+
+	filter := &client.TicketFilter{}
+	for _, s := range splitList(*status) {
+		normalized, err := normalizeStatus(s)
+		if err != nil {
+			return err
+		}
+		filter.Statuses = append(filter.Statuses, normalized)
+	}
+	for _, p := range splitList(*priority) {
+		normalized, err := normalizePriority(p)
+		if err != nil {
+			return err
+		}
+		filter.Priorities = append(filter.Priorities, normalized)
+	}
+
+	users := newUserDirectory(c.client)
+	if *assignee == "me" {
+		filter.AssignedToMe = true
+	} else if *assignee != "" {
+		id, err := users.lookup(ctx, *assignee)
+		if err != nil {
+			return err
+		}
+		filter.AssigneeID = &id
+	}
+	if *creator != "" {
+		id, err := users.lookup(ctx, *creator)
+		if err != nil {
+			return err
+		}
+		filter.CreatorID = &id
+	}
+
+	if *dueWithin >= 0 {
+		filter.DueWithinDays = dueWithin
+	}
+	var err error
+	if filter.DueAfter, err = parseDate(*dueAfter); err != nil {
+		return err
+	}
+	if filter.DueBefore, err = parseDate(*dueBefore); err != nil {
+		return err
+	}
+
+	tickets, err := c.client.ListTickets(ctx, filter)
+	if err != nil {
+		return err
+	}
+	return c.printTickets(tickets)
+}
+
+func (c *cli) show(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("show", flag.ContinueOnError)
+	if err := c.parseFlags(flags, args, "<id>", 1); err != nil {
+		return err
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ticket, err := c.client.GetTicket(ctx, id)
+	if err != nil {
+		return err
+	}
+	return c.printTicket(ticket)
+}
+
+func (c *cli) create(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ContinueOnError)
+	title := flags.String("title", "", "title, opens $EDITOR when empty")
+	description := flags.String("description", "", "description")
+	status := flags.String("status", "", "initial status (default Backlog)")
+	priority := flags.String("priority", "", "priority (default Medium)")
+	due := flags.String("due", "", "due date YYYY-MM-DD")
+	assignee := flags.String("assignee", "", "assignee username")
+	if err := c.parseFlags(flags, args, "[flags]", 0); err != nil {
+		return err
+	}
+
+	form := ticketForm{
+		Title:       *title,
+		Description: *description,
+		Status:      *status,
+		Priority:    *priority,
+		Due:         *due,
+		Assignee:    *assignee,
+	}
+	if form.Title == "" {
+		edited, err := editForm(ctx, form)
+		if err != nil {
+			return err
+		}
+		form = *edited
+	}
+
+	input, err := form.input(ctx, newUserDirectory(c.client))
+	if err != nil {
+		return err
+	}
+
+	ticket, err := c.client.CreateTicket(ctx, input)
+	if err != nil {
+		return err
+	}
+	return c.printTicket(ticket)
+}
+
+func (c *cli) edit(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("edit", flag.ContinueOnError)
+	if err := c.parseFlags(flags, args, "<id>", 1); err != nil {
+		return err
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	ticket, err := c.client.GetTicket(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	original := formFromTicket(ticket)
+	edited, err := editForm(ctx, original)
+	if err != nil {
+		return err
+	}
+
+	patch, err := original.diff(ctx, *edited, newUserDirectory(c.client))
+	if err != nil {
+		return err
+	}
+	if len(patch) == 0 {
+		fmt.Fprintln(c.stderr, "No changes")
+		return nil
+	}
+
+	updated, err := c.client.PatchTicket(ctx, id, patch)
+	if err != nil {
+		return err
+	}
+	return c.printTicket(updated)
+}
+
+func (c *cli) status(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("status", flag.ContinueOnError)
+	if err := c.parseFlags(flags, args, "<id> <status>", 2); err != nil {
+		return err
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	status, err := normalizeStatus(flags.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	if err := c.client.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "Moved #%d to %s\n", id, status)
+	return nil
+}
+
+func (c *cli) assign(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("assign", flag.ContinueOnError)
+	if err := c.parseFlags(flags, args, `<id> <username|me|->`, 2); err != nil {
+		return err
+	}
+	id, err := parseID(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	var ticket *client.Ticket
+	switch username := flags.Arg(1); username {
+	case "-":
+		ticket, err = c.client.UnassignTicket(ctx, id)
+	default:
+		var userID int
+		userID, err = c.userID(ctx, username)
+		if err != nil {
+			return err
+		}
+		ticket, err = c.client.AssignTicket(ctx, id, userID)
+	}
+	if err != nil {
+		return err
+	}
+	return c.printTicket(ticket)
+}
+
+// userID resolves a username, "me" is the authenticated user
+func (c *cli) userID(ctx context.Context, username string) (int, error) {
+	if username != "me" {
+		return newUserDirectory(c.client).lookup(ctx, username)
+	}
+
+	me, err := c.client.Me(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return me.ID, nil
+}
+
+// userDirectory resolves usernames to ids, fetching the user list at most once
+type userDirectory struct {
+	client *client.Client
+	ids    map[string]int
+}
+
+func newUserDirectory(c *client.Client) *userDirectory {
+	return &userDirectory{client: c}
+}
+
+func (d *userDirectory) lookup(ctx context.Context, username string) (int, error) {
+	if d.ids == nil {
+		users, err := d.client.Users(ctx)
+		if err != nil {
+			return 0, err
+		}
+		d.ids = make(map[string]int, len(users))
+		for _, u := range users {
+			d.ids[u.Username] = u.ID
+		}
+	}
+
+	id, ok := d.ids[username]
+	if !ok {
+		return 0, fmt.Errorf("unknown user %q", username)
+	}
+	return id, nil
+}
+
+func parseID(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(s, "#"))
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid ticket id %q", s)
+	}
+	return id, nil
+}
+
+// parseDate reads a YYYY-MM-DD date in local time, empty yields nil
+func parseDate(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.ParseInLocation(dateLayout, s, time.Local)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, want YYYY-MM-DD", s)
+	}
+	return &t, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
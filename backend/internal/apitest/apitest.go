@@ -0,0 +1,207 @@
+// Package apitest runs the full HTTP API in-process for end-to-end tests. A Harness
+// wires the real router, services and handlers to injectable repositories and offers
+// helpers to send requests, decode the utils.Response envelope and authenticate.
+package apitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"go-todolist/internal/app"
+	"go-todolist/internal/config"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/health"
+	"go-todolist/internal/repository/memory"
+	"go-todolist/internal/utils"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWTSecret signs the tokens issued while a harness is running
+const JWTSecret = "apitest-secret-that-is-long-enough-for-production"
+
+// Harness serves the API from an in-memory router
+type Harness struct {
+	t        testing.TB
+	Config   *config.Config
+	Repos    app.Repositories
+	Services *app.Services
+	// Checker backs /readyz, it has no database so only draining fails it
+	Checker *health.Checker
+	Handler http.Handler
+}
+
+// MemoryRepositories returns repositories backed by a fresh in-memory store
+func MemoryRepositories() app.Repositories {
+	store := memory.NewStore()
+	return app.Repositories{
+		Users:        memory.NewUserRepository(store),
+		Tickets:      memory.NewTicketRepository(store),
+		ActivityLogs: memory.NewActivityLogRepository(store),
+		SavedFilters: memory.NewSavedFilterRepository(store),
+	}
+}
+
+// New starts a harness on empty in-memory storage
+func New(t testing.TB) *Harness {
+	return NewWithRepositories(t, MemoryRepositories())
+}
+
+// NewWithRepositories starts a harness on the given repositories
+func NewWithRepositories(t testing.TB, repos app.Repositories) *Harness {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	cfg := config.Default()
+	cfg.JWT.Secret = JWTSecret
+	utils.SetJWTSecret(cfg.JWT.Secret)
+
+	services := app.NewServices(repos)
+	checker := health.NewChecker(nil)
+	router := app.NewRouterFromServices(cfg, services, checker)
+
+	return &Harness{
+		t:        t,
+		Config:   cfg,
+		Repos:    repos,
+		Services: services,
+		Checker:  checker,
+		Handler:  router.Setup(),
+	}
+}
+
+// Response is a recorded API response
+type Response struct {
+	t          testing.TB
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Do sends a request with body encoded as JSON, token is sent as a bearer token
+// unless empty
+func (h *Harness) Do(method, path string, body any, token string) *Response {
+	h.t.Helper()
+
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			h.t.Fatalf("encode %s %s body: %v", method, path, err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	rec := httptest.NewRecorder()
+	h.Handler.ServeHTTP(rec, req)
+
+	return &Response{
+		t:          h.t,
+		StatusCode: rec.Code,
+		Header:     rec.Header(),
+		Body:       rec.Body.Bytes(),
+	}
+}
+
+// Envelope decodes the utils.Response wrapper, leaving Data as raw JSON
+func (r *Response) Envelope() utils.Response {
+	r.t.Helper()
+
+	var envelope utils.Response
+	if err := json.Unmarshal(r.Body, &envelope); err != nil {
+		r.t.Fatalf("decode envelope: %v\nbody: %s", err, r.Body)
+	}
+	return envelope
+}
+
+// ExpectStatus fails the test unless the response has the given status code
+func (r *Response) ExpectStatus(want int) *Response {
+	r.t.Helper()
+
+	if r.StatusCode != want {
+		r.t.Fatalf("status %d, want %d\nbody: %s", r.StatusCode, want, r.Body)
+	}
+	return r
+}
+
+// ExpectError fails the test unless the response is an error with the given status
+// and machine-readable code
+func (r *Response) ExpectError(status int, code string) utils.Response {
+	r.t.Helper()
+
+	r.ExpectStatus(status)
+	envelope := r.Envelope()
+	if envelope.Success {
+		r.t.Fatalf("success = true on a %d response", status)
+	}
+	if envelope.Code != code {
+		r.t.Fatalf("error code %q, want %q\nbody: %s", envelope.Code, code, r.Body)
+	}
+	return envelope
+}
+
+// Data decodes the data member of a successful response into v
+func (r *Response) Data(v any) {
+	r.t.Helper()
+
+	var envelope struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(r.Body, &envelope); err != nil {
+		r.t.Fatalf("decode envelope: %v\nbody: %s", err, r.Body)
+	}
+	if !envelope.Success {
+		r.t.Fatalf("response is not successful\nbody: %s", r.Body)
+	}
+	if err := json.Unmarshal(envelope.Data, v); err != nil {
+		r.t.Fatalf("decode data: %v\nbody: %s", err, r.Body)
+	}
+}
+
+// Session is an authenticated user
+type Session struct {
+	Token string      `json:"token"`
+	User  domain.User `json:"user"`
+}
+
+// Signup registers a user and returns its session
+func (h *Harness) Signup(username, password string) Session {
+	h.t.Helper()
+
+	var session Session
+	h.Do(http.MethodPost, "/api/auth/signup", map[string]string{
+		"username": username,
+		"password": password,
+	}, "").ExpectStatus(http.StatusCreated).Data(&session)
+	return session
+}
+
+// Login authenticates an existing user and returns its session
+func (h *Harness) Login(username, password string) Session {
+	h.t.Helper()
+
+	var session Session
+	h.Do(http.MethodPost, "/api/auth/login", map[string]string{
+		"username": username,
+		"password": password,
+	}, "").ExpectStatus(http.StatusOK).Data(&session)
+	return session
+}
+
+// User signs up a user with a throwaway password and returns its session
+func (h *Harness) User(username string) Session {
+	h.t.Helper()
+	return h.Signup(username, "password-"+username)
+}
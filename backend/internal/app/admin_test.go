@@ -0,0 +1,123 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/service"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestAdminCommands(t *testing.T) {
+	h := apitest.New(t)
+	ctx := context.Background()
+	admin := service.NewAdminService(h.Repos.Users, h.Repos.Tickets, h.Services.Auth)
+
+	root, err := admin.CreateUser(ctx, "root", "root-password", domain.RoleAdmin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.Role != domain.RoleAdmin {
+		t.Fatalf("created role %q, want admin", root.Role)
+	}
+	h.Login("root", "root-password")
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		expectCode(t, ignoreUser(admin.CreateUser(ctx, "someone", "short", domain.RoleUser)), "invalid_password")
+		expectCode(t, ignoreUser(admin.CreateUser(ctx, "someone", "long-enough", "owner")), "invalid_role")
+		expectCode(t, ignoreUser(admin.SetRole(ctx, "nobody", domain.RoleAdmin)), "user_not_found")
+	})
+
+	t.Run("ResetPassword", func(t *testing.T) {
+		h.User("carol")
+		if err := admin.ResetPassword(ctx, "carol", "fresh-password"); err != nil {
+			t.Fatal(err)
+		}
+		h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "carol", "password": "password-carol"}, "").
+			ExpectError(http.StatusUnauthorized, "invalid_credentials")
+		h.Login("carol", "fresh-password")
+	})
+
+	t.Run("DisableAndEnable", func(t *testing.T) {
+		dave := h.User("dave")
+		user, err := admin.SetDisabled(ctx, "dave", true)
+		if err != nil || !user.Disabled() {
+			t.Fatalf("disable: %+v, %v", user, err)
+		}
+		h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "dave", "password": "password-dave"}, "").
+			ExpectError(http.StatusForbidden, "account_disabled")
+		// tokens issued before are refused as well
+		h.Do(http.MethodGet, "/api/auth/me", nil, dave.Token).ExpectError(http.StatusForbidden, "account_disabled")
+		h.Do(http.MethodGet, "/api/tickets/", nil, dave.Token).ExpectError(http.StatusForbidden, "account_disabled")
+
+		if _, err := admin.SetDisabled(ctx, "dave", false); err != nil {
+			t.Fatal(err)
+		}
+		h.Login("dave", "password-dave")
+		h.Do(http.MethodGet, "/api/auth/me", nil, dave.Token).ExpectStatus(http.StatusOK)
+	})
+
+	t.Run("Reassign", func(t *testing.T) {
+		erin := h.User("erin")
+		frank := h.User("frank")
+		for _, title := range []string{"One", "Two"} {
+			h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": title, "assignee_id": erin.User.ID}, erin.Token).
+				ExpectStatus(http.StatusCreated)
+		}
+		h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Mine", "assignee_id": frank.User.ID}, frank.Token).
+			ExpectStatus(http.StatusCreated)
+
+		expectCode(t, ignoreCount(admin.ReassignTickets(ctx, "root", "erin", "erin")), "same_user")
+		expectCode(t, ignoreCount(admin.ReassignTickets(ctx, "nobody", "erin", "frank")), "user_not_found")
+
+		n, err := admin.ReassignTickets(ctx, "root", "erin", "frank")
+		if err != nil || n != 2 {
+			t.Fatalf("reassigned %d, %v, want 2", n, err)
+		}
+
+		var tickets []service.TicketResponse
+		h.Do(http.MethodGet, "/api/tickets/?assignee_id="+strconv.Itoa(frank.User.ID), nil, frank.Token).
+			ExpectStatus(http.StatusOK).Data(&tickets)
+		if len(tickets) != 3 {
+			t.Fatalf("frank has %d tickets, want 3", len(tickets))
+		}
+
+		var logs []domain.ActivityLog
+		h.Do(http.MethodGet, "/api/logs/", nil, frank.Token).ExpectStatus(http.StatusOK).Data(&logs)
+		if logs[0].Username != "root" || !strings.HasPrefix(logs[0].Action, "reassigned ticket from erin to frank: ") {
+			t.Fatalf("newest log %s %q", logs[0].Username, logs[0].Action)
+		}
+	})
+
+	t.Run("Stats", func(t *testing.T) {
+		if _, err := admin.SetDisabled(ctx, "carol", true); err != nil {
+			t.Fatal(err)
+		}
+		stats, err := admin.Stats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.Users != 5 || stats.Admins != 1 || stats.DisabledUsers != 1 {
+			t.Errorf("users %d admins %d disabled %d, want 5 1 1", stats.Users, stats.Admins, stats.DisabledUsers)
+		}
+		if stats.Tickets != 3 || stats.TicketsByStatus[domain.StatusBacklog] != 3 || stats.TrashedTickets != 0 {
+			t.Errorf("tickets %d by status %v trash %d", stats.Tickets, stats.TicketsByStatus, stats.TrashedTickets)
+		}
+	})
+}
+
+func expectCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var de *domain.Error
+	if !errors.As(err, &de) || de.Code != code {
+		t.Fatalf("got error %v, want %s", err, code)
+	}
+}
+
+func ignoreUser(_ *domain.User, err error) error { return err }
+
+func ignoreCount(_ int, err error) error { return err }
@@ -0,0 +1,235 @@
+package app_test
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/metrics"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestTicketExport(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+	bob := h.User("bob")
+
+	tickets := []map[string]any{
+		{"title": `Broken "Save as...", again`, "description": "first line\nsecond, line", "status": domain.StatusTodo, "priority": domain.PriorityHigh, "assignee_id": bob.User.ID},
+		{"title": "=1+1", "status": domain.StatusDone},
+		{"title": "Later", "status": domain.StatusBacklog, "assignee_id": bob.User.ID},
+	}
+	for _, ticket := range tickets {
+		h.Do(http.MethodPost, "/api/tickets/", ticket, alice.Token).ExpectStatus(http.StatusCreated)
+	}
+
+	t.Run("CSVWithFiltersAndColumns", func(t *testing.T) {
+		res := h.Do(http.MethodGet, "/api/tickets/export?status=Todo,Backlog&assigned_to_me=false&columns=title,description,assignee_username,status", nil, alice.Token).
+			ExpectStatus(http.StatusOK)
+		if ct := res.Header.Get("Content-Type"); ct != "text/csv; charset=utf-8" {
+			t.Fatalf("content type %q", ct)
+		}
+		if cd := res.Header.Get("Content-Disposition"); !strings.HasPrefix(cd, `attachment; filename="tickets-`) || !strings.HasSuffix(cd, `.csv"`) {
+			t.Fatalf("content disposition %q", cd)
+		}
+
+		records, err := csv.NewReader(strings.NewReader(string(res.Body))).ReadAll()
+		if err != nil {
+			t.Fatalf("invalid CSV: %v\n%s", err, res.Body)
+		}
+		want := [][]string{
+			{"title", "description", "assignee_username", "status"},
+			{"Later", "", "bob", domain.StatusBacklog},
+			{`Broken "Save as...", again`, "first line\nsecond, line", "bob", domain.StatusTodo},
+		}
+		if len(records) != len(want) {
+			t.Fatalf("got %q", records)
+		}
+		for i := range want {
+			if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+				t.Errorf("row %d = %q, want %q", i, records[i], want[i])
+			}
+		}
+	})
+
+	t.Run("CSVNeutralizesFormulas", func(t *testing.T) {
+		res := h.Do(http.MethodGet, "/api/tickets/export?status=Done&columns=title", nil, alice.Token).ExpectStatus(http.StatusOK)
+		if string(res.Body) != "title\n'=1+1\n" {
+			t.Fatalf("got %q", res.Body)
+		}
+	})
+
+	t.Run("NDJSONRespectsRelativeFilters", func(t *testing.T) {
+		res := h.Do(http.MethodGet, "/api/tickets/export?format=ndjson&assigned_to_me=true&columns=id,title,assignee_id,due_date", nil, bob.Token).
+			ExpectStatus(http.StatusOK)
+		if ct := res.Header.Get("Content-Type"); ct != "application/x-ndjson" {
+			t.Fatalf("content type %q", ct)
+		}
+
+		lines := strings.Split(strings.TrimSuffix(string(res.Body), "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("got %q", res.Body)
+		}
+		var row map[string]any
+		if err := json.Unmarshal([]byte(lines[1]), &row); err != nil {
+			t.Fatal(err)
+		}
+		if row["title"] != tickets[0]["title"] || row["assignee_id"] != float64(bob.User.ID) || row["due_date"] != nil || len(row) != 4 {
+			t.Fatalf("got %v", row)
+		}
+		if !strings.HasPrefix(lines[0], `{"id":`) {
+			t.Fatalf("columns out of order: %s", lines[0])
+		}
+	})
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		envelope := h.Do(http.MethodGet, "/api/tickets/export?format=xlsx&columns=title,password&status=Closed", nil, alice.Token).
+			ExpectError(http.StatusBadRequest, "validation_failed")
+		fields := map[string]bool{}
+		for _, e := range envelope.Errors {
+			fields[e.Field] = true
+		}
+		if !fields["format"] || !fields["columns"] || !fields["status"] {
+			t.Fatalf("got field errors %+v", envelope.Errors)
+		}
+	})
+
+	t.Run("RequiresAuth", func(t *testing.T) {
+		h.Do(http.MethodGet, "/api/tickets/export", nil, "").ExpectStatus(http.StatusUnauthorized)
+		h.Do(http.MethodGet, "/api/logs/export", nil, "").ExpectStatus(http.StatusUnauthorized)
+	})
+}
+
+func TestActivityExport(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+	bob := h.User("bob")
+	ctx := context.Background()
+
+	// more history than the 100 entries the activity feed shows
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	item := domain.TicketImport{Ticket: &domain.Ticket{Title: "Busy", CreatorID: alice.User.ID, CreatedAt: start, UpdatedAt: start}}
+	for i := 0; i < 150; i++ {
+		user := alice.User.ID
+		if i%3 == 0 {
+			user = bob.User.ID
+		}
+		item.History = append(item.History, &domain.ActivityLog{UserID: user, Action: "tick " + strconv.Itoa(i), CreatedAt: start.Add(time.Duration(i) * time.Hour)})
+	}
+	if err := h.Repos.Tickets.Import(ctx, []domain.TicketImport{item}); err != nil {
+		t.Fatal(err)
+	}
+
+	res := h.Do(http.MethodGet, "/api/logs/export", nil, alice.Token).ExpectStatus(http.StatusOK)
+	records, err := csv.NewReader(strings.NewReader(string(res.Body))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 151 || strings.Join(records[0], ",") != "id,ticket_id,user_id,username,action,created_at" {
+		t.Fatalf("got %d rows starting with %q", len(records), records[0])
+	}
+	if records[1][4] != "tick 149" || records[1][5] != "2026-01-07T05:00:00Z" {
+		t.Fatalf("newest row %q", records[1])
+	}
+
+	path := "/api/logs/export?format=ndjson&columns=username,action&user_id=" + strconv.Itoa(bob.User.ID) +
+		"&ticket_id=" + strconv.Itoa(item.Ticket.ID) + "&since=2026-01-02&until=2026-01-03"
+	res = h.Do(http.MethodGet, path, nil, alice.Token).ExpectStatus(http.StatusOK)
+	lines := strings.Split(strings.TrimSuffix(string(res.Body), "\n"), "\n")
+	if len(lines) != 8 || lines[0] != `{"username":"bob","action":"tick 45"}` || lines[7] != `{"username":"bob","action":"tick 24"}` {
+		t.Fatalf("got %q", lines)
+	}
+
+	// errors follow the parameter order, not the order of the query string or a map
+	want := []string{"ticket_id", "user_id", "since", "until"}
+	for range 10 {
+		envelope := h.Do(http.MethodGet, "/api/logs/export?until=x&since=yesterday&user_id=me&ticket_id=x", nil, alice.Token).
+			ExpectError(http.StatusBadRequest, "validation_failed")
+		var fields []string
+		for _, e := range envelope.Errors {
+			fields = append(fields, e.Field)
+		}
+		if !slices.Equal(fields, want) {
+			t.Fatalf("fields %v, want %v", fields, want)
+		}
+	}
+}
+
+// failingTickets fails exports after the wrapped repository produced every row
+type failingTickets struct {
+	domain.TicketRepository
+	afterRows bool
+}
+
+func (r failingTickets) StreamByFilter(ctx context.Context, filter domain.TicketFilter, fn func(*domain.Ticket) error) error {
+	if !r.afterRows {
+		return errors.New("connection refused")
+	}
+	if err := r.TicketRepository.StreamByFilter(ctx, filter, fn); err != nil {
+		return err
+	}
+	return errors.New("connection reset")
+}
+
+func TestExportFailures(t *testing.T) {
+	t.Run("BeforeFirstRow", func(t *testing.T) {
+		repos := apitest.MemoryRepositories()
+		repos.Tickets = failingTickets{TicketRepository: repos.Tickets}
+		h := apitest.NewWithRepositories(t, repos)
+
+		res := h.Do(http.MethodGet, "/api/tickets/export", nil, h.User("alice").Token)
+		res.ExpectError(http.StatusInternalServerError, "internal_error")
+		if cd := res.Header.Get("Content-Disposition"); cd != "" {
+			t.Fatalf("error response still offered as a download: %q", cd)
+		}
+		if ct := res.Header.Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+			t.Fatalf("error response labelled %q", ct)
+		}
+	})
+
+	t.Run("MidStream", func(t *testing.T) {
+		repos := apitest.MemoryRepositories()
+		repos.Tickets = failingTickets{TicketRepository: repos.Tickets, afterRows: true}
+		h := apitest.NewWithRepositories(t, repos)
+		alice := h.User("alice")
+
+		// enough rows to push the response past the encoder's buffer
+		for i := 0; i < 100; i++ {
+			h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Ticket " + strconv.Itoa(i), "description": strings.Repeat("x", 100)}, alice.Token).
+				ExpectStatus(http.StatusCreated)
+		}
+
+		aborted := testutil.ToFloat64(metrics.HTTPResponsesAborted.WithLabelValues(http.MethodGet, "/api/tickets/export"))
+		server := httptest.NewServer(h.Handler)
+		defer server.Close()
+
+		req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/tickets/export", nil)
+		req.Header.Set("Authorization", "Bearer "+alice.Token)
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		if res.StatusCode != http.StatusOK {
+			t.Fatalf("status %d", res.StatusCode)
+		}
+		if _, err := io.ReadAll(res.Body); err == nil {
+			t.Fatal("truncated export read as complete")
+		}
+		if got := testutil.ToFloat64(metrics.HTTPResponsesAborted.WithLabelValues(http.MethodGet, "/api/tickets/export")) - aborted; got != 1 {
+			t.Fatalf("counted %v aborted responses, want 1", got)
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/app"
+	"go-todolist/internal/health"
+	"net"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeDatabase answers readiness probes with fixed results
+type fakeDatabase struct {
+	pingErr error
+	pending []string
+}
+
+func (f fakeDatabase) Ping(ctx context.Context) error { return f.pingErr }
+
+func (f fakeDatabase) PendingMigrations(ctx context.Context) ([]string, error) {
+	return f.pending, nil
+}
+
+// readiness decodes the report /readyz returns with either status
+func readiness(t *testing.T, res *apitest.Response) health.Report {
+	t.Helper()
+
+	var envelope struct {
+		Data health.Report `json:"data"`
+	}
+	if err := json.Unmarshal(res.Body, &envelope); err != nil {
+		t.Fatalf("decode: %v\nbody: %s", err, res.Body)
+	}
+	return envelope.Data
+}
+
+func TestHealthEndpoints(t *testing.T) {
+	h := apitest.New(t)
+
+	h.Do(http.MethodGet, "/healthz", nil, "").ExpectStatus(http.StatusOK)
+
+	report := readiness(t, h.Do(http.MethodGet, "/readyz", nil, "").ExpectStatus(http.StatusOK))
+	if report.Status != "ok" || report.Checks["shutdown"] != "ok" {
+		t.Fatalf("got %+v, want ready", report)
+	}
+
+	// draining fails readiness while liveness stays up
+	h.Checker.MarkShuttingDown()
+	h.Do(http.MethodGet, "/healthz", nil, "").ExpectStatus(http.StatusOK)
+	h.Do(http.MethodGet, "/readyz", nil, "").ExpectError(http.StatusServiceUnavailable, "not_ready")
+}
+
+func TestReadinessChecksTheDatabase(t *testing.T) {
+	h := apitest.New(t)
+
+	for _, tc := range []struct {
+		name  string
+		db    fakeDatabase
+		check string
+		want  string
+	}{
+		{"Unreachable", fakeDatabase{pingErr: errors.New("connection refused")}, "database", "unreachable"},
+		{"PendingMigrations", fakeDatabase{pending: []string{"0004_next"}}, "migrations", "pending: 0004_next"},
+		{"UpToDate", fakeDatabase{}, "migrations", "ok"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			router := app.NewRouterFromServices(h.Config, h.Services, health.NewChecker(tc.db))
+			h := *h
+			h.Handler = router.Setup()
+
+			want := http.StatusServiceUnavailable
+			if tc.want == "ok" {
+				want = http.StatusOK
+			}
+			report := readiness(t, h.Do(http.MethodGet, "/readyz", nil, "").ExpectStatus(want))
+			if report.Checks[tc.check] != tc.want {
+				t.Fatalf("%s check %q, want %q (%+v)", tc.check, report.Checks[tc.check], tc.want, report)
+			}
+		})
+	}
+}
+
+// freePort returns a port nothing listens on at the time of the call
+func freePort(t *testing.T) string {
+	t.Helper()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	return strconv.Itoa(l.Addr().(*net.TCPAddr).Port)
+}
+
+func TestServerDrainsBeforeShutdown(t *testing.T) {
+	h := apitest.New(t)
+
+	cfg := h.Config.Server
+	cfg.Port = freePort(t)
+	cfg.MetricsPort = ""
+	cfg.DrainDelay = 300 * time.Millisecond
+
+	ctx, stop := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- app.NewServer(h.Handler.(*gin.Engine), cfg, h.Checker).Run(ctx)
+	}()
+
+	readyz := "http://127.0.0.1:" + cfg.Port + "/readyz"
+	status := func() int {
+		res, err := http.Get(readyz)
+		if err != nil {
+			return 0
+		}
+		res.Body.Close()
+		return res.StatusCode
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for status() != http.StatusOK {
+		if time.Now().After(deadline) {
+			t.Fatal("server never became ready")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// the server keeps answering during the drain delay, with readiness failed
+	stop()
+	time.Sleep(50 * time.Millisecond)
+	if got := status(); got != http.StatusServiceUnavailable {
+		t.Fatalf("readyz while draining = %d, want 503", got)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("run: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestServerReturnsListenErrors(t *testing.T) {
+	h := apitest.New(t)
+
+	busy, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	cfg := h.Config.Server
+	cfg.Port = strconv.Itoa(busy.Addr().(*net.TCPAddr).Port)
+	cfg.MetricsPort = ""
+
+	done := make(chan error, 1)
+	go func() {
+		done <- app.NewServer(h.Handler.(*gin.Engine), cfg, h.Checker).Run(context.Background())
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("run returned nil with the port in use")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("run kept going with the port in use")
+	}
+}
@@ -0,0 +1,98 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/middleware"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// captureLogs sends the default logger to a buffer for the rest of the test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+
+	var buf bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewJSONHandler(&buf, nil)))
+	t.Cleanup(func() { slog.SetDefault(previous) })
+	return &buf
+}
+
+// lostActivity fails every activity log write
+type lostActivity struct {
+	domain.ActivityLogRepository
+}
+
+func (lostActivity) Create(context.Context, *domain.ActivityLog) error {
+	return errors.New("disk full")
+}
+
+func TestFailedActivityLogIsLogged(t *testing.T) {
+	repos := apitest.MemoryRepositories()
+	repos.ActivityLogs = lostActivity{repos.ActivityLogs}
+	h := apitest.NewWithRepositories(t, repos)
+	alice := h.User("alice")
+	logs := captureLogs(t)
+
+	// the ticket is saved, so the request still succeeds
+	res := h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Draft"}, alice.Token).
+		ExpectStatus(http.StatusCreated)
+
+	var lost string
+	for line := range strings.Lines(logs.String()) {
+		if strings.Contains(line, `"msg":"failed to record activity"`) {
+			lost = line
+		}
+	}
+	if lost == "" {
+		t.Fatalf("lost activity not logged:\n%s", logs)
+	}
+	requestID := res.Header.Get(middleware.RequestIDHeader)
+	if !strings.Contains(lost, `"request_id":"`+requestID+`"`) || !strings.Contains(lost, "disk full") {
+		t.Fatalf("log line lacks request ID %q or cause: %s", requestID, lost)
+	}
+}
+
+func TestRequestIDIsReusedOrGenerated(t *testing.T) {
+	h := apitest.New(t)
+
+	send := func(requestID string) string {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+		if requestID != "" {
+			req.Header.Set(middleware.RequestIDHeader, requestID)
+		}
+		rec := httptest.NewRecorder()
+		h.Handler.ServeHTTP(rec, req)
+		return rec.Header().Get(middleware.RequestIDHeader)
+	}
+
+	// an upstream ID is echoed back and tags the access log line
+	logs := captureLogs(t)
+	if got := send("edge-7f3a-42"); got != "edge-7f3a-42" {
+		t.Fatalf("echoed %q, want the incoming ID", got)
+	}
+	if !strings.Contains(logs.String(), `"request_id":"edge-7f3a-42"`) {
+		t.Fatalf("access log lacks the incoming ID:\n%s", logs)
+	}
+
+	generated := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	for _, incoming := range []string{"", "has spaces", strings.Repeat("x", 129)} {
+		got := send(incoming)
+		if !generated.MatchString(got) {
+			t.Errorf("incoming %q: got %q, want a generated ID", incoming, got)
+		}
+	}
+	if first, second := send(""), send(""); first == second {
+		t.Fatalf("generated the same ID twice: %q", first)
+	}
+}
@@ -0,0 +1,68 @@
+package app_test
+
+import (
+	"context"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/metrics"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetricsMiddlewareLabels(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+
+	requests := func(method, route, status string) float64 {
+		return testutil.ToFloat64(metrics.HTTPRequests.WithLabelValues(method, route, status))
+	}
+	found := requests(http.MethodGet, "/api/tickets/:id", "404")
+	unmatched := requests(http.MethodGet, "unmatched", "404")
+
+	h.Do(http.MethodGet, ticketPath(4242), nil, alice.Token).ExpectStatus(http.StatusNotFound)
+	h.Do(http.MethodGet, ticketPath(4243), nil, alice.Token).ExpectStatus(http.StatusNotFound)
+	h.Do(http.MethodGet, "/no/such/page", nil, alice.Token).ExpectStatus(http.StatusNotFound)
+
+	if got := requests(http.MethodGet, "/api/tickets/:id", "404") - found; got != 2 {
+		t.Fatalf("route template counted %v requests, want 2", got)
+	}
+	if got := requests(http.MethodGet, "unmatched", "404") - unmatched; got != 1 {
+		t.Fatalf("unmatched path counted %v requests, want 1", got)
+	}
+
+	// metrics are served on their own port, not through the API
+	h.Do(http.MethodGet, "/metrics", nil, "").ExpectStatus(http.StatusNotFound)
+}
+
+func TestLoginAttemptsCountEveryFailure(t *testing.T) {
+	h := apitest.New(t)
+	h.User("alice")
+	bob := h.User("bob")
+	disabledAt := time.Now()
+	if err := h.Repos.Users.SetDisabled(context.Background(), bob.User.ID, &disabledAt); err != nil {
+		t.Fatal(err)
+	}
+
+	attempts := func(result string) float64 {
+		return testutil.ToFloat64(metrics.LoginAttempts.WithLabelValues(result))
+	}
+	failures, successes := attempts("failure"), attempts("success")
+
+	h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "alice", "password": "wrong-password"}, "").
+		ExpectStatus(http.StatusUnauthorized)
+	h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "alice"}, "").
+		ExpectStatus(http.StatusBadRequest)
+	if res := h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "bob", "password": "password-bob"}, ""); res.StatusCode == http.StatusOK {
+		t.Fatal("disabled account logged in")
+	}
+	h.Login("alice", "password-alice")
+
+	if got := attempts("failure") - failures; got != 3 {
+		t.Fatalf("counted %v failures, want 3", got)
+	}
+	if got := attempts("success") - successes; got != 1 {
+		t.Fatalf("counted %v successes, want 1", got)
+	}
+}
@@ -0,0 +1,95 @@
+package app_test
+
+import (
+	"encoding/json"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/openapi"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var pathParam = regexp.MustCompile(`:([A-Za-z_]+)`)
+
+// specPath converts a gin route like /api/tickets/:id to /api/tickets/{id}
+func specPath(route string) string {
+	return pathParam.ReplaceAllString(route, "{$1}")
+}
+
+func TestEveryRouteIsDocumented(t *testing.T) {
+	h := apitest.New(t)
+	spec, err := openapi.Parse()
+	if err != nil {
+		t.Fatalf("parse openapi.json: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for method := range item {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for _, route := range h.Handler.(*gin.Engine).Routes() {
+		key := route.Method + " " + specPath(route.Path)
+		if !documented[key] {
+			t.Errorf("route %s %s is not documented in openapi.json", route.Method, route.Path)
+		}
+		delete(documented, key)
+	}
+
+	for key := range documented {
+		method, _, _ := strings.Cut(key, " ")
+		switch method {
+		case "GET", "PUT", "POST", "PATCH", "DELETE", "HEAD", "OPTIONS":
+			t.Errorf("openapi.json documents %s but no such route is registered", key)
+		}
+	}
+}
+
+func TestOpenAPIDocumentIsServed(t *testing.T) {
+	h := apitest.New(t)
+
+	res := h.Do(http.MethodGet, "/api/openapi.json", nil, "").ExpectStatus(http.StatusOK)
+	var doc struct {
+		OpenAPI    string                                `json:"openapi"`
+		Components map[string]map[string]json.RawMessage `json:"components"`
+	}
+	if err := json.Unmarshal(res.Body, &doc); err != nil {
+		t.Fatalf("decode document: %v", err)
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Fatalf("openapi version %q", doc.OpenAPI)
+	}
+
+	// every $ref must point at a defined component
+	refs := regexp.MustCompile(`"\$ref": "#/components/(\w+)/(\w+)"`).FindAllStringSubmatch(string(res.Body), -1)
+	if len(refs) == 0 {
+		t.Fatal("no references found")
+	}
+	for _, ref := range refs {
+		if _, ok := doc.Components[ref[1]][ref[2]]; !ok {
+			t.Errorf("dangling reference to %s/%s", ref[1], ref[2])
+		}
+	}
+
+	page := h.Do(http.MethodGet, "/api/docs", nil, "").ExpectStatus(http.StatusOK)
+	if !strings.Contains(string(page.Body), "/api/openapi.json") {
+		t.Fatal("docs page does not load the document")
+	}
+	// every script and stylesheet comes from the API itself
+	if external := regexp.MustCompile(`(src|href)="(https?:)?//`).FindString(string(page.Body)); external != "" {
+		t.Fatalf("docs page loads a third-party resource: %s", external)
+	}
+	if !strings.Contains(string(page.Body), `src="/api/docs/redoc.standalone.js"`) {
+		t.Fatal("docs page does not load the embedded Redoc bundle")
+	}
+
+	bundle := h.Do(http.MethodGet, "/api/docs/redoc.standalone.js", nil, "").ExpectStatus(http.StatusOK)
+	if len(bundle.Body) == 0 || !strings.HasPrefix(bundle.Header.Get("Content-Type"), "text/javascript") {
+		t.Fatalf("Redoc bundle served as %q with %d bytes", bundle.Header.Get("Content-Type"), len(bundle.Body))
+	}
+}
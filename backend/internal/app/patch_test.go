@@ -0,0 +1,77 @@
+package app_test
+
+import (
+	"context"
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/service"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// racingTickets changes a ticket's status right before each patch is written, like a
+// concurrent request would
+type racingTickets struct {
+	domain.TicketRepository
+}
+
+func (r racingTickets) Patch(ctx context.Context, id int, changes domain.TicketChanges, editorID int) error {
+	if err := r.TicketRepository.UpdateStatus(ctx, id, domain.StatusDone, domain.AnyEditor); err != nil {
+		return err
+	}
+	return r.TicketRepository.Patch(ctx, id, changes, editorID)
+}
+
+func TestPatchTicket(t *testing.T) {
+	repos := apitest.MemoryRepositories()
+	repos.Tickets = racingTickets{repos.Tickets}
+	h := apitest.NewWithRepositories(t, repos)
+	alice := h.User("alice")
+
+	var created service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Draft", "description": "Details"}, alice.Token).
+		ExpectStatus(http.StatusCreated).Data(&created)
+
+	t.Run("KeepsConcurrentChanges", func(t *testing.T) {
+		var patched service.TicketResponse
+		h.Do(http.MethodPatch, ticketPath(created.ID), map[string]any{"title": "Final", "description": nil}, alice.Token).
+			ExpectStatus(http.StatusOK).Data(&patched)
+		if patched.Title != "Final" || patched.Description != "" {
+			t.Fatalf("patch not applied: %+v", patched)
+		}
+		if patched.Status != domain.StatusDone {
+			t.Fatalf("concurrent status change lost: %+v", patched)
+		}
+	})
+
+	t.Run("UnknownField", func(t *testing.T) {
+		envelope := h.Do(http.MethodPatch, ticketPath(created.ID), map[string]any{"title": "Final", "colour": "red"}, alice.Token).
+			ExpectError(http.StatusBadRequest, "validation_failed")
+		if len(envelope.Errors) != 1 || envelope.Errors[0].Field != "colour" || envelope.Errors[0].Code != "unknown_field" {
+			t.Fatalf("got field errors %+v", envelope.Errors)
+		}
+	})
+
+	t.Run("TitleLengthInCharacters", func(t *testing.T) {
+		// 255 characters but 765 bytes, the same limit creation applies
+		title := strings.Repeat("日", 255)
+		var patched service.TicketResponse
+		h.Do(http.MethodPatch, ticketPath(created.ID), map[string]any{"title": title}, alice.Token).
+			ExpectStatus(http.StatusOK).Data(&patched)
+		if patched.Title != title {
+			t.Fatalf("title stored as %q", patched.Title)
+		}
+
+		envelope := h.Do(http.MethodPatch, ticketPath(created.ID), map[string]any{"title": title + "日"}, alice.Token).
+			ExpectError(http.StatusBadRequest, "validation_failed")
+		if len(envelope.Errors) != 1 || envelope.Errors[0].Field != "title" || envelope.Errors[0].Code != "too_long" {
+			t.Fatalf("got field errors %+v", envelope.Errors)
+		}
+	})
+
+	t.Run("MissingTicket", func(t *testing.T) {
+		h.Do(http.MethodPatch, ticketPath(4242), map[string]any{"title": "Ghost"}, alice.Token).
+			ExpectError(http.StatusNotFound, "ticket_not_found")
+	})
+}
@@ -1,52 +1,105 @@
 package app
 
 import (
+	"go-todolist/internal/config"
 	"go-todolist/internal/handler"
 	"go-todolist/internal/middleware"
+	"go-todolist/internal/openapi"
+	"go-todolist/internal/utils"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Router struct {
+	config             *config.Config
+	users              middleware.UserLookup
 	authHanler         *handler.AuthHandler
 	ticketHandler      *handler.TicketHandler
 	activityLogHandler *handler.ActivityLogHandler
+	searchHandler      *handler.SearchHandler
+	savedFilterHandler *handler.SavedFilterHandler
+	healthHandler      *handler.HealthHandler
 }
 
 func NewRouter(
+	cfg *config.Config,
+	users middleware.UserLookup,
 	authHanler *handler.AuthHandler,
 	ticketHandler *handler.TicketHandler,
 	activityLogHandler *handler.ActivityLogHandler,
+	searchHandler *handler.SearchHandler,
+	savedFilterHandler *handler.SavedFilterHandler,
+	healthHandler *handler.HealthHandler,
 ) *Router {
 	return &Router{
+		config:             cfg,
+		users:              users,
 		authHanler:         authHanler,
 		ticketHandler:      ticketHandler,
 		activityLogHandler: activityLogHandler,
+		searchHandler:      searchHandler,
+		savedFilterHandler: savedFilterHandler,
+		healthHandler:      healthHandler,
 	}
 }
 
 func (r *Router) Setup() *gin.Engine {
-	router := gin.Default()
+	router := gin.New()
+
+	// Tag requests with an ID, log, count and trace them, then recover from panics
+	// inside the others so a failed request is still logged, counted and traced
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.LoggerMiddleware())
+	router.Use(middleware.MetricsMiddleware())
+	router.Use(middleware.TracingMiddleware(r.config.Tracing.ServiceName)...)
+	router.Use(middleware.RecoveryMiddleware())
+
+	// Register custom request validation rules
+	utils.RegisterValidators()
 
 	// Apply CORS middleware
-	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.CORSMiddleware(r.config.CORS.AllowedOrigins))
+
+	// Render errors attached by handlers
+	router.Use(middleware.ErrorHandler())
+
+	// Bound every request with a deadline that reaches the database, exports stream
+	// whole tables and get a longer one. They also lift the server write timeout
+	// themselves, any other streaming route must do both.
+	router.Use(middleware.TimeoutMiddleware(r.config.Server.RequestTimeout, map[string]time.Duration{
+		"/api/tickets/export": r.config.Server.ExportTimeout,
+		"/api/logs/export":    r.config.Server.ExportTimeout,
+	}))
+
+	// Authenticated routes check the bearer token and the account behind it
+	requireAuth := middleware.AuthMiddleware(r.users)
+
+	// Liveness and readiness probes
+	router.GET("/healthz", r.healthHandler.Live)
+	router.GET("/readyz", r.healthHandler.Ready)
 
 	// API routes
 	api := router.Group("/api")
 	{
-		api.GET("/users", middleware.AuthMiddleware(), r.authHanler.GetUsers)
+		// API description and reference page
+		api.GET("/openapi.json", openapi.Handler())
+		api.GET("/docs", openapi.DocsHandler())
+		api.GET("/docs/redoc.standalone.js", openapi.RedocHandler())
+
+		api.GET("/users", requireAuth, r.authHanler.GetUsers)
 
 		// Public routes - Authentication
 		auth := api.Group("/auth")
 		{
 			auth.POST("/login", r.authHanler.Login)
 			auth.POST("/signup", r.authHanler.Signup)
-			auth.GET("/me", middleware.AuthMiddleware(), r.authHanler.Me)
+			auth.GET("/me", requireAuth, r.authHanler.Me)
 		}
 
 		// Profile routes
 		profile := api.Group("/profile")
-		profile.Use(middleware.AuthMiddleware())
+		profile.Use(requireAuth)
 		{
 			profile.PUT("/", r.authHanler.UpdateProfile)
 			profile.PUT("/password", r.authHanler.UpdatePassword)
@@ -54,21 +107,42 @@ func (r *Router) Setup() *gin.Engine {
 
 		// Private routes - Tickets
 		tickets := api.Group("/tickets")
-		tickets.Use(middleware.AuthMiddleware())
+		tickets.Use(requireAuth)
 		{
 			tickets.POST("/", r.ticketHandler.Create)
 			tickets.GET("/", r.ticketHandler.GetAll)
+			tickets.GET("/trash", r.ticketHandler.GetTrash)
+			tickets.GET("/export", r.ticketHandler.Export)
 			tickets.GET("/:id", r.ticketHandler.GetByID)
 			tickets.PUT("/:id", r.ticketHandler.Update)
+			tickets.PATCH("/:id", r.ticketHandler.Patch)
 			tickets.DELETE("/:id", r.ticketHandler.Delete)
 			tickets.PATCH("/:id/status", r.ticketHandler.UpdateStatus)
+			tickets.POST("/:id/restore", r.ticketHandler.Restore)
+			tickets.POST("/bulk", r.ticketHandler.BulkUpdate)
 		}
 
 		// Activity Logs
 		logs := api.Group("/logs")
-		logs.Use(middleware.AuthMiddleware())
+		logs.Use(requireAuth)
 		{
 			logs.GET("/", r.activityLogHandler.GetAll)
+			logs.GET("/export", r.activityLogHandler.Export)
+		}
+
+		// Search
+		api.GET("/search", requireAuth, r.searchHandler.Search)
+
+		// Saved filters
+		filters := api.Group("/filters")
+		filters.Use(requireAuth)
+		{
+			filters.POST("/", r.savedFilterHandler.Create)
+			filters.GET("/", r.savedFilterHandler.GetAll)
+			filters.GET("/:id", r.savedFilterHandler.GetByID)
+			filters.PUT("/:id", r.savedFilterHandler.Update)
+			filters.DELETE("/:id", r.savedFilterHandler.Delete)
+			filters.GET("/:id/tickets", r.savedFilterHandler.Execute)
 		}
 	}
 
@@ -0,0 +1,458 @@
+package app_test
+
+import (
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/service"
+	"go-todolist/internal/utils"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func ticketPath(id int) string {
+	return "/api/tickets/" + strconv.Itoa(id)
+}
+
+func TestAuthFlow(t *testing.T) {
+	h := apitest.New(t)
+
+	signup := h.Signup("alice", "s3cret-pass")
+	if signup.Token == "" || signup.User.ID == 0 || signup.User.Username != "alice" {
+		t.Fatalf("got session %+v", signup)
+	}
+	if body := h.Do(http.MethodGet, "/api/users", nil, signup.Token).ExpectStatus(http.StatusOK).Body; strings.Contains(string(body), "$2a$") {
+		t.Fatalf("password hash leaked: %s", body)
+	}
+
+	login := h.Login("alice", "s3cret-pass")
+	if login.User.ID != signup.User.ID {
+		t.Fatalf("login user %d, signup user %d", login.User.ID, signup.User.ID)
+	}
+
+	var me domain.User
+	h.Do(http.MethodGet, "/api/auth/me", nil, login.Token).ExpectStatus(http.StatusOK).Data(&me)
+	if me.Username != "alice" {
+		t.Fatalf("me = %q, want alice", me.Username)
+	}
+
+	t.Run("DuplicateUsername", func(t *testing.T) {
+		h.Do(http.MethodPost, "/api/auth/signup", map[string]string{"username": "alice", "password": "another-pass"}, "").
+			ExpectError(http.StatusConflict, "username_taken")
+	})
+
+	t.Run("WrongPassword", func(t *testing.T) {
+		h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "alice", "password": "wrong-pass"}, "").
+			ExpectError(http.StatusUnauthorized, "invalid_credentials")
+	})
+
+	t.Run("UnknownUser", func(t *testing.T) {
+		h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "nobody", "password": "whatever"}, "").
+			ExpectError(http.StatusUnauthorized, "invalid_credentials")
+	})
+
+	t.Run("InvalidSignup", func(t *testing.T) {
+		envelope := h.Do(http.MethodPost, "/api/auth/signup", map[string]string{"username": "al", "password": "123"}, "").
+			ExpectError(http.StatusBadRequest, "validation_failed")
+		if len(envelope.Errors) != 2 {
+			t.Fatalf("got field errors %+v, want username and password", envelope.Errors)
+		}
+	})
+
+	t.Run("MissingAndBadTokens", func(t *testing.T) {
+		h.Do(http.MethodGet, "/api/auth/me", nil, "").ExpectStatus(http.StatusUnauthorized)
+		h.Do(http.MethodGet, "/api/auth/me", nil, "not-a-jwt").ExpectStatus(http.StatusUnauthorized)
+		h.Do(http.MethodGet, "/api/tickets/", nil, "").ExpectStatus(http.StatusUnauthorized)
+	})
+
+	t.Run("ChangePassword", func(t *testing.T) {
+		h.Do(http.MethodPut, "/api/profile/password", map[string]string{"password": "brand-new-pass"}, login.Token).
+			ExpectStatus(http.StatusOK)
+		h.Do(http.MethodPost, "/api/auth/login", map[string]string{"username": "alice", "password": "s3cret-pass"}, "").
+			ExpectStatus(http.StatusUnauthorized)
+		h.Login("alice", "brand-new-pass")
+	})
+}
+
+func TestTicketLifecycle(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+	bob := h.User("bob")
+
+	var created service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{
+		"title":       "Fix login bug",
+		"description": "Users get logged out",
+		"priority":    domain.PriorityHigh,
+		"assignee_id": bob.User.ID,
+	}, alice.Token).ExpectStatus(http.StatusCreated).Data(&created)
+
+	if created.ID == 0 || created.Status != domain.StatusBacklog || created.CreatorUsername != "alice" {
+		t.Fatalf("got %+v", created)
+	}
+	if created.AssigneeUsername == nil || *created.AssigneeUsername != "bob" {
+		t.Fatalf("assignee %v, want bob", created.AssigneeUsername)
+	}
+
+	var fetched service.TicketResponse
+	h.Do(http.MethodGet, ticketPath(created.ID), nil, bob.Token).ExpectStatus(http.StatusOK).Data(&fetched)
+	if fetched.Title != "Fix login bug" || fetched.Priority != domain.PriorityHigh {
+		t.Fatalf("got %+v", fetched)
+	}
+
+	var updated service.TicketResponse
+	h.Do(http.MethodPut, ticketPath(created.ID), map[string]any{
+		"title":    "Fix login bug on Safari",
+		"status":   domain.StatusTodo,
+		"priority": domain.PriorityHigh,
+	}, alice.Token).ExpectStatus(http.StatusOK).Data(&updated)
+	if updated.Title != "Fix login bug on Safari" || updated.Status != domain.StatusTodo {
+		t.Fatalf("got %+v", updated)
+	}
+
+	var listed []service.TicketResponse
+	h.Do(http.MethodGet, "/api/tickets/?status=Todo", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&listed)
+	if len(listed) != 1 || listed[0].ID != created.ID {
+		t.Fatalf("got %+v", listed)
+	}
+	h.Do(http.MethodGet, "/api/tickets/?status=Done", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&listed)
+	if len(listed) != 0 {
+		t.Fatalf("got %+v, want no done tickets", listed)
+	}
+
+	h.Do(http.MethodDelete, ticketPath(created.ID), nil, alice.Token).ExpectStatus(http.StatusOK)
+	h.Do(http.MethodGet, ticketPath(created.ID), nil, alice.Token).ExpectError(http.StatusNotFound, "ticket_not_found")
+
+	var trash []service.TicketResponse
+	h.Do(http.MethodGet, "/api/tickets/trash", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&trash)
+	if len(trash) != 1 || trash[0].ID != created.ID || trash[0].DeletedAt == nil {
+		t.Fatalf("got trash %+v", trash)
+	}
+
+	var restored service.TicketResponse
+	h.Do(http.MethodPost, ticketPath(created.ID)+"/restore", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&restored)
+	if restored.ID != created.ID || restored.DeletedAt != nil {
+		t.Fatalf("got %+v", restored)
+	}
+	h.Do(http.MethodPost, ticketPath(created.ID)+"/restore", nil, alice.Token).ExpectError(http.StatusNotFound, "ticket_not_in_trash")
+
+	t.Run("Validation", func(t *testing.T) {
+		envelope := h.Do(http.MethodPost, "/api/tickets/", map[string]any{"status": "Someday"}, alice.Token).
+			ExpectError(http.StatusBadRequest, "validation_failed")
+		if len(envelope.Errors) != 2 {
+			t.Fatalf("got field errors %+v, want title and status", envelope.Errors)
+		}
+
+		h.Do(http.MethodGet, "/api/tickets/?priority=Urgent", nil, alice.Token).ExpectError(http.StatusBadRequest, "validation_failed")
+		h.Do(http.MethodGet, ticketPath(9999), nil, alice.Token).ExpectError(http.StatusNotFound, "ticket_not_found")
+	})
+
+	t.Run("UnknownAssignee", func(t *testing.T) {
+		h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Orphan", "assignee_id": 9999}, alice.Token).
+			ExpectError(http.StatusBadRequest, "invalid_reference")
+	})
+}
+
+func TestValidationErrorEnvelope(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+
+	var ticket service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Valid"}, alice.Token).
+		ExpectStatus(http.StatusCreated).Data(&ticket)
+
+	statuses := "must be one of: " + strings.Join(domain.TicketStatuses, ", ")
+	for _, tc := range []struct {
+		name   string
+		method string
+		path   string
+		body   map[string]any
+		want   []utils.FieldError
+	}{
+		{"BadStatus", http.MethodPost, "/api/tickets/", map[string]any{"title": "Valid", "status": "Someday"},
+			[]utils.FieldError{{Field: "status", Code: "invalid_choice", Message: statuses}}},
+		{"TitleTooLong", http.MethodPost, "/api/tickets/", map[string]any{"title": strings.Repeat("a", 256)},
+			[]utils.FieldError{{Field: "title", Code: "too_long", Message: "must be at most 255 characters"}}},
+		{"BothOnReplace", http.MethodPut, ticketPath(ticket.ID), map[string]any{"title": strings.Repeat("a", 256), "status": "Someday"},
+			[]utils.FieldError{
+				{Field: "title", Code: "too_long", Message: "must be at most 255 characters"},
+				{Field: "status", Code: "invalid_choice", Message: statuses},
+			}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := h.Do(tc.method, tc.path, tc.body, alice.Token)
+			envelope := res.ExpectError(http.StatusBadRequest, "validation_failed")
+			if !slices.Equal(envelope.Errors, tc.want) {
+				t.Fatalf("got field errors %+v, want %+v", envelope.Errors, tc.want)
+			}
+			// clients read these member names, not the Go field names
+			if !strings.Contains(string(res.Body), `{"field":"`+tc.want[0].Field+`","code":"`+tc.want[0].Code+`","message":"`) {
+				t.Fatalf("unexpected envelope layout: %s", res.Body)
+			}
+		})
+	}
+}
+
+func TestTicketFilterErrorOrder(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+
+	want := []string{"status", "assignee_id", "creator_id", "due_within_days", "due_after", "due_before", "assigned_to_me"}
+	path := "/api/tickets/?due_before=x&due_after=x&due_within_days=x&creator_id=x&assignee_id=x&assigned_to_me=x&status=Someday"
+	// map iteration order differs between runs, a few requests catch a regression
+	for range 10 {
+		envelope := h.Do(http.MethodGet, path, nil, alice.Token).ExpectError(http.StatusBadRequest, "validation_failed")
+		var fields []string
+		for _, e := range envelope.Errors {
+			fields = append(fields, e.Field)
+		}
+		if !slices.Equal(fields, want) {
+			t.Fatalf("fields %v, want %v", fields, want)
+		}
+	}
+}
+
+func TestStatusChanges(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+
+	var ticket service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Ship it"}, alice.Token).
+		ExpectStatus(http.StatusCreated).Data(&ticket)
+
+	for _, status := range []string{domain.StatusTodo, domain.StatusInProgress, domain.StatusDone} {
+		h.Do(http.MethodPatch, ticketPath(ticket.ID)+"/status", map[string]string{"status": status}, alice.Token).
+			ExpectStatus(http.StatusOK)
+
+		var fetched service.TicketResponse
+		h.Do(http.MethodGet, ticketPath(ticket.ID), nil, alice.Token).ExpectStatus(http.StatusOK).Data(&fetched)
+		if fetched.Status != status {
+			t.Fatalf("status %q, want %q", fetched.Status, status)
+		}
+	}
+
+	h.Do(http.MethodPatch, ticketPath(ticket.ID)+"/status", map[string]string{"status": "Archived"}, alice.Token).
+		ExpectError(http.StatusBadRequest, "validation_failed")
+	h.Do(http.MethodPatch, ticketPath(9999)+"/status", map[string]string{"status": domain.StatusDone}, alice.Token).
+		ExpectError(http.StatusNotFound, "ticket_not_found")
+
+	var patched service.TicketResponse
+	h.Do(http.MethodPatch, ticketPath(ticket.ID), map[string]any{"status": domain.StatusTodo, "due_date": nil}, alice.Token).
+		ExpectStatus(http.StatusOK).Data(&patched)
+	if patched.Status != domain.StatusTodo || patched.Title != "Ship it" {
+		t.Fatalf("got %+v", patched)
+	}
+
+	var bulk service.BulkTicketResponse
+	h.Do(http.MethodPost, "/api/tickets/bulk", map[string]any{
+		"ticket_ids": []int{ticket.ID},
+		"changes":    map[string]string{"status": domain.StatusDone},
+	}, alice.Token).ExpectStatus(http.StatusOK).Data(&bulk)
+
+	var done []service.TicketResponse
+	h.Do(http.MethodGet, "/api/tickets/?status=Done", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&done)
+	if len(done) != 1 {
+		t.Fatalf("bulk update left %d done tickets, want 1", len(done))
+	}
+}
+
+func TestBulkUpdateNeedsCreatorOrAssignee(t *testing.T) {
+	h := apitest.New(t)
+	alice, bob := h.User("alice"), h.User("bob")
+
+	var own, unassigned service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Bob's"}, bob.Token).
+		ExpectStatus(http.StatusCreated).Data(&own)
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Alice's"}, alice.Token).
+		ExpectStatus(http.StatusCreated).Data(&unassigned)
+
+	var bulk service.BulkTicketResponse
+	h.Do(http.MethodPost, "/api/tickets/bulk", map[string]any{
+		"ticket_ids": []int{own.ID, unassigned.ID},
+		"changes":    map[string]string{"status": domain.StatusDone},
+	}, bob.Token).ExpectStatus(http.StatusOK).Data(&bulk)
+
+	if bulk.Updated != 1 || bulk.Failed != 1 {
+		t.Fatalf("got %+v", bulk)
+	}
+	for _, result := range bulk.Results {
+		if result.TicketID == unassigned.ID && (result.Success || result.Error == "") {
+			t.Fatalf("an unassigned ticket was changed by a user who did not create it: %+v", result)
+		}
+	}
+
+	var fetched service.TicketResponse
+	h.Do(http.MethodGet, ticketPath(unassigned.ID), nil, alice.Token).ExpectStatus(http.StatusOK).Data(&fetched)
+	if fetched.Status == domain.StatusDone {
+		t.Fatal("refused ticket was updated")
+	}
+}
+
+func TestTicketWritesNeedCreatorOrAssignee(t *testing.T) {
+	h := apitest.New(t)
+	alice, bob := h.User("alice"), h.User("bob")
+
+	var ticket service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Alice's"}, alice.Token).
+		ExpectStatus(http.StatusCreated).Data(&ticket)
+
+	for _, req := range []struct {
+		method, path string
+		body         any
+	}{
+		{http.MethodPut, ticketPath(ticket.ID), map[string]any{"title": "Bob's", "status": domain.StatusDone, "priority": domain.PriorityLow}},
+		{http.MethodPatch, ticketPath(ticket.ID), map[string]any{"title": "Bob's"}},
+		{http.MethodPatch, ticketPath(ticket.ID) + "/status", map[string]string{"status": domain.StatusDone}},
+		{http.MethodDelete, ticketPath(ticket.ID), nil},
+	} {
+		h.Do(req.method, req.path, req.body, bob.Token).ExpectError(http.StatusForbidden, "ticket_forbidden")
+	}
+
+	h.Do(http.MethodDelete, ticketPath(ticket.ID), nil, alice.Token).ExpectStatus(http.StatusOK)
+	h.Do(http.MethodPost, ticketPath(ticket.ID)+"/restore", nil, bob.Token).ExpectError(http.StatusForbidden, "ticket_forbidden")
+	h.Do(http.MethodPost, ticketPath(ticket.ID)+"/restore", nil, alice.Token).ExpectStatus(http.StatusOK)
+
+	// assigning the ticket lets bob work on it
+	h.Do(http.MethodPatch, ticketPath(ticket.ID), map[string]any{"assignee_id": bob.User.ID}, alice.Token).ExpectStatus(http.StatusOK)
+	h.Do(http.MethodPatch, ticketPath(ticket.ID)+"/status", map[string]string{"status": domain.StatusDone}, bob.Token).ExpectStatus(http.StatusOK)
+}
+
+func TestReplaceKeepsOmittedStatusAndPriority(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+	bob := h.User("bob")
+
+	var ticket service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{
+		"title":       "Keep me",
+		"status":      domain.StatusInProgress,
+		"priority":    domain.PriorityHigh,
+		"assignee_id": bob.User.ID,
+	}, alice.Token).ExpectStatus(http.StatusCreated).Data(&ticket)
+
+	var updated service.TicketResponse
+	h.Do(http.MethodPut, ticketPath(ticket.ID), map[string]any{"title": "Kept", "assignee_id": bob.User.ID}, bob.Token).
+		ExpectStatus(http.StatusOK).Data(&updated)
+	if updated.Title != "Kept" || updated.Status != domain.StatusInProgress || updated.Priority != domain.PriorityHigh {
+		t.Fatalf("got %+v, want status and priority kept", updated)
+	}
+
+	// the update is credited to bob who made it, not to alice who created the ticket
+	var logs []domain.ActivityLog
+	h.Do(http.MethodGet, "/api/logs/", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&logs)
+	if len(logs) == 0 || logs[0].Action != "updated ticket: Kept" || logs[0].Username != "bob" {
+		t.Fatalf("latest log %+v, want bob's update", logs)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+
+	create := func(title, description string) service.TicketResponse {
+		var ticket service.TicketResponse
+		h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": title, "description": description}, alice.Token).
+			ExpectStatus(http.StatusCreated).Data(&ticket)
+		return ticket
+	}
+	login := create("Fix login bug", "Users get logged out on Safari")
+	create("Write docs", "Explain the setup")
+	trashed := create("Login redesign", "")
+	h.Do(http.MethodDelete, ticketPath(trashed.ID), nil, alice.Token).ExpectStatus(http.StatusOK)
+
+	// "logi" is a prefix of login, the trashed ticket matches too but stays hidden
+	var results []service.SearchResultResponse
+	h.Do(http.MethodGet, "/api/search?q=logi", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&results)
+	if len(results) != 1 || results[0].ID != login.ID {
+		t.Fatalf("got %+v, want only ticket %d", results, login.ID)
+	}
+	if results[0].TitleHighlight != "Fix <mark>login</mark> bug" {
+		t.Fatalf("title highlight %q, want the matched word marked", results[0].TitleHighlight)
+	}
+
+	h.Do(http.MethodGet, "/api/search?q=safari+logged", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&results)
+	if len(results) != 1 || !strings.Contains(results[0].Snippet, "<mark>logged</mark>") || !strings.Contains(results[0].Snippet, "<mark>Safari</mark>") {
+		t.Fatalf("got %+v, want the description terms marked in the snippet", results)
+	}
+
+	h.Do(http.MethodGet, "/api/search?q=redesign", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&results)
+	if len(results) != 0 {
+		t.Fatalf("trashed ticket found: %+v", results)
+	}
+}
+
+func TestActivityLogging(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+	bob := h.User("bob")
+
+	var ticket service.TicketResponse
+	h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Audit me", "assignee_id": bob.User.ID}, alice.Token).
+		ExpectStatus(http.StatusCreated).Data(&ticket)
+	h.Do(http.MethodPatch, ticketPath(ticket.ID)+"/status", map[string]string{"status": domain.StatusInProgress}, bob.Token).
+		ExpectStatus(http.StatusOK)
+	h.Do(http.MethodDelete, ticketPath(ticket.ID), nil, bob.Token).ExpectStatus(http.StatusOK)
+	h.Do(http.MethodPost, ticketPath(ticket.ID)+"/restore", nil, alice.Token).ExpectStatus(http.StatusOK)
+
+	var logs []domain.ActivityLog
+	h.Do(http.MethodGet, "/api/logs/", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&logs)
+
+	want := []struct{ user, action string }{
+		{"alice", "restored ticket: Audit me"},
+		{"bob", "moved ticket to trash: Audit me"},
+		{"bob", "changed status to In Progress"},
+		{"alice", "created ticket: Audit me"},
+	}
+	if len(logs) != len(want) {
+		t.Fatalf("got %d logs %+v, want %d", len(logs), logs, len(want))
+	}
+	for i, w := range want {
+		if logs[i].Username != w.user || logs[i].Action != w.action {
+			t.Errorf("log %d = %s %q, want %s %q", i, logs[i].Username, logs[i].Action, w.user, w.action)
+		}
+		if logs[i].TicketID == nil || *logs[i].TicketID != ticket.ID {
+			t.Errorf("log %d ticket id %v, want %d", i, logs[i].TicketID, ticket.ID)
+		}
+	}
+
+	h.Do(http.MethodGet, "/api/logs/", nil, "").ExpectStatus(http.StatusUnauthorized)
+}
+
+func TestActivityLogPaging(t *testing.T) {
+	h := apitest.New(t)
+	alice := h.User("alice")
+
+	for i := 0; i < 105; i++ {
+		h.Do(http.MethodPost, "/api/tickets/", map[string]any{"title": "Burst " + strconv.Itoa(i)}, alice.Token).
+			ExpectStatus(http.StatusCreated)
+	}
+
+	var latest []domain.ActivityLog
+	h.Do(http.MethodGet, "/api/logs/", nil, alice.Token).ExpectStatus(http.StatusOK).Data(&latest)
+	if len(latest) != 100 || latest[0].Action != "created ticket: Burst 104" {
+		t.Fatalf("got %d logs starting with %+v, want the latest 100", len(latest), latest[0])
+	}
+
+	// paging from the start reaches every entry, oldest first, including the five the
+	// plain listing leaves out
+	var seen []domain.ActivityLog
+	for afterID := 0; ; {
+		var page []domain.ActivityLog
+		h.Do(http.MethodGet, "/api/logs/?limit=40&after_id="+strconv.Itoa(afterID), nil, alice.Token).
+			ExpectStatus(http.StatusOK).Data(&page)
+		seen = append(seen, page...)
+		if len(page) < 40 {
+			break
+		}
+		afterID = page[len(page)-1].ID
+	}
+	if len(seen) != 105 || seen[0].Action != "created ticket: Burst 0" || seen[104].Action != "created ticket: Burst 104" {
+		t.Fatalf("paged %d logs, want all 105 oldest first", len(seen))
+	}
+
+	h.Do(http.MethodGet, "/api/logs/?after_id=-1", nil, alice.Token).ExpectError(http.StatusBadRequest, "validation_failed")
+	h.Do(http.MethodGet, "/api/logs/?after_id=0&limit=lots", nil, alice.Token).ExpectError(http.StatusBadRequest, "validation_failed")
+	h.Do(http.MethodGet, "/api/logs/?limit=10", nil, alice.Token).ExpectError(http.StatusBadRequest, "validation_failed")
+}
@@ -3,9 +3,12 @@ package app
 import (
 	"context"
 	"fmt"
-	"log"
+	"go-todolist/internal/config"
+	"go-todolist/internal/health"
+	"go-todolist/internal/metrics"
+	"log/slog"
+	"net"
 	"net/http"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -14,46 +17,126 @@ import (
 )
 
 type Server struct {
-	router *gin.Engine
-	port   string
+	router  *gin.Engine
+	config  config.ServerConfig
+	checker *health.Checker
 }
 
-func NewServer(router *gin.Engine, port string) *Server {
+func NewServer(router *gin.Engine, cfg config.ServerConfig, checker *health.Checker) *Server {
 	return &Server{
-		router: router,
-		port:   port,
+		router:  router,
+		config:  cfg,
+		checker: checker,
 	}
 }
 
+// Start serves until SIGINT or SIGTERM, then drains and shuts down
 func (s *Server) Start() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	return s.Run(ctx)
+}
+
+// Run serves until ctx is done, then fails readiness for the drain delay and shuts
+// down gracefully. A listener that fails, e.g. on a port already in use, stops both
+// servers and its error is returned so the caller's deferred cleanup still runs.
+func (s *Server) Run(ctx context.Context) error {
+	// every request context derives from baseCtx, cancelling it aborts in-flight queries
+	baseCtx, cancelRequests := context.WithCancel(context.Background())
+	defer cancelRequests()
+
+	cfg := s.config
+	if cfg.TLSEnabled() && (cfg.TLSCertFile == "" || cfg.TLSKeyFile == "") {
+		return fmt.Errorf("TLS needs both a certificate and a key file")
+	}
+
+	var handler http.Handler = s.router
+	if cfg.MaxBodyBytes > 0 {
+		handler = http.MaxBytesHandler(handler, cfg.MaxBodyBytes)
+	}
+
 	srv := &http.Server{
-		Addr:    fmt.Sprintf(":%s", s.port),
-		Handler: s.router,
+		Addr:              fmt.Sprintf(":%s", cfg.Port),
+		Handler:           handler,
+		ReadTimeout:       cfg.ReadTimeout,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+		BaseContext: func(net.Listener) context.Context {
+			return baseCtx
+		},
 	}
 
+	// listeners report failures here instead of exiting the process
+	failed := make(chan error, 2)
+
 	// Start server in a gorountine
 	go func() {
-		log.Printf("Server starting on port %s", s.port)
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server failed to start: %v", err)
+		slog.Info("server starting", "port", cfg.Port, "tls", cfg.TLSEnabled())
+
+		var err error
+		if cfg.TLSEnabled() {
+			err = srv.ListenAndServeTLS(cfg.TLSCertFile, cfg.TLSKeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			failed <- fmt.Errorf("server failed: %w", err)
 		}
 	}()
 
-	// wait for interrupt signal to gracefully shutdown the server
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	// Serve metrics on their own port, kept off the public listener
+	var metricsSrv *http.Server
+	if cfg.MetricsPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		metricsSrv = &http.Server{
+			Addr:              fmt.Sprintf(":%s", cfg.MetricsPort),
+			Handler:           mux,
+			ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		}
 
-	log.Println("Shutting down server...")
+		go func() {
+			slog.Info("metrics server starting", "port", cfg.MetricsPort)
+			if err := metricsSrv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				failed <- fmt.Errorf("metrics server failed: %w", err)
+			}
+		}()
+	}
 
-	// give the server 5 seconds to finish ongoing requests
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// wait for the signal to gracefully shutdown the server
+	select {
+	case <-ctx.Done():
+	case err := <-failed:
+		srv.Close()
+		if metricsSrv != nil {
+			metricsSrv.Close()
+		}
+		return err
+	}
+
+	// fail readiness first and keep serving while load balancers notice
+	slog.Info("shutting down server", "drain_delay", cfg.DrainDelay.String())
+	s.checker.MarkShuttingDown()
+	time.Sleep(cfg.DrainDelay)
+
+	// give ongoing requests the grace period to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 	defer cancel()
 
-	if err := srv.Shutdown(ctx); err != nil {
+	if metricsSrv != nil {
+		// keep metrics scrapable while draining, then stop them with the API
+		defer metricsSrv.Close()
+	}
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// requests still running after the grace period get their queries cancelled
+		cancelRequests()
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
-	log.Println("Server exited")
+	slog.Info("server exited")
 	return nil
 }
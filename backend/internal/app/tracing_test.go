@@ -0,0 +1,62 @@
+package app_test
+
+import (
+	"go-todolist/internal/apitest"
+	"go-todolist/internal/tracing"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(tracing.NewProvider(recorder, "test"))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	h := apitest.New(t)
+	alice := h.User("alice")
+	recorder.Reset()
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/tickets/999", nil)
+	req.Header.Set("Authorization", "Bearer "+alice.Token)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	h.Handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status %d", rec.Code)
+	}
+	if got := rec.Header().Get("traceparent"); !strings.HasPrefix(got, "00-"+traceID+"-") {
+		t.Fatalf("response traceparent %q does not continue the trace", got)
+	}
+
+	// the auth middleware loads the account, then the handler looks up the ticket
+	spans := recorder.Ended()
+	if len(spans) != 3 || spans[0].Name() != "AuthService.GetUserByID" {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	service, server := spans[1], spans[2]
+	if server.Name() != "GET /api/tickets/:id" || server.Parent().SpanID().String() != "00f067aa0ba902b7" || server.SpanContext().TraceID().String() != traceID {
+		t.Fatalf("server span %q, parent %s", server.Name(), server.Parent().SpanID())
+	}
+	if service.Name() != "TicketService.FindByID" || service.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Fatalf("service span %q, parent %s", service.Name(), service.Parent().SpanID())
+	}
+	if service.Status().Code != codes.Error {
+		t.Fatalf("service failure not recorded: %+v", service.Status())
+	}
+	var requestID bool
+	for _, kv := range server.Attributes() {
+		requestID = requestID || kv.Key == "request.id" && kv.Value.AsString() == rec.Header().Get("X-Request-ID")
+	}
+	if !requestID {
+		t.Fatalf("server span lacks the request ID: %v", server.Attributes())
+	}
+}
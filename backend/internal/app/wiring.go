@@ -0,0 +1,54 @@
+package app
+
+import (
+	"go-todolist/internal/config"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/handler"
+	"go-todolist/internal/health"
+	"go-todolist/internal/service"
+)
+
+// Repositories are the storage implementations the API runs on, any backend
+// (PostgreSQL, SQLite, memory) can be plugged in
+type Repositories struct {
+	Users        domain.UserRepository
+	Tickets      domain.TicketRepository
+	ActivityLogs domain.ActivityLogRepository
+	SavedFilters domain.SavedFilterRepository
+}
+
+// Services holds the business services built on top of a set of repositories
+type Services struct {
+	Auth        *service.AuthService
+	Tickets     *service.TicketService
+	ActivityLog *service.ActivityLogService
+	Search      *service.SearchService
+	SavedFilter *service.SavedFilterService
+}
+
+// NewServices wires every service to its repositories
+func NewServices(repos Repositories) *Services {
+	ticketService := service.NewTicketService(repos.Tickets, repos.ActivityLogs)
+
+	return &Services{
+		Auth:        service.NewAuthService(repos.Users),
+		Tickets:     ticketService,
+		ActivityLog: service.NewActivityLogService(repos.ActivityLogs),
+		Search:      service.NewSearchService(repos.Tickets),
+		SavedFilter: service.NewSavedFilterService(repos.SavedFilters, ticketService),
+	}
+}
+
+// NewRouterFromServices creates the handlers for services and the router serving them
+func NewRouterFromServices(cfg *config.Config, services *Services, checker *health.Checker) *Router {
+	return NewRouter(
+		cfg,
+		services.Auth,
+		handler.NewAuthHandler(services.Auth),
+		handler.NewTicketHandler(services.Tickets),
+		handler.NewActivityLogHandler(services.ActivityLog),
+		handler.NewSearchHandler(services.Search),
+		handler.NewSavedFilterHandler(services.SavedFilter),
+		handler.NewHealthHandler(checker),
+	)
+}
@@ -3,67 +3,266 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+)
+
 type Config struct {
-	Database DatabaseConfig
-	Server   ServerConfig
-	JWT      JWTConfig
-	RawDSN   string // if provided via DB_URL or DATABASE_URL
+	// Env is development or production, production turns on the strict secret checks
+	Env string `yaml:"env"`
+	// Storage selects the repository backend: postgres, sqlite (needs the sqlite build
+	// tag) or memory, which keeps everything in process and loses it on restart
+	Storage  string         `yaml:"storage"`
+	Database DatabaseConfig `yaml:"database"`
+	Server   ServerConfig   `yaml:"server"`
+	CORS     CORSConfig     `yaml:"cors"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Trash    TrashConfig    `yaml:"trash"`
+	Log      LogConfig      `yaml:"log"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	RawDSN   string         `yaml:"database_url"` // if provided via DB_URL or DATABASE_URL
+
+	// envErrs collects environment variables that did not parse, Validate reports them
+	envErrs []error
 }
 
 type DatabaseConfig struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"name"`
+	// AutoMigrate applies pending migrations on startup
+	AutoMigrate bool `yaml:"auto_migrate"`
+
+	// pool tuning, zero keeps the value set in DATABASE_URL (pool_max_conns,
+	// pool_min_conns, pool_max_conn_lifetime, pool_max_conn_idle_time) or else the
+	// pgx default
+	MaxConns        int32         `yaml:"max_conns"`
+	MinConns        int32         `yaml:"min_conns"`
+	MaxConnLifetime time.Duration `yaml:"max_conn_lifetime"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
+	// StatementCacheMode is a pgx query exec mode: cache_statement, cache_describe,
+	// describe_exec, exec or simple_protocol (the last two work behind PgBouncer).
+	// Empty keeps default_query_exec_mode from DATABASE_URL or pgx's cache_statement.
+	StatementCacheMode string `yaml:"statement_cache_mode"`
+	// SQLitePath is the database file used when Storage is sqlite
+	SQLitePath string `yaml:"sqlite_path"`
+	// ConnectMaxWait is how long startup keeps retrying while the database is
+	// unreachable, zero tries once
+	ConnectMaxWait time.Duration `yaml:"connect_max_wait"`
 }
 
 type ServerConfig struct {
-	Port string
+	Port string `yaml:"port"`
+	// RequestTimeout bounds the handling of a single request including its queries,
+	// zero disables the deadline
+	RequestTimeout time.Duration `yaml:"request_timeout"`
+	// ExportTimeout replaces RequestTimeout and WriteTimeout on the export endpoints,
+	// which stream whole tables
+	ExportTimeout time.Duration `yaml:"export_timeout"`
+	// DrainDelay is how long /readyz fails before shutdown starts, giving load
+	// balancers time to stop sending traffic
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// ShutdownTimeout is how long in-flight requests get to finish once shutdown starts
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	// WriteTimeout cuts off any response still being written after it. Only the
+	// export endpoints lift it, so a new streaming endpoint needs the same override
+	// (see streamExport) or it is dropped after 30s by default.
+	WriteTimeout   time.Duration `yaml:"write_timeout"`
+	IdleTimeout    time.Duration `yaml:"idle_timeout"`
+	MaxHeaderBytes int           `yaml:"max_header_bytes"`
+	// MaxBodyBytes caps request bodies, zero means unlimited
+	MaxBodyBytes int64 `yaml:"max_body_bytes"`
+
+	// TLSCertFile and TLSKeyFile enable HTTPS when both are set
+	TLSCertFile string `yaml:"tls_cert_file"`
+	TLSKeyFile  string `yaml:"tls_key_file"`
+
+	// MetricsPort serves /metrics on its own listener so it is not reachable through
+	// the public API port, empty disables it
+	MetricsPort string `yaml:"metrics_port"`
+}
+
+// TLSEnabled reports whether the server should serve HTTPS
+func (s ServerConfig) TLSEnabled() bool {
+	return s.TLSCertFile != "" || s.TLSKeyFile != ""
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
 }
 
 type JWTConfig struct {
-	Secret string
+	Secret string `yaml:"secret"`
 }
 
-// load loads configuration from environment variables
-func Load() (*Config, error) {
-	// load .env file
-	if err := godotenv.Load(); err != nil {
-		// its okay if .env doesn't exist in production
-		fmt.Println("Warning: .env file not found, using environenment variables")
-	}
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // json or text
+}
 
-	rawDSN := strings.TrimSpace(os.Getenv("DATABASE_URL"))
-	if rawDSN == "" {
-		rawDSN = strings.TrimSpace(os.Getenv("DB_URL"))
-	}
+type TracingConfig struct {
+	Exporter     string `yaml:"exporter"`      // none, stdout or otlp
+	OTLPEndpoint string `yaml:"otlp_endpoint"` // collector base URL, /v1/traces is appended
+	ServiceName  string `yaml:"service_name"`
+}
+
+type TrashConfig struct {
+	// Retention is how long a deleted ticket stays in the trash before it is purged
+	Retention time.Duration `yaml:"retention"`
+}
 
-	config := &Config{
-		RawDSN: rawDSN,
+// Default returns the configuration used when neither a file nor the environment
+// sets a value
+func Default() *Config {
+	return &Config{
+		Env:     EnvDevelopment,
+		Storage: StoragePostgres,
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "127.0.0.1"),
-			Port:     getEnv("DB_PORT", "5432"),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "postgres"),
-			DBName:   getEnv("DB_NAME", "db_todolist"),
+			Host:        "127.0.0.1",
+			Port:        "5432",
+			User:        "postgres",
+			Password:    defaultDBPassword,
+			DBName:      "db_todolist",
+			AutoMigrate: true,
+
+			ConnectMaxWait: 30 * time.Second,
+			SQLitePath:     "todolist.db",
 		},
 		Server: ServerConfig{
-			Port: getEnv("PORT", "8000"),
+			Port:              "8000",
+			RequestTimeout:    15 * time.Second,
+			ExportTimeout:     5 * time.Minute,
+			DrainDelay:        5 * time.Second,
+			ShutdownTimeout:   5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			MaxBodyBytes:      1 << 20,
+			MetricsPort:       "9090",
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{
+				"http://localhost:3000",
+				"http://localhost:3001",
+				"http://localhost:5173",
+			},
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "secret"),
+			Secret: defaultJWTSecret,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "http://localhost:4318",
+			ServiceName:  "go-todolist",
+		},
+		Trash: TrashConfig{
+			Retention: 30 * 24 * time.Hour,
 		},
 	}
+}
+
+// Load builds the configuration in layers: defaults, then the YAML or TOML file at
+// path (skipped when path is empty), then environment variables. The result is not
+// validated, call Validate before starting the server.
+func Load(path string) (*Config, error) {
+	// load .env file
+	if err := godotenv.Load(); err != nil {
+		// its okay if .env doesn't exist in production
+		fmt.Fprintln(os.Stderr, "Warning: .env file not found, using environenment variables")
+	}
+
+	config := Default()
+	if path != "" {
+		if err := loadFile(path, config); err != nil {
+			return nil, err
+		}
+	}
+	config.applyEnv()
 
 	return config, nil
 }
 
+// applyEnv overrides every setting whose environment variable is set
+func (c *Config) applyEnv() {
+	c.Env = getEnv("APP_ENV", c.Env)
+	c.Storage = getEnv("STORAGE", c.Storage)
+
+	if rawDSN := strings.TrimSpace(os.Getenv("DATABASE_URL")); rawDSN != "" {
+		c.RawDSN = rawDSN
+	} else if rawDSN := strings.TrimSpace(os.Getenv("DB_URL")); rawDSN != "" {
+		c.RawDSN = rawDSN
+	}
+
+	c.Database.Host = getEnv("DB_HOST", c.Database.Host)
+	c.Database.Port = getEnv("DB_PORT", c.Database.Port)
+	c.Database.User = getEnv("DB_USER", c.Database.User)
+	c.Database.Password = getEnv("DB_PASSWORD", c.Database.Password)
+	c.Database.DBName = getEnv("DB_NAME", c.Database.DBName)
+	c.Database.AutoMigrate = c.getEnvBool("DB_AUTO_MIGRATE", c.Database.AutoMigrate)
+	c.Database.MaxConns = int32(c.getEnvInt("DB_MAX_CONNS", int(c.Database.MaxConns)))
+	c.Database.MinConns = int32(c.getEnvInt("DB_MIN_CONNS", int(c.Database.MinConns)))
+	c.Database.MaxConnLifetime = c.getEnvDuration("DB_MAX_CONN_LIFETIME", c.Database.MaxConnLifetime)
+	c.Database.MaxConnIdleTime = c.getEnvDuration("DB_MAX_CONN_IDLE_TIME", c.Database.MaxConnIdleTime)
+	c.Database.StatementCacheMode = getEnv("DB_STATEMENT_CACHE_MODE", c.Database.StatementCacheMode)
+	c.Database.ConnectMaxWait = c.getEnvDuration("DB_CONNECT_MAX_WAIT", c.Database.ConnectMaxWait)
+	c.Database.SQLitePath = getEnv("SQLITE_PATH", c.Database.SQLitePath)
+
+	c.Server.Port = getEnv("PORT", c.Server.Port)
+	c.Server.RequestTimeout = c.getEnvDuration("REQUEST_TIMEOUT", c.Server.RequestTimeout)
+	c.Server.ExportTimeout = c.getEnvDuration("EXPORT_TIMEOUT", c.Server.ExportTimeout)
+	c.Server.DrainDelay = c.getEnvDuration("SHUTDOWN_DRAIN_DELAY", c.Server.DrainDelay)
+	c.Server.ShutdownTimeout = c.getEnvDuration("SHUTDOWN_TIMEOUT", c.Server.ShutdownTimeout)
+	c.Server.ReadTimeout = c.getEnvDuration("HTTP_READ_TIMEOUT", c.Server.ReadTimeout)
+	c.Server.ReadHeaderTimeout = c.getEnvDuration("HTTP_READ_HEADER_TIMEOUT", c.Server.ReadHeaderTimeout)
+	c.Server.WriteTimeout = c.getEnvDuration("HTTP_WRITE_TIMEOUT", c.Server.WriteTimeout)
+	c.Server.IdleTimeout = c.getEnvDuration("HTTP_IDLE_TIMEOUT", c.Server.IdleTimeout)
+	c.Server.MaxHeaderBytes = c.getEnvInt("HTTP_MAX_HEADER_BYTES", c.Server.MaxHeaderBytes)
+	c.Server.MaxBodyBytes = int64(c.getEnvInt("HTTP_MAX_BODY_BYTES", int(c.Server.MaxBodyBytes)))
+	c.Server.TLSCertFile = getEnv("TLS_CERT_FILE", c.Server.TLSCertFile)
+	c.Server.TLSKeyFile = getEnv("TLS_KEY_FILE", c.Server.TLSKeyFile)
+	c.Server.MetricsPort = getEnv("METRICS_PORT", c.Server.MetricsPort)
+
+	c.CORS.AllowedOrigins = getEnvList("CORS_ALLOWED_ORIGINS", c.CORS.AllowedOrigins)
+
+	c.JWT.Secret = getEnv("JWT_SECRET", c.JWT.Secret)
+
+	c.Log.Level = getEnv("LOG_LEVEL", c.Log.Level)
+	c.Log.Format = getEnv("LOG_FORMAT", c.Log.Format)
+
+	c.Tracing.Exporter = getEnv("TRACING_EXPORTER", c.Tracing.Exporter)
+	c.Tracing.OTLPEndpoint = getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", c.Tracing.OTLPEndpoint)
+	c.Tracing.ServiceName = getEnv("OTEL_SERVICE_NAME", c.Tracing.ServiceName)
+
+	// any value is applied, zero, negative and unparsable ones included, so Validate
+	// reports it instead of the default silently staying in place
+	if getEnv("TRASH_RETENTION_DAYS", "") != "" {
+		c.Trash.Retention = time.Duration(c.getEnvInt("TRASH_RETENTION_DAYS", 0)) * 24 * time.Hour
+	}
+}
+
 func (c *Config) GetDSN() string {
 	if c.RawDSN != "" {
 		return c.RawDSN
@@ -86,3 +285,68 @@ func getEnv(key, defaultValue string) string {
 
 	return defaultValue
 }
+
+// getEnvInt keeps defaultValue when the variable does not parse and records the error
+// for Validate, so a typo stops startup instead of running with the default.
+// getEnvDuration and getEnvBool do the same.
+func (c *Config) getEnvInt(key string, defaultValue int) int {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		c.envErrs = append(c.envErrs, fmt.Errorf("%s must be an integer, got %q", key, value))
+		return defaultValue
+	}
+
+	return parsed
+}
+
+func (c *Config) getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		c.envErrs = append(c.envErrs, fmt.Errorf("%s must be a duration such as 30s or 5m, got %q", key, value))
+		return defaultValue
+	}
+
+	return parsed
+}
+
+func (c *Config) getEnvBool(key string, defaultValue bool) bool {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		c.envErrs = append(c.envErrs, fmt.Errorf("%s must be true or false, got %q", key, value))
+		return defaultValue
+	}
+
+	return parsed
+}
+
+// getEnvList reads a comma separated list, empty entries are dropped
+func getEnvList(key string, defaultValue []string) []string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+
+	return list
+}
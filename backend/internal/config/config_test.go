@@ -0,0 +1,160 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// loadWith writes content to a config file named name and loads it with env set,
+// variables mapped to "" are treated as unset
+func loadWith(t *testing.T, name, content string, env map[string]string) *Config {
+	t.Helper()
+
+	for _, key := range []string{"PORT", "REQUEST_TIMEOUT", "HTTP_WRITE_TIMEOUT", "DB_MAX_CONNS", "CORS_ALLOWED_ORIGINS", "TRASH_RETENTION_DAYS", "LOG_LEVEL", "DB_AUTO_MIGRATE"} {
+		t.Setenv(key, "")
+	}
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	const yamlFile = `
+server:
+  port: "9000"
+  request_timeout: 20s
+  write_timeout: 1m30s
+database:
+  max_conns: 25
+log:
+  level: debug
+trash:
+  retention: 72h
+`
+	const tomlFile = `
+[server]
+port = "9000"
+request_timeout = "20s"
+write_timeout = "1m30s"
+
+[database]
+max_conns = 25
+
+[log]
+level = "debug"
+
+[trash]
+retention = "72h"
+`
+
+	for _, tc := range []struct {
+		name, file, content string
+		env                 map[string]string
+		check               func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "YAMLOverDefaults", file: "app.yaml", content: yamlFile,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != "9000" || cfg.Server.RequestTimeout != 20*time.Second || cfg.Database.MaxConns != 25 {
+					t.Errorf("file values not applied: %+v %+v", cfg.Server, cfg.Database)
+				}
+				if cfg.Server.ReadTimeout != Default().Server.ReadTimeout {
+					t.Errorf("key missing from the file lost its default: %s", cfg.Server.ReadTimeout)
+				}
+			},
+		},
+		{
+			name: "TOMLDurations", file: "app.toml", content: tomlFile,
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.RequestTimeout != 20*time.Second || cfg.Server.WriteTimeout != 90*time.Second || cfg.Trash.Retention != 72*time.Hour {
+					t.Errorf("durations decoded as %s, %s, %s", cfg.Server.RequestTimeout, cfg.Server.WriteTimeout, cfg.Trash.Retention)
+				}
+				if cfg.Server.Port != "9000" || cfg.Database.MaxConns != 25 || cfg.Log.Level != "debug" {
+					t.Errorf("TOML values not applied: %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "EnvOverFile", file: "app.toml", content: tomlFile,
+			env: map[string]string{
+				"PORT":                 "9100",
+				"REQUEST_TIMEOUT":      "45s",
+				"DB_MAX_CONNS":         "3",
+				"CORS_ALLOWED_ORIGINS": "https://a.example, https://b.example,",
+				"TRASH_RETENTION_DAYS": "7",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != "9100" || cfg.Server.RequestTimeout != 45*time.Second || cfg.Database.MaxConns != 3 {
+					t.Errorf("environment did not win: %+v %+v", cfg.Server, cfg.Database)
+				}
+				if cfg.Server.WriteTimeout != 90*time.Second || cfg.Log.Level != "debug" {
+					t.Errorf("file values without a variable were lost: %s %s", cfg.Server.WriteTimeout, cfg.Log.Level)
+				}
+				if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://b.example" {
+					t.Errorf("origins %q", cfg.CORS.AllowedOrigins)
+				}
+				if cfg.Trash.Retention != 7*24*time.Hour {
+					t.Errorf("retention %s", cfg.Trash.Retention)
+				}
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.check(t, loadWith(t, tc.file, tc.content, tc.env))
+		})
+	}
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  prot: \"9000\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil {
+		t.Fatal("typo in a key accepted")
+	}
+}
+
+func TestTrashRetentionDaysIsReported(t *testing.T) {
+	for _, days := range []string{"0", "-3", "two"} {
+		cfg := loadWith(t, "app.yaml", "env: development\n", map[string]string{"TRASH_RETENTION_DAYS": days})
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("TRASH_RETENTION_DAYS=%s accepted, retention %s", days, cfg.Trash.Retention)
+		}
+	}
+}
+
+func TestUnparsableEnvIsReported(t *testing.T) {
+	cfg := loadWith(t, "app.yaml", "env: development\n", map[string]string{
+		"DB_MAX_CONNS":       "lots",
+		"REQUEST_TIMEOUT":    "15",
+		"DB_AUTO_MIGRATE":    "maybe",
+		"HTTP_WRITE_TIMEOUT": "30s",
+	})
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("unparsable environment accepted")
+	}
+	for _, want := range []string{`DB_MAX_CONNS must be an integer, got "lots"`, `REQUEST_TIMEOUT must be a duration`, `DB_AUTO_MIGRATE must be true or false, got "maybe"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+	if strings.Contains(err.Error(), "HTTP_WRITE_TIMEOUT") {
+		t.Errorf("valid HTTP_WRITE_TIMEOUT reported: %v", err)
+	}
+}
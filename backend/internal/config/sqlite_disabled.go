@@ -0,0 +1,6 @@
+//go:build !sqlite
+
+package config
+
+// sqliteCompiled reports whether the SQLite driver is linked in, see the sqlite tag
+const sqliteCompiled = false
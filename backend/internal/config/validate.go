@@ -0,0 +1,177 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultJWTSecret  = "secret"
+	defaultDBPassword = "postgres"
+
+	// minJWTSecretLength matches the HS256 key size
+	minJWTSecretLength = 32
+
+	redacted = "<redacted>"
+)
+
+// weakSecrets are placeholder values that must never reach production
+var weakSecrets = []string{defaultJWTSecret, "changeme", "change-me", "jwt-secret", "your-secret-key", "password"}
+
+// Validate reports every invalid setting at once. In production it also refuses the
+// built-in default secrets and short JWT keys.
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	// values from the environment that did not parse come first, the checks below
+	// only see the defaults kept in their place
+	errs = append(errs, c.envErrs...)
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		add("env must be %s or %s, got %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+
+	switch c.Storage {
+	case StoragePostgres, StorageMemory:
+	case StorageSQLite:
+		if !sqliteCompiled {
+			add("storage sqlite needs a build with -tags sqlite")
+		}
+		if c.Database.SQLitePath == "" {
+			add("database.sqlite_path must be set when storage is sqlite")
+		}
+	default:
+		add("storage must be %s, %s or %s, got %q", StoragePostgres, StorageSQLite, StorageMemory, c.Storage)
+	}
+
+	if c.Database.MaxConns < 0 || c.Database.MinConns < 0 {
+		add("database.max_conns and database.min_conns must not be negative")
+	} else if c.Database.MaxConns > 0 && c.Database.MinConns > c.Database.MaxConns {
+		add("database.min_conns must not exceed database.max_conns")
+	}
+	if c.Database.MaxConnLifetime < 0 || c.Database.MaxConnIdleTime < 0 || c.Database.ConnectMaxWait < 0 {
+		add("database durations must not be negative")
+	}
+	switch c.Database.StatementCacheMode {
+	case "", "cache_statement", "cache_describe", "describe_exec", "exec", "simple_protocol":
+	default:
+		add("database.statement_cache_mode must be cache_statement, cache_describe, describe_exec, exec or simple_protocol, got %q", c.Database.StatementCacheMode)
+	}
+
+	if port, err := strconv.Atoi(c.Server.Port); err != nil || port < 1 || port > 65535 {
+		add("server.port must be a port number, got %q", c.Server.Port)
+	}
+	if c.Server.MetricsPort != "" {
+		if port, err := strconv.Atoi(c.Server.MetricsPort); err != nil || port < 1 || port > 65535 {
+			add("server.metrics_port must be a port number or empty, got %q", c.Server.MetricsPort)
+		} else if c.Server.MetricsPort == c.Server.Port {
+			add("server.metrics_port must differ from server.port")
+		}
+	}
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		add("server.tls_cert_file and server.tls_key_file must be set together")
+	}
+	if c.Server.RequestTimeout < 0 || c.Server.ExportTimeout < 0 || c.Server.DrainDelay < 0 || c.Server.ShutdownTimeout < 0 ||
+		c.Server.ReadTimeout < 0 || c.Server.ReadHeaderTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		add("server timeouts must not be negative")
+	}
+	if c.Server.MaxHeaderBytes < 0 || c.Server.MaxBodyBytes < 0 {
+		add("server size limits must not be negative")
+	}
+	if len(c.CORS.AllowedOrigins) == 0 {
+		add("cors.allowed_origins must list at least one origin")
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		add("log.level must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "json", "text":
+	default:
+		add("log.format must be json or text, got %q", c.Log.Format)
+	}
+	switch strings.ToLower(c.Tracing.Exporter) {
+	case "none", "stdout", "otlp":
+	default:
+		add("tracing.exporter must be none, stdout or otlp, got %q", c.Tracing.Exporter)
+	}
+	if c.Trash.Retention <= 0 {
+		add("trash.retention must be positive, got %s", c.Trash.Retention)
+	}
+	// credentials are always allowed and browsers refuse them with a wildcard origin
+	if slices.Contains(c.CORS.AllowedOrigins, "*") {
+		add("cors.allowed_origins must list the origins, * cannot be used with credentials")
+	}
+	// the CORS middleware panics on anything else
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin != "*" && !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") {
+			add("cors.allowed_origins entries must start with http:// or https://, got %q", origin)
+		}
+	}
+
+	if c.JWT.Secret == "" {
+		add("jwt.secret must be set")
+	}
+
+	if c.Env == EnvProduction {
+		if isWeakSecret(c.JWT.Secret) {
+			add("jwt.secret is a well-known default, set JWT_SECRET to a random value")
+		} else if len(c.JWT.Secret) < minJWTSecretLength {
+			add("jwt.secret must be at least %d characters in production", minJWTSecretLength)
+		}
+		if c.Storage == StoragePostgres && c.RawDSN == "" && (c.Database.Password == "" || c.Database.Password == defaultDBPassword) {
+			add("database.password must be changed from the default in production")
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+func isWeakSecret(secret string) bool {
+	for _, weak := range weakSecrets {
+		if strings.EqualFold(secret, weak) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redacted returns a copy that is safe to print, with passwords and keys masked
+func (c *Config) Redacted() *Config {
+	out := *c
+	out.CORS.AllowedOrigins = append([]string(nil), c.CORS.AllowedOrigins...)
+
+	if out.Database.Password != "" {
+		out.Database.Password = redacted
+	}
+	if out.JWT.Secret != "" {
+		out.JWT.Secret = redacted
+	}
+	if out.RawDSN != "" {
+		out.RawDSN = redactDSN(out.RawDSN)
+	}
+
+	return &out
+}
+
+// redactDSN masks the password of a postgres:// URL, key/value DSNs are hidden
+// entirely since they may carry the password anywhere
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil || u.Scheme == "" {
+		return redacted
+	}
+	return u.Redacted()
+}
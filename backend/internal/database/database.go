@@ -3,36 +3,135 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"go-todolist/internal/config"
+	"go-todolist/internal/tracing"
+	"log/slog"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// Connect creates a new PostgreSQL connection pool
-func Connect(dsn string) (*pgxpool.Pool, error) {
-	config, err := pgxpool.ParseConfig(dsn)
+// delays between connection attempts, variables so tests can shorten them
+var (
+	initialRetryDelay = 500 * time.Millisecond
+	maxRetryDelay     = 5 * time.Second
+)
+
+var execModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+// Connect creates a new PostgreSQL connection pool tuned by opts. Pool settings left at
+// zero in opts keep the value from dsn. While the database is unreachable the ping is
+// retried with exponential backoff for up to opts.ConnectMaxWait.
+func Connect(dsn string, opts config.DatabaseConfig) (*pgxpool.Pool, error) {
+	poolConfig, err := newPoolConfig(dsn, opts)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse database config: %w", err)
+		return nil, err
 	}
 
-	pool, err := pgxpool.NewWithConfig(context.Background(), config)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("unable to connect to database: %w", err)
 	}
 
 	// Test the connection
-	if err := pool.Ping(context.Background()); err != nil {
+	if err := pingWithRetry(context.Background(), pool, opts.ConnectMaxWait); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("unable to ping database: %w", err)
 	}
 
-	log.Println("Successfully connected to database")
+	slog.Info("successfully connected to database",
+		"max_conns", poolConfig.MaxConns,
+		"exec_mode", poolConfig.ConnConfig.DefaultQueryExecMode.String(),
+	)
 	return pool, nil
 }
 
+// newPoolConfig parses dsn and applies the settings opts sets explicitly on top
+func newPoolConfig(dsn string, opts config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse database config: %w", err)
+	}
+
+	if opts.MaxConns > 0 {
+		poolConfig.MaxConns = opts.MaxConns
+	}
+	if opts.MinConns > 0 {
+		poolConfig.MinConns = opts.MinConns
+	}
+	if opts.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = opts.MaxConnLifetime
+	}
+	if opts.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = opts.MaxConnIdleTime
+	}
+	if opts.StatementCacheMode != "" {
+		mode, ok := execModes[opts.StatementCacheMode]
+		if !ok {
+			return nil, fmt.Errorf("unknown statement cache mode %q", opts.StatementCacheMode)
+		}
+		poolConfig.ConnConfig.DefaultQueryExecMode = mode
+	}
+
+	// Record a span for every query, a no-op while tracing is disabled
+	poolConfig.ConnConfig.Tracer = tracing.PgxTracer{}
+
+	return poolConfig, nil
+}
+
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// pingWithRetry pings until it succeeds or maxWait has passed, doubling the delay
+// between attempts up to maxRetryDelay. Every attempt shares the maxWait deadline, so a
+// ping hanging on an unresponsive host cannot hold startup past it. With a zero maxWait
+// the single attempt is only bounded by ctx and the connect_timeout of the DSN.
+func pingWithRetry(ctx context.Context, pool pinger, maxWait time.Duration) error {
+	deadline := time.Now().Add(maxWait)
+	if maxWait > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+	delay := initialRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return err
+		}
+		if delay > remaining {
+			delay = remaining
+		}
+
+		slog.Warn("database not ready, retrying", "attempt", attempt, "retry_in", delay.Round(time.Millisecond).String(), "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
 // Close closes the database connection pool
 func Close(pool *pgxpool.Pool) {
 	if pool != nil {
 		pool.Close()
-		log.Println("Database connection closed")
+		slog.Info("database connection closed")
 	}
 }
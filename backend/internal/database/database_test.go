@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/config"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// flakyDB fails the first failures pings, a ping with blockUntilDone set hangs until
+// its context ends like one sent to an unresponsive host
+type flakyDB struct {
+	failures       int
+	blockUntilDone bool
+
+	pings     []time.Time
+	deadlines []time.Time
+}
+
+func (db *flakyDB) Ping(ctx context.Context) error {
+	db.pings = append(db.pings, time.Now())
+	deadline, _ := ctx.Deadline()
+	db.deadlines = append(db.deadlines, deadline)
+
+	if db.blockUntilDone {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	if len(db.pings) <= db.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func shortenRetryDelays(t *testing.T) {
+	t.Helper()
+	initial, max := initialRetryDelay, maxRetryDelay
+	initialRetryDelay, maxRetryDelay = 10*time.Millisecond, 40*time.Millisecond
+	t.Cleanup(func() { initialRetryDelay, maxRetryDelay = initial, max })
+}
+
+func TestPingWithRetry(t *testing.T) {
+	shortenRetryDelays(t)
+
+	t.Run("BacksOffUntilReady", func(t *testing.T) {
+		db := &flakyDB{failures: 4}
+		if err := pingWithRetry(context.Background(), db, time.Second); err != nil {
+			t.Fatal(err)
+		}
+		if len(db.pings) != 5 {
+			t.Fatalf("pinged %d times, want 5", len(db.pings))
+		}
+
+		// 10ms doubling to 20ms and 40ms, then capped at 40ms
+		want := []time.Duration{10, 20, 40, 40}
+		for i, w := range want {
+			gap := db.pings[i+1].Sub(db.pings[i])
+			if w *= time.Millisecond; gap < w || gap > w+30*time.Millisecond {
+				t.Errorf("delay before attempt %d = %s, want %s", i+2, gap, w)
+			}
+		}
+	})
+
+	t.Run("GivesUpAfterMaxWait", func(t *testing.T) {
+		db := &flakyDB{failures: 1000}
+		start := time.Now()
+		err := pingWithRetry(context.Background(), db, 100*time.Millisecond)
+		if err == nil || err.Error() != "connection refused" {
+			t.Fatalf("got %v, want the last ping error", err)
+		}
+		if elapsed := time.Since(start); elapsed < 100*time.Millisecond || elapsed > 200*time.Millisecond {
+			t.Fatalf("gave up after %s, want about 100ms", elapsed)
+		}
+	})
+
+	t.Run("HangingPingBoundedByMaxWait", func(t *testing.T) {
+		db := &flakyDB{blockUntilDone: true}
+		start := time.Now()
+		if err := pingWithRetry(context.Background(), db, 50*time.Millisecond); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("got %v", err)
+		}
+		if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+			t.Fatalf("hanging ping held startup for %s", elapsed)
+		}
+		if wait := db.deadlines[0].Sub(start); wait < 50*time.Millisecond || wait > 60*time.Millisecond {
+			t.Fatalf("ping deadline %v is not the max wait deadline", db.deadlines[0])
+		}
+	})
+
+	t.Run("ZeroMaxWaitTriesOnce", func(t *testing.T) {
+		db := &flakyDB{failures: 1}
+		if err := pingWithRetry(context.Background(), db, 0); err == nil {
+			t.Fatal("failed ping reported as success")
+		}
+		if len(db.pings) != 1 || !db.deadlines[0].IsZero() {
+			t.Fatalf("pinged %d times with deadline %v, want once without", len(db.pings), db.deadlines[0])
+		}
+	})
+}
+
+func TestPoolConfigKeepsDSNSettings(t *testing.T) {
+	const dsn = "postgres://app@db.internal/todo?pool_max_conns=42&pool_max_conn_lifetime=2h&default_query_exec_mode=exec"
+
+	fromDSN, err := newPoolConfig(dsn, config.Default().Database)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromDSN.MaxConns != 42 || fromDSN.MaxConnLifetime != 2*time.Hour || fromDSN.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeExec {
+		t.Fatalf("defaults replaced the DSN settings: max conns %d, lifetime %s, exec mode %s",
+			fromDSN.MaxConns, fromDSN.MaxConnLifetime, fromDSN.ConnConfig.DefaultQueryExecMode)
+	}
+
+	opts := config.Default().Database
+	opts.MaxConns, opts.StatementCacheMode = 7, "simple_protocol"
+	explicit, err := newPoolConfig(dsn, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explicit.MaxConns != 7 || explicit.MaxConnLifetime != 2*time.Hour || explicit.ConnConfig.DefaultQueryExecMode != pgx.QueryExecModeSimpleProtocol {
+		t.Fatalf("explicit settings not applied: max conns %d, lifetime %s, exec mode %s",
+			explicit.MaxConns, explicit.MaxConnLifetime, explicit.ConnConfig.DefaultQueryExecMode)
+	}
+}
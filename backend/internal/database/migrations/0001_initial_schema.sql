@@ -1,11 +1,8 @@
--- Todolist Database Schema
--- PostgreSQL
-
--- Create database (run this separately if needed)
--- Create DATABASE db_todolist;
+-- Initial schema
+-- IF NOT EXISTS keeps this safe on databases created from the old schema.sql
 
 -- Users table
-CREATE TABLE users (
+CREATE TABLE IF NOT EXISTS users (
     id SERIAL PRIMARY KEY,
     username VARCHAR(255) UNIQUE NOT NULL,
     password VARCHAR(255) NOT NULL,
@@ -14,7 +11,7 @@ CREATE TABLE users (
 );
 
 -- Todos table
-CREATE TABLE todos (
+CREATE TABLE IF NOT EXISTS todos (
     id SERIAL PRIMARY KEY,
     user_id INTEGER NOT NULL,
     title VARCHAR(255) NOT NULL,
@@ -26,7 +23,7 @@ CREATE TABLE todos (
 );
 
 -- Tickets table
-CREATE TABLE tickets (
+CREATE TABLE IF NOT EXISTS tickets (
     id SERIAL PRIMARY KEY,
     title VARCHAR(255) NOT NULL,
     description TEXT,
@@ -42,7 +39,7 @@ CREATE TABLE tickets (
 );
 
 -- Activity Logs table
-CREATE TABLE activity_logs (
+CREATE TABLE IF NOT EXISTS activity_logs (
     id SERIAL PRIMARY KEY,
     ticket_id INTEGER REFERENCES tickets(id) ON DELETE CASCADE,
     user_id INTEGER REFERENCES users(id),
@@ -51,4 +48,4 @@ CREATE TABLE activity_logs (
 );
 
 -- Add profile_photo to users (optional, if you want to support it)
-ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_photo TEXT;
\ No newline at end of file
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_photo TEXT;
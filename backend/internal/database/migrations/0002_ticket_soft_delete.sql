@@ -0,0 +1,3 @@
+-- Soft delete for tickets: trashed tickets keep their activity history until purged
+ALTER TABLE tickets ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+CREATE INDEX IF NOT EXISTS idx_tickets_deleted_at ON tickets (deleted_at) WHERE deleted_at IS NOT NULL;
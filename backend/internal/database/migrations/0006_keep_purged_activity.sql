@@ -0,0 +1,5 @@
+-- Keep the activity history of purged tickets, their entries lose the ticket reference
+-- instead of being deleted with the ticket
+ALTER TABLE activity_logs DROP CONSTRAINT IF EXISTS activity_logs_ticket_id_fkey;
+ALTER TABLE activity_logs ADD CONSTRAINT activity_logs_ticket_id_fkey
+    FOREIGN KEY (ticket_id) REFERENCES tickets(id) ON DELETE SET NULL;
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TableStats is the planner's view of one table
+type TableStats struct {
+	Name      string `json:"name"`
+	LiveRows  int64  `json:"live_rows"`
+	DeadRows  int64  `json:"dead_rows"`
+	TotalSize string `json:"total_size"`
+}
+
+// Stats describes the size of the database
+type Stats struct {
+	Database string       `json:"database"`
+	Size     string       `json:"size"`
+	Tables   []TableStats `json:"tables"`
+}
+
+// CollectStats reads size and row estimates from the PostgreSQL statistics views,
+// it never scans the tables themselves
+func CollectStats(ctx context.Context, pool *pgxpool.Pool) (*Stats, error) {
+	stats := &Stats{}
+	err := pool.QueryRow(ctx, `
+		SELECT current_database(), pg_size_pretty(pg_database_size(current_database()))
+	`).Scan(&stats.Database, &stats.Size)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read database size: %w", err)
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT relname, n_live_tup, n_dead_tup, pg_size_pretty(pg_total_relation_size(relid))
+		FROM pg_stat_user_tables
+		ORDER BY relname
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read table statistics: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t TableStats
+		if err := rows.Scan(&t.Name, &t.LiveRows, &t.DeadRows, &t.TotalSize); err != nil {
+			return nil, fmt.Errorf("unable to scan table statistics: %w", err)
+		}
+		stats.Tables = append(stats.Tables, t)
+	}
+	return stats, rows.Err()
+}
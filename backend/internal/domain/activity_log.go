@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type ActivityLog struct {
 	ID        int       `json:"id"`
@@ -11,7 +14,19 @@ type ActivityLog struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ActivityLogFilter narrows down an activity export, empty fields are ignored
+type ActivityLogFilter struct {
+	TicketID *int
+	UserID   *int
+	Since    *time.Time
+	Until    *time.Time
+}
+
 type ActivityLogRepository interface {
-	Create(log *ActivityLog) error
-	FindAll() ([]ActivityLog, error)
+	Create(ctx context.Context, log *ActivityLog) error
+	FindAll(ctx context.Context) ([]ActivityLog, error)
+	// FindAfter returns at most limit entries with an id above afterID, oldest first,
+	// so a follower can page through the feed without skipping entries
+	FindAfter(ctx context.Context, afterID, limit int) ([]ActivityLog, error)
+	Stream(ctx context.Context, filter ActivityLogFilter, fn func(*ActivityLog) error) error
 }
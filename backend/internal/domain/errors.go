@@ -0,0 +1,65 @@
+package domain
+
+import "errors"
+
+// Error kinds, match them with errors.Is
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrForbidden    = errors.New("forbidden")
+	ErrValidation   = errors.New("validation failed")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Error is returned by repositories and services for failures the client can act on.
+// Code and Message are safe to send in a response, Err is the underlying cause and
+// stays internal.
+type Error struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
+
+func NewNotFoundError(code, message string) *Error {
+	return &Error{Kind: ErrNotFound, Code: code, Message: message}
+}
+
+func NewConflictError(code, message string) *Error {
+	return &Error{Kind: ErrConflict, Code: code, Message: message}
+}
+
+func NewForbiddenError(code, message string) *Error {
+	return &Error{Kind: ErrForbidden, Code: code, Message: message}
+}
+
+func NewValidationError(code, message string) *Error {
+	return &Error{Kind: ErrValidation, Code: code, Message: message}
+}
+
+func NewUnauthorizedError(code, message string) *Error {
+	return &Error{Kind: ErrUnauthorized, Code: code, Message: message}
+}
+
+// Common errors shared by several layers
+var (
+	ErrTicketNotFound      = NewNotFoundError("ticket_not_found", "Ticket not found")
+	ErrTicketForbidden     = NewForbiddenError("ticket_forbidden", "Only the creator or the assignee can modify this ticket")
+	ErrUserNotFound        = NewNotFoundError("user_not_found", "User not found")
+	ErrTodoNotFound        = NewNotFoundError("todo_not_found", "Todo not found")
+	ErrSavedFilterNotFound = NewNotFoundError("saved_filter_not_found", "Saved filter not found")
+)
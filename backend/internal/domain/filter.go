@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// TicketFilter holds the criteria used to narrow down a ticket listing,
+// empty fields are ignored
+type TicketFilter struct {
+	Statuses      []string   `json:"status,omitempty" binding:"dive,ticket_status"`
+	Priorities    []string   `json:"priority,omitempty" binding:"dive,ticket_priority"`
+	AssigneeID    *int       `json:"assignee_id,omitempty" binding:"omitempty,gt=0"`
+	CreatorID     *int       `json:"creator_id,omitempty" binding:"omitempty,gt=0"`
+	AssignedToMe  bool       `json:"assigned_to_me,omitempty"`
+	DueWithinDays *int       `json:"due_within_days,omitempty" binding:"omitempty,gte=0"`
+	DueAfter      *time.Time `json:"due_after,omitempty"`
+	DueBefore     *time.Time `json:"due_before,omitempty"`
+}
+
+type SavedFilter struct {
+	ID        int          `json:"id"`
+	UserID    int          `json:"user_id"`
+	Username  string       `json:"username"`
+	Name      string       `json:"name"`
+	Criteria  TicketFilter `json:"criteria"`
+	Shared    bool         `json:"shared"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type SavedFilterRepository interface {
+	Create(ctx context.Context, filter *SavedFilter) error
+	FindByID(ctx context.Context, id int) (*SavedFilter, error)
+	FindVisibleTo(ctx context.Context, userID int) ([]SavedFilter, error)
+	Update(ctx context.Context, filter *SavedFilter) error
+	Delete(ctx context.Context, id int) error
+}
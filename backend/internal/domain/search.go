@@ -0,0 +1,15 @@
+package domain
+
+type TicketSearchParams struct {
+	Query      string
+	Status     string
+	AssigneeID *int
+	Limit      int
+}
+
+type TicketSearchResult struct {
+	Ticket
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
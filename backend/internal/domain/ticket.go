@@ -1,6 +1,43 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
+
+const (
+	StatusBacklog    = "Backlog"
+	StatusTodo       = "Todo"
+	StatusInProgress = "In Progress"
+	StatusDone       = "Done"
+
+	PriorityLow    = "Low"
+	PriorityMedium = "Medium"
+	PriorityHigh   = "High"
+)
+
+// TicketStatuses lists the board columns in order
+var TicketStatuses = []string{StatusBacklog, StatusTodo, StatusInProgress, StatusDone}
+
+var TicketPriorities = []string{PriorityLow, PriorityMedium, PriorityHigh}
+
+func IsValidTicketStatus(status string) bool {
+	for _, s := range TicketStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func IsValidTicketPriority(priority string) bool {
+	for _, p := range TicketPriorities {
+		if p == priority {
+			return true
+		}
+	}
+	return false
+}
 
 type Ticket struct {
 	ID               int        `json:"id"`
@@ -15,14 +52,106 @@ type Ticket struct {
 	DueDate          *time.Time `json:"due_date,omitempty"`
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
+}
+
+// AnyEditor is passed as the editor of a ticket write that is not limited to the
+// tickets one user may modify, such as an operator command
+const AnyEditor = 0
+
+// EditableBy reports whether editorID may modify t: its creator and its current
+// assignee may, and anyone when editorID is AnyEditor
+func (t *Ticket) EditableBy(editorID int) bool {
+	return editorID == AnyEditor || t.CreatorID == editorID || t.AssigneeID != nil && *t.AssigneeID == editorID
+}
+
+// TicketChanges is a partial update of a ticket. Only the fields that are set are
+// written, so concurrent changes to the other fields are kept.
+type TicketChanges struct {
+	Title       *string
+	Description *string
+	Status      *string
+	Priority    *string
+	DueDate     NullableChange[time.Time]
+	AssigneeID  NullableChange[int]
+}
+
+// NullableChange updates a nullable field when Set, a nil Value clears it
+type NullableChange[T any] struct {
+	Set   bool
+	Value *T
+}
+
+// IsEmpty reports whether the changes leave every field untouched
+func (c TicketChanges) IsEmpty() bool {
+	return c.Title == nil && c.Description == nil && c.Status == nil && c.Priority == nil && !c.DueDate.Set && !c.AssigneeID.Set
+}
+
+// Apply copies the changed fields onto t
+func (c TicketChanges) Apply(t *Ticket) {
+	if c.Title != nil {
+		t.Title = *c.Title
+	}
+	if c.Description != nil {
+		t.Description = *c.Description
+	}
+	if c.Status != nil {
+		t.Status = *c.Status
+	}
+	if c.Priority != nil {
+		t.Priority = *c.Priority
+	}
+	if c.DueDate.Set {
+		t.DueDate = nil
+		if c.DueDate.Value != nil {
+			dueDate := *c.DueDate.Value
+			t.DueDate = &dueDate
+		}
+	}
+	if c.AssigneeID.Set {
+		t.AssigneeID = nil
+		if c.AssigneeID.Value != nil {
+			assigneeID := *c.AssigneeID.Value
+			t.AssigneeID = &assigneeID
+		}
+	}
+}
+
+// TicketImport is a ticket together with its activity history. Import stores both with
+// the timestamps they carry instead of the current time.
+type TicketImport struct {
+	Ticket  *Ticket
+	History []*ActivityLog
 }
 
+// TicketRepository stores tickets. The writes that change one existing ticket take
+// the editor making the change and apply only when the ticket is EditableBy them,
+// checked in the same statement so a concurrent reassignment cannot slip between
+// the check and the write. A ticket the editor may not modify is refused with
+// ErrTicketForbidden.
 type TicketRepository interface {
-	Create(ticket *Ticket) error
-	FindAll() ([]Ticket, error)
-	FindByID(id int) (*Ticket, error)
-	Update(ticket *Ticket) error
-	Delete(id int) error
-	FindByStatus(status string) ([]Ticket, error)
-	UpdateStatus(id int, status string) error
+	Create(ctx context.Context, ticket *Ticket) error
+	FindAll(ctx context.Context) ([]Ticket, error)
+	FindByFilter(ctx context.Context, filter TicketFilter) ([]Ticket, error)
+	StreamByFilter(ctx context.Context, filter TicketFilter, fn func(*Ticket) error) error
+	FindByID(ctx context.Context, id int) (*Ticket, error)
+	Update(ctx context.Context, ticket *Ticket, editorID int) error
+	// Patch writes only the fields set in changes and bumps updated_at
+	Patch(ctx context.Context, id int, changes TicketChanges, editorID int) error
+	Delete(ctx context.Context, id int, editorID int) error
+	FindByStatus(ctx context.Context, status string) ([]Ticket, error)
+	UpdateStatus(ctx context.Context, id int, status string, editorID int) error
+	// PatchMany applies the same changes to every ticket in ids and stores logs in one
+	// transaction, writing nothing when one of the tickets does not exist or may not
+	// be modified by editorID
+	PatchMany(ctx context.Context, ids []int, changes TicketChanges, logs []*ActivityLog, editorID int) error
+	Import(ctx context.Context, tickets []TicketImport) error
+	FindTrashed(ctx context.Context) ([]Ticket, error)
+	Restore(ctx context.Context, id int, editorID int) error
+	// Purge removes tickets trashed before deletedBefore for good. Their activity logs
+	// are kept with the ticket reference cleared.
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	Search(ctx context.Context, params TicketSearchParams) ([]TicketSearchResult, error)
+	CountByStatus(ctx context.Context) (map[string]int, error)
+	CountTrashed(ctx context.Context) (int, error)
 }
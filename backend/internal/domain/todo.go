@@ -1,6 +1,9 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type Todo struct {
 	ID          int       `json:"id"`
@@ -13,9 +16,9 @@ type Todo struct {
 }
 
 type TodoRepository interface {
-	Create(todo *Todo) error
-	FindAll() ([]Todo, error)
-	FindById(id int) (*Todo, error)
-	Update(todo *Todo) error
-	Delete(id int) error
+	Create(ctx context.Context, todo *Todo) error
+	FindAll(ctx context.Context) ([]Todo, error)
+	FindById(ctx context.Context, id int) (*Todo, error)
+	Update(ctx context.Context, todo *Todo) error
+	Delete(ctx context.Context, id int) error
 }
@@ -1,20 +1,54 @@
 package domain
 
-import "time"
+import (
+	"context"
+	"time"
+)
+
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// UserRoles lists the valid roles
+var UserRoles = []string{RoleUser, RoleAdmin}
+
+func IsValidUserRole(role string) bool {
+	for _, r := range UserRoles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
 
 type User struct {
-	ID           int       `json:"id"`
-	Username     string    `json:"username"`
-	Password     string    `json:"-"`
-	ProfilePhoto string    `json:"profile_photo,omitempty"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           int        `json:"id"`
+	Username     string     `json:"username"`
+	Password     string     `json:"-"`
+	ProfilePhoto string     `json:"profile_photo,omitempty"`
+	Role         string     `json:"role"`
+	DisabledAt   *time.Time `json:"disabled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Disabled reports whether an administrator disabled the account
+func (u *User) Disabled() bool {
+	return u.DisabledAt != nil
 }
 
 type UserRepository interface {
-	Create(user *User) error
-	FindByUsername(username string) (*User, error)
-	FindByID(id int) (*User, error)
-	FindAll() ([]User, error)
-	Update(user *User) error
+	Create(ctx context.Context, user *User) error
+	FindByUsername(ctx context.Context, username string) (*User, error)
+	FindByID(ctx context.Context, id int) (*User, error)
+	FindAll(ctx context.Context) ([]User, error)
+	// Update writes the username, password and profile photo. The role and the
+	// disabled state have their own writes, so a profile change made from a copy
+	// loaded earlier cannot undo them.
+	Update(ctx context.Context, user *User) error
+	SetRole(ctx context.Context, id int, role string) error
+	// SetDisabled disables the account at disabledAt, nil enables it again
+	SetDisabled(ctx context.Context, id int, disabledAt *time.Time) error
 }
@@ -0,0 +1,29 @@
+package export
+
+import "go-todolist/internal/domain"
+
+// TicketColumns are the fields of a ticket export
+var TicketColumns = Columns[domain.Ticket]{
+	{"id", func(t *domain.Ticket) any { return t.ID }},
+	{"title", func(t *domain.Ticket) any { return t.Title }},
+	{"description", func(t *domain.Ticket) any { return t.Description }},
+	{"status", func(t *domain.Ticket) any { return t.Status }},
+	{"priority", func(t *domain.Ticket) any { return t.Priority }},
+	{"due_date", func(t *domain.Ticket) any { return t.DueDate }},
+	{"creator_id", func(t *domain.Ticket) any { return t.CreatorID }},
+	{"creator_username", func(t *domain.Ticket) any { return t.CreatorUsername }},
+	{"assignee_id", func(t *domain.Ticket) any { return t.AssigneeID }},
+	{"assignee_username", func(t *domain.Ticket) any { return t.AssigneeUsername }},
+	{"created_at", func(t *domain.Ticket) any { return t.CreatedAt }},
+	{"updated_at", func(t *domain.Ticket) any { return t.UpdatedAt }},
+}
+
+// ActivityLogColumns are the fields of an activity log export
+var ActivityLogColumns = Columns[domain.ActivityLog]{
+	{"id", func(l *domain.ActivityLog) any { return l.ID }},
+	{"ticket_id", func(l *domain.ActivityLog) any { return l.TicketID }},
+	{"user_id", func(l *domain.ActivityLog) any { return l.UserID }},
+	{"username", func(l *domain.ActivityLog) any { return l.Username }},
+	{"action", func(l *domain.ActivityLog) any { return l.Action }},
+	{"created_at", func(l *domain.ActivityLog) any { return l.CreatedAt }},
+}
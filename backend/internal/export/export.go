@@ -0,0 +1,196 @@
+// Package export encodes records as CSV or newline-delimited JSON for the export
+// endpoints. Records are written one at a time through small buffers, so an export
+// never holds more than a row in memory.
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// Formats lists the supported encodings, the first one is the default
+var Formats = []string{FormatCSV, FormatNDJSON}
+
+// IsValidFormat reports whether format is one of Formats
+func IsValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// ContentType returns the media type of format
+func ContentType(format string) string {
+	if format == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Column is one field of an exported record. Value returns the field as is, pointers
+// included, and the encoder decides how to render it.
+type Column[T any] struct {
+	Name  string
+	Value func(*T) any
+}
+
+// Columns is every field a record can be exported with, in their default order
+type Columns[T any] []Column[T]
+
+// Names returns the column names in order
+func (cs Columns[T]) Names() []string {
+	names := make([]string, len(cs))
+	for i, c := range cs {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// Select returns the named columns in the order given, or all of them when names is
+// empty. The bool is false if a name is unknown.
+func (cs Columns[T]) Select(names []string) (Columns[T], bool) {
+	if len(names) == 0 {
+		return cs, true
+	}
+
+	selected := make(Columns[T], 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, c := range cs {
+			if c.Name == name {
+				selected = append(selected, c)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, false
+		}
+	}
+	return selected, true
+}
+
+// Writer encodes records of type T in one format
+type Writer[T any] struct {
+	columns Columns[T]
+	csv     *csv.Writer
+	json    *bufio.Writer
+	row     []string
+	buf     bytes.Buffer
+}
+
+// NewWriter returns a writer for format. CSV output starts with a header row.
+func NewWriter[T any](w io.Writer, format string, columns Columns[T]) (*Writer[T], error) {
+	ew := &Writer[T]{columns: columns, row: make([]string, len(columns))}
+
+	switch format {
+	case FormatCSV:
+		ew.csv = csv.NewWriter(w)
+		if err := ew.csv.Write(columns.Names()); err != nil {
+			return nil, err
+		}
+	case FormatNDJSON:
+		ew.json = bufio.NewWriter(w)
+	default:
+		return nil, fmt.Errorf("export: unknown format %q", format)
+	}
+
+	return ew, nil
+}
+
+// Write encodes one record
+func (w *Writer[T]) Write(record *T) error {
+	if w.csv != nil {
+		for i, c := range w.columns {
+			w.row[i] = csvCell(c.Value(record))
+		}
+		return w.csv.Write(w.row)
+	}
+
+	// marshal each field on its own to keep the column order in the object
+	w.buf.Reset()
+	w.buf.WriteByte('{')
+	for i, c := range w.columns {
+		if i > 0 {
+			w.buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(c.Name)
+		value, err := json.Marshal(c.Value(record))
+		if err != nil {
+			return fmt.Errorf("export: failed to encode %s: %w", c.Name, err)
+		}
+		w.buf.Write(key)
+		w.buf.WriteByte(':')
+		w.buf.Write(value)
+	}
+	w.buf.WriteString("}\n")
+
+	_, err := w.json.Write(w.buf.Bytes())
+	return err
+}
+
+// Flush writes any buffered output to the underlying writer
+func (w *Writer[T]) Flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.json.Flush()
+}
+
+// csvCell renders a value for a spreadsheet: nil is an empty cell, times are RFC 3339
+// in UTC and text is guarded against formula injection
+func csvCell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return neutralize(v)
+	case *string:
+		if v == nil {
+			return ""
+		}
+		return neutralize(*v)
+	case int:
+		return strconv.Itoa(v)
+	case *int:
+		if v == nil {
+			return ""
+		}
+		return strconv.Itoa(*v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// neutralize prefixes text that a spreadsheet would evaluate as a formula with a quote,
+// quoting of commas, quotes and newlines is left to encoding/csv
+func neutralize(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
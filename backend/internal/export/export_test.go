@@ -0,0 +1,115 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"go-todolist/internal/domain"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sampleTickets() []domain.Ticket {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	bob := "bob"
+	return []domain.Ticket{
+		{
+			ID:               7,
+			Title:            `Fix "Save as...", then ship`,
+			Description:      "line one\nline two, with a comma",
+			Status:           domain.StatusTodo,
+			Priority:         domain.PriorityHigh,
+			DueDate:          &due,
+			CreatorID:        1,
+			CreatorUsername:  "alice",
+			AssigneeID:       intPtr(2),
+			AssigneeUsername: &bob,
+			CreatedAt:        time.Date(2026, 2, 1, 9, 30, 0, 0, time.UTC),
+		},
+		{ID: 8, Title: "=HYPERLINK(\"http://evil\")", Description: "-1+1", Status: domain.StatusBacklog, CreatorID: 1, CreatorUsername: "alice"},
+	}
+}
+
+func intPtr(v int) *int { return &v }
+
+func encode(t *testing.T, format string, columns Columns[domain.Ticket], tickets []domain.Ticket) string {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, format, columns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range tickets {
+		if err := w.Write(&tickets[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCSVQuotesAndNeutralizesCells(t *testing.T) {
+	columns, ok := TicketColumns.Select([]string{"id", "title", "description", "due_date", "assignee_username", "created_at"})
+	if !ok {
+		t.Fatal("columns not found")
+	}
+
+	out := encode(t, FormatCSV, columns, sampleTickets())
+	records, err := csv.NewReader(strings.NewReader(out)).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, out)
+	}
+
+	want := [][]string{
+		{"id", "title", "description", "due_date", "assignee_username", "created_at"},
+		{"7", `Fix "Save as...", then ship`, "line one\nline two, with a comma", "2026-03-01T00:00:00Z", "bob", "2026-02-01T09:30:00Z"},
+		{"8", `'=HYPERLINK("http://evil")`, "'-1+1", "", "", "0001-01-01T00:00:00Z"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("got %q\nwant %q", records, want)
+	}
+}
+
+func TestCSVWithoutRowsHasHeader(t *testing.T) {
+	columns, _ := TicketColumns.Select([]string{"status", "id"})
+	if out := encode(t, FormatCSV, columns, nil); out != "status,id\n" {
+		t.Fatalf("got %q", out)
+	}
+}
+
+func TestNDJSONKeepsColumnOrderAndNulls(t *testing.T) {
+	columns, _ := TicketColumns.Select([]string{"title", "id", "assignee_id", "due_date"})
+	out := encode(t, FormatNDJSON, columns, sampleTickets())
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines: %q", len(lines), out)
+	}
+	if want := `{"title":"=HYPERLINK(\"http://evil\")","id":8,"assignee_id":null,"due_date":null}`; lines[1] != want {
+		t.Fatalf("got %s\nwant %s", lines[1], want)
+	}
+
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if first["title"] != `Fix "Save as...", then ship` || first["assignee_id"] != float64(2) || first["due_date"] != "2026-03-01T00:00:00Z" {
+		t.Fatalf("got %v", first)
+	}
+}
+
+func TestSelectColumns(t *testing.T) {
+	if all, ok := TicketColumns.Select(nil); !ok || len(all) != len(TicketColumns) {
+		t.Fatalf("empty selection returned %d columns", len(all))
+	}
+	if _, ok := ActivityLogColumns.Select([]string{"action", "password"}); ok {
+		t.Fatal("unknown column accepted")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, "xlsx", TicketColumns); err == nil {
+		t.Fatal("unknown format accepted")
+	}
+}
@@ -1,9 +1,12 @@
 package handler
 
 import (
+	"go-todolist/internal/domain"
+	"go-todolist/internal/export"
 	"go-todolist/internal/service"
 	"go-todolist/internal/utils"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -18,12 +21,78 @@ func NewActivityLogHandler(activityLogService *service.ActivityLogService) *Acti
 	}
 }
 
+// GetAll returns the latest 100 entries newest first. With after_id it returns the
+// entries after that id oldest first instead, up to limit, so a follower can page
+// through bursts without skipping any.
 func (h *ActivityLogHandler) GetAll(c *gin.Context) {
-	logs, err := h.activityLogService.FindAll()
+	rawAfter, paged := c.GetQuery("after_id")
+	if !paged {
+		if c.Query("limit") != "" {
+			utils.ValidationErrorsResponse(c, []utils.FieldError{{Field: "after_id", Code: "required", Message: "is required with limit"}})
+			return
+		}
+		logs, err := h.activityLogService.FindAll(c.Request.Context())
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		utils.SuccessResponse(c, http.StatusOK, "Activity logs retrieved successfully", logs)
+		return
+	}
+
+	var errs []utils.FieldError
+	afterID, err := strconv.Atoi(rawAfter)
+	if err != nil || afterID < 0 {
+		errs = append(errs, utils.FieldError{Field: "after_id", Code: "invalid_type", Message: "must be a non-negative integer"})
+	}
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		if limit, err = strconv.Atoi(raw); err != nil {
+			errs = append(errs, utils.FieldError{Field: "limit", Code: "invalid_type", Message: "must be an integer"})
+		}
+	}
+	if len(errs) > 0 {
+		utils.ValidationErrorsResponse(c, errs)
+		return
+	}
+
+	logs, err := h.activityLogService.FindAfter(c.Request.Context(), afterID, limit)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to fetch activity logs")
+		c.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Activity logs retrieved successfully", logs)
 }
+
+// Export streams every activity log matching the query as CSV or NDJSON, unlike GetAll
+// it has no limit
+func (h *ActivityLogHandler) Export(c *gin.Context) {
+	filter, errs := parseActivityLogFilter(c)
+	format, columns, exportErrs := parseExportOptions(c, export.ActivityLogColumns)
+	if errs = append(errs, exportErrs...); len(errs) > 0 {
+		utils.ValidationErrorsResponse(c, errs)
+		return
+	}
+
+	streamExport(c, "activity", format, columns, func(fn func(*domain.ActivityLog) error) error {
+		return h.activityLogService.ExportLogs(c.Request.Context(), filter, fn)
+	})
+}
+
+// parseActivityLogFilter reads ticket_id, user_id and the since/until range, until is
+// exclusive like due_before on the ticket listing
+func parseActivityLogFilter(c *gin.Context) (domain.ActivityLogFilter, []utils.FieldError) {
+	var filter domain.ActivityLogFilter
+
+	errs := parseIntParams(c, []intParam{
+		{"ticket_id", &filter.TicketID},
+		{"user_id", &filter.UserID},
+	})
+	errs = append(errs, parseDateParams(c, []dateParam{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	})...)
+
+	return filter, errs
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"go-todolist/internal/metrics"
 	"go-todolist/internal/service"
 	"go-todolist/internal/utils"
 	"net/http"
@@ -18,32 +19,38 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 	}
 }
 
+// Login counts every attempt that does not end in a session as a failure, bad
+// credentials, disabled accounts, invalid bodies and internal errors alike
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req service.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid request body")
+		metrics.LoginAttempts.WithLabelValues("failure").Inc()
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
-	response, err := h.authService.Login(req)
+	response, err := h.authService.Login(c.Request.Context(), req)
 	if err != nil {
-		utils.UnauthorizedResponse(c, err.Error())
+		metrics.LoginAttempts.WithLabelValues("failure").Inc()
+		c.Error(err)
 		return
 	}
 
+	metrics.LoginAttempts.WithLabelValues("success").Inc()
+
 	utils.SuccessResponse(c, http.StatusOK, "Login successful", response)
 }
 
 func (h *AuthHandler) Signup(c *gin.Context) {
 	var req service.SignUpRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid required body")
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
-	response, err := h.authService.Signup(req)
+	response, err := h.authService.Signup(c.Request.Context(), req)
 	if err != nil {
-		utils.ValidationErrorResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
@@ -51,9 +58,9 @@ func (h *AuthHandler) Signup(c *gin.Context) {
 }
 
 func (h *AuthHandler) GetUsers(c *gin.Context) {
-	users, err := h.authService.FindAllUsers()
+	users, err := h.authService.FindAllUsers(c.Request.Context())
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "Failed to fetch users")
+		c.Error(err)
 		return
 	}
 
@@ -65,17 +72,17 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 	uID, _ := userID.(int)
 
 	var req struct {
-		Username     string `json:"username"`
+		Username     string `json:"username" binding:"required,min=3,max=255"`
 		ProfilePhoto string `json:"profile_photo"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid request body")
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
-	if err := h.authService.UpdateProfile(uID, req.Username, req.ProfilePhoto); err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.authService.UpdateProfile(c.Request.Context(), uID, req.Username, req.ProfilePhoto); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -87,16 +94,16 @@ func (h *AuthHandler) UpdatePassword(c *gin.Context) {
 	uID, _ := userID.(int)
 
 	var req struct {
-		Password string `json:"password"`
+		Password string `json:"password" binding:"required,min=6,max=72"`
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid request body")
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
-	if err := h.authService.UpdatePassword(uID, req.Password); err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.authService.UpdatePassword(c.Request.Context(), uID, req.Password); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -107,9 +114,9 @@ func (h *AuthHandler) Me(c *gin.Context) {
 	userID, _ := c.Get("user_id")
 	uID, _ := userID.(int)
 
-	user, err := h.authService.GetUserByID(uID)
+	user, err := h.authService.GetUserByID(c.Request.Context(), uID)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, "User not found")
+		c.Error(err)
 		return
 	}
 
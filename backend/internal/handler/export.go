@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"fmt"
+	"go-todolist/internal/export"
+	"go-todolist/internal/logging"
+	"go-todolist/internal/utils"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseExportOptions reads ?format= (csv by default) and ?columns=, which picks and
+// orders the columns like the list filters: repeated or comma separated
+func parseExportOptions[T any](c *gin.Context, available export.Columns[T]) (string, export.Columns[T], []utils.FieldError) {
+	var errs []utils.FieldError
+
+	format := c.DefaultQuery("format", export.Formats[0])
+	if !export.IsValidFormat(format) {
+		errs = append(errs, invalidChoice("format", export.Formats))
+	}
+
+	columns, ok := available.Select(queryList(c, "columns"))
+	if !ok {
+		errs = append(errs, invalidChoice("columns", available.Names()))
+	}
+
+	return format, columns, errs
+}
+
+// streamExport sends the records produced by each as a file attachment, writing them as
+// they come. A failure before anything reached the client is rendered as the usual
+// JSON error. Past that point the status is already sent, so the connection is dropped
+// instead to keep clients from taking a truncated file for a complete one.
+func streamExport[T any](c *gin.Context, name, format string, columns export.Columns[T], each func(fn func(*T) error) error) {
+	ctx := c.Request.Context()
+
+	// the server write timeout is sized for ordinary responses, an export runs until
+	// its request deadline instead (none when the export timeout is disabled)
+	deadline, _ := ctx.Deadline()
+	http.NewResponseController(c.Writer).SetWriteDeadline(deadline)
+
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().UTC().Format("20060102"), format)
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	w, err := export.NewWriter(c.Writer, format, columns)
+	if err == nil {
+		err = each(w.Write)
+	}
+	if err == nil {
+		err = w.Flush()
+	}
+	if err == nil {
+		return
+	}
+
+	if !c.Writer.Written() {
+		// the error is rendered as JSON, not as the file the headers announced
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		c.Error(err)
+		return
+	}
+
+	logging.FromContext(ctx).Error("export aborted", "route", c.FullPath(), "error", err)
+	panic(http.ErrAbortHandler)
+}
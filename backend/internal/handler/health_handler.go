@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"go-todolist/internal/health"
+	"go-todolist/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	checker *health.Checker
+}
+
+func NewHealthHandler(checker *health.Checker) *HealthHandler {
+	return &HealthHandler{
+		checker: checker,
+	}
+}
+
+// Live reports that the process is up, it never touches dependencies
+func (h *HealthHandler) Live(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "alive", gin.H{"status": "ok"})
+}
+
+// Ready reports whether the server can take traffic
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report, ready := h.checker.Ready(c.Request.Context())
+	if !ready {
+		c.JSON(http.StatusServiceUnavailable, utils.Response{
+			Success: false,
+			Error:   "not ready",
+			Code:    "not_ready",
+			Data:    report,
+		})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "ready", report)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"go-todolist/internal/service"
+	"go-todolist/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SavedFilterHandler struct {
+	savedFilterService *service.SavedFilterService
+}
+
+func NewSavedFilterHandler(savedFilterService *service.SavedFilterService) *SavedFilterHandler {
+	return &SavedFilterHandler{
+		savedFilterService: savedFilterService,
+	}
+}
+
+func (h *SavedFilterHandler) Create(c *gin.Context) {
+	var req service.SavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.savedFilterService.Create(c.Request.Context(), uID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Saved filter created successfully", response)
+}
+
+func (h *SavedFilterHandler) GetAll(c *gin.Context) {
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.savedFilterService.FindVisible(c.Request.Context(), uID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Saved filters retrieved successfully", response)
+}
+
+func (h *SavedFilterHandler) GetByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid filter ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.savedFilterService.FindByID(c.Request.Context(), id, uID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Saved filter retrieved successfully", response)
+}
+
+func (h *SavedFilterHandler) Update(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid filter ID")
+		return
+	}
+
+	var req service.SavedFilterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.savedFilterService.Update(c.Request.Context(), id, uID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Saved filter updated successfully", response)
+}
+
+func (h *SavedFilterHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid filter ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	if err := h.savedFilterService.Delete(c.Request.Context(), id, uID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Saved filter deleted successfully", nil)
+}
+
+func (h *SavedFilterHandler) Execute(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid filter ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.savedFilterService.Execute(c.Request.Context(), id, uID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tickets retrieved successfully", response)
+}
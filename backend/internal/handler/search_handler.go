@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"go-todolist/internal/domain"
+	"go-todolist/internal/service"
+	"go-todolist/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SearchHandler struct {
+	searchService *service.SearchService
+}
+
+func NewSearchHandler(searchService *service.SearchService) *SearchHandler {
+	return &SearchHandler{
+		searchService: searchService,
+	}
+}
+
+func (h *SearchHandler) Search(c *gin.Context) {
+	req := service.SearchRequest{
+		Query:  c.Query("q"),
+		Status: c.Query("status"),
+	}
+
+	if req.Status != "" && !domain.IsValidTicketStatus(req.Status) {
+		utils.ValidationErrorsResponse(c, []utils.FieldError{invalidChoice("status", domain.TicketStatuses)})
+		return
+	}
+
+	if raw := c.Query("assignee_id"); raw != "" {
+		assigneeID, err := strconv.Atoi(raw)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid assignee ID")
+			return
+		}
+		req.AssigneeID = &assigneeID
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			utils.ValidationErrorResponse(c, "Invalid limit")
+			return
+		}
+		req.Limit = limit
+	}
+
+	if req.Query == "" {
+		utils.ValidationErrorResponse(c, "Search query is required")
+		return
+	}
+
+	response, err := h.searchService.SearchTickets(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Search results retrieved successfully", response)
+}
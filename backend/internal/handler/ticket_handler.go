@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"encoding/json"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/export"
 	"go-todolist/internal/service"
 	"go-todolist/internal/utils"
-	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
@@ -23,21 +28,19 @@ func NewTicketHandler(ticketService *service.TicketService) *TicketHandler {
 func (h *TicketHandler) Create(c *gin.Context) {
 	var req service.TicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid request body")
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
-	log.Printf("Received ticket create request: %+v", req)
-
 	// Get user ID from middlewre (logic for this will be added/checked)
 	userID, _ := c.Get("user_id")
 	if id, ok := userID.(int); ok {
 		req.CreatorID = id
 	}
 
-	response, err := h.ticketService.CreateTicket(req)
+	response, err := h.ticketService.CreateTicket(c.Request.Context(), req)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
@@ -45,15 +48,41 @@ func (h *TicketHandler) Create(c *gin.Context) {
 }
 
 func (h *TicketHandler) GetAll(c *gin.Context) {
-	response, err := h.ticketService.FindAll()
+	filter, errs := parseTicketFilter(c)
+	if len(errs) > 0 {
+		utils.ValidationErrorsResponse(c, errs)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.ticketService.FindTickets(c.Request.Context(), filter, uID)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Tickets retrieved successfully", response)
 }
 
+// Export streams the tickets matching the listing filters as CSV or NDJSON
+func (h *TicketHandler) Export(c *gin.Context) {
+	filter, errs := parseTicketFilter(c)
+	format, columns, exportErrs := parseExportOptions(c, export.TicketColumns)
+	if errs = append(errs, exportErrs...); len(errs) > 0 {
+		utils.ValidationErrorsResponse(c, errs)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	streamExport(c, "tickets", format, columns, func(fn func(*domain.Ticket) error) error {
+		return h.ticketService.ExportTickets(c.Request.Context(), filter, uID, fn)
+	})
+}
+
 func (h *TicketHandler) GetByID(c *gin.Context) {
 	id, err := strconv.Atoi(c.Param("id"))
 	if err != nil {
@@ -61,9 +90,9 @@ func (h *TicketHandler) GetByID(c *gin.Context) {
 		return
 	}
 
-	response, err := h.ticketService.FindByID(id)
+	response, err := h.ticketService.FindByID(c.Request.Context(), id)
 	if err != nil {
-		utils.NotFoundResponse(c, "Ticket not found")
+		c.Error(err)
 		return
 	}
 
@@ -79,13 +108,56 @@ func (h *TicketHandler) Update(c *gin.Context) {
 
 	var req service.TicketRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid request body")
+		utils.BindingErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.ticketService.UpdateTicket(c.Request.Context(), id, req, uID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket updated successfully", response)
+}
+
+// Patch applies an RFC 7396 JSON Merge Patch, members left out of the document are
+// not touched and members set to null are cleared
+func (h *TicketHandler) Patch(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid ticket ID")
+		return
+	}
+
+	contentType := c.ContentType()
+	if contentType != "application/merge-patch+json" && contentType != "application/json" {
+		utils.ErrorResponse(c, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json")
+		return
+	}
+
+	var patch service.TicketPatch
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&patch); err != nil {
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
-	response, err := h.ticketService.UpdateTicket(id, req)
+	if errs := validateTicketPatch(patch); len(errs) > 0 {
+		utils.ValidationErrorsResponse(c, errs)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.ticketService.PatchTicket(c.Request.Context(), id, patch, uID)
 	if err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+		c.Error(err)
 		return
 	}
 
@@ -99,12 +171,63 @@ func (h *TicketHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	if err := h.ticketService.DeleteTicket(id); err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	if err := h.ticketService.DeleteTicket(c.Request.Context(), id, uID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ticket moved to trash successfully", nil)
+}
+
+func (h *TicketHandler) BulkUpdate(c *gin.Context) {
+	var req service.BulkTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BindingErrorResponse(c, err)
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.ticketService.BulkUpdate(c.Request.Context(), req, uID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Bulk update completed", response)
+}
+
+func (h *TicketHandler) GetTrash(c *gin.Context) {
+	response, err := h.ticketService.FindTrashed(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trashed tickets retrieved successfully", response)
+}
+
+func (h *TicketHandler) Restore(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ValidationErrorResponse(c, "Invalid ticket ID")
+		return
+	}
+
+	userID, _ := c.Get("user_id")
+	uID, _ := userID.(int)
+
+	response, err := h.ticketService.RestoreTicket(c.Request.Context(), id, uID)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Ticket deleted successfully", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Ticket restored successfully", response)
 }
 
 func (h *TicketHandler) UpdateStatus(c *gin.Context) {
@@ -115,20 +238,157 @@ func (h *TicketHandler) UpdateStatus(c *gin.Context) {
 	}
 
 	var req struct {
-		Status string `json:"status"`
+		Status string `json:"status" binding:"required,ticket_status"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		utils.ValidationErrorResponse(c, "Invalid request body")
+		utils.BindingErrorResponse(c, err)
 		return
 	}
 
 	userID, _ := c.Get("user_id")
 	uID, _ := userID.(int)
 
-	if err := h.ticketService.UpdateStatus(id, req.Status, uID); err != nil {
-		utils.InternalServerErrorResponse(c, err.Error())
+	if err := h.ticketService.UpdateStatus(c.Request.Context(), id, req.Status, uID); err != nil {
+		c.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Ticket status updated successfully", nil)
 }
+
+// parseTicketFilter reads listing criteria from the query string, list values can be
+// repeated (?status=Todo&status=Done) or comma separated (?status=Todo,Done)
+func parseTicketFilter(c *gin.Context) (domain.TicketFilter, []utils.FieldError) {
+	var filter domain.TicketFilter
+	var errs []utils.FieldError
+
+	filter.Statuses = queryList(c, "status")
+	for _, status := range filter.Statuses {
+		if !domain.IsValidTicketStatus(status) {
+			errs = append(errs, invalidChoice("status", domain.TicketStatuses))
+			break
+		}
+	}
+
+	filter.Priorities = queryList(c, "priority")
+	for _, priority := range filter.Priorities {
+		if !domain.IsValidTicketPriority(priority) {
+			errs = append(errs, invalidChoice("priority", domain.TicketPriorities))
+			break
+		}
+	}
+
+	errs = append(errs, parseIntParams(c, []intParam{
+		{"assignee_id", &filter.AssigneeID},
+		{"creator_id", &filter.CreatorID},
+		{"due_within_days", &filter.DueWithinDays},
+	})...)
+	errs = append(errs, parseDateParams(c, []dateParam{
+		{"due_after", &filter.DueAfter},
+		{"due_before", &filter.DueBefore},
+	})...)
+
+	if raw := c.Query("assigned_to_me"); raw != "" {
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: "assigned_to_me", Code: "invalid_type", Message: "must be a boolean"})
+		}
+		filter.AssignedToMe = value
+	}
+
+	return filter, errs
+}
+
+// intParam pairs an optional integer query parameter with the filter field it sets.
+// Parameters are passed as a slice so their field errors come out in a fixed order.
+type intParam struct {
+	key    string
+	target **int
+}
+
+func parseIntParams(c *gin.Context, params []intParam) []utils.FieldError {
+	var errs []utils.FieldError
+	for _, p := range params {
+		raw := c.Query(p.key)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: p.key, Code: "invalid_type", Message: "must be an integer"})
+			continue
+		}
+		*p.target = &value
+	}
+	return errs
+}
+
+// dateParam is intParam for dates, see parseDate for the accepted formats
+type dateParam struct {
+	key    string
+	target **time.Time
+}
+
+func parseDateParams(c *gin.Context, params []dateParam) []utils.FieldError {
+	var errs []utils.FieldError
+	for _, p := range params {
+		raw := c.Query(p.key)
+		if raw == "" {
+			continue
+		}
+		value, err := parseDate(raw)
+		if err != nil {
+			errs = append(errs, utils.FieldError{Field: p.key, Code: "invalid_type", Message: "must be a date (YYYY-MM-DD or RFC 3339)"})
+			continue
+		}
+		*p.target = &value
+	}
+	return errs
+}
+
+// validateTicketPatch checks the members present in a merge patch, absent members are skipped
+func validateTicketPatch(patch service.TicketPatch) []utils.FieldError {
+	var errs []utils.FieldError
+
+	if patch.Title.Set {
+		if patch.Title.Value == nil || strings.TrimSpace(*patch.Title.Value) == "" {
+			errs = append(errs, utils.FieldError{Field: "title", Code: "required", Message: "cannot be empty"})
+		} else if utf8.RuneCountInString(*patch.Title.Value) > 255 {
+			errs = append(errs, utils.FieldError{Field: "title", Code: "too_long", Message: "must be at most 255 characters"})
+		}
+	}
+	if patch.Status.Set && (patch.Status.Value == nil || !domain.IsValidTicketStatus(*patch.Status.Value)) {
+		errs = append(errs, invalidChoice("status", domain.TicketStatuses))
+	}
+	if patch.Priority.Set && (patch.Priority.Value == nil || !domain.IsValidTicketPriority(*patch.Priority.Value)) {
+		errs = append(errs, invalidChoice("priority", domain.TicketPriorities))
+	}
+	if patch.AssigneeID.Set && patch.AssigneeID.Value != nil && *patch.AssigneeID.Value <= 0 {
+		errs = append(errs, utils.FieldError{Field: "assignee_id", Code: "out_of_range", Message: "must be greater than 0"})
+	}
+
+	return errs
+}
+
+func invalidChoice(field string, choices []string) utils.FieldError {
+	return utils.FieldError{Field: field, Code: "invalid_choice", Message: "must be one of: " + strings.Join(choices, ", ")}
+}
+
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, v := range strings.Split(raw, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+	return values
+}
+
+func parseDate(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", raw)
+}
@@ -0,0 +1,124 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"go-todolist/internal/database"
+	"go-todolist/internal/repository/sqlite"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const checkTimeout = 2 * time.Second
+
+// Report is the outcome of a readiness check, Checks maps each dependency to "ok"
+// or to the reason it failed
+type Report struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Database is the storage a readiness check probes
+type Database interface {
+	Ping(ctx context.Context) error
+	PendingMigrations(ctx context.Context) ([]string, error)
+}
+
+// Postgres probes a PostgreSQL pool
+func Postgres(pool *pgxpool.Pool) Database {
+	return postgres{pool: pool}
+}
+
+type postgres struct {
+	pool *pgxpool.Pool
+}
+
+func (p postgres) Ping(ctx context.Context) error {
+	return p.pool.Ping(ctx)
+}
+
+func (p postgres) PendingMigrations(ctx context.Context) ([]string, error) {
+	return database.PendingMigrations(ctx, p.pool)
+}
+
+// SQLite probes a database opened by sqlite.Open
+func SQLite(db *sql.DB) Database {
+	return sqliteDB{db: db}
+}
+
+type sqliteDB struct {
+	db *sql.DB
+}
+
+func (s sqliteDB) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+func (s sqliteDB) PendingMigrations(ctx context.Context) ([]string, error) {
+	return sqlite.PendingMigrations(ctx, s.db)
+}
+
+// Checker decides whether the server should receive traffic
+type Checker struct {
+	db           Database
+	shuttingDown atomic.Bool
+}
+
+// NewChecker returns a checker for db, nil skips the database checks for the memory
+// storage that has no database
+func NewChecker(db Database) *Checker {
+	return &Checker{db: db}
+}
+
+// MarkShuttingDown makes every following readiness check fail so load balancers
+// stop routing new requests while in-flight ones finish
+func (c *Checker) MarkShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Ready checks that the server is not draining, the database answers and the schema
+// is up to date
+func (c *Checker) Ready(ctx context.Context) (Report, bool) {
+	report := Report{Status: "ok", Checks: map[string]string{}}
+	ready := true
+
+	fail := func(name, reason string) {
+		report.Checks[name] = reason
+		ready = false
+	}
+
+	if c.shuttingDown.Load() {
+		fail("shutdown", "server is shutting down")
+	} else {
+		report.Checks["shutdown"] = "ok"
+	}
+
+	if c.db != nil {
+		ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+		defer cancel()
+
+		if err := c.db.Ping(ctx); err != nil {
+			fail("database", "unreachable")
+		} else {
+			report.Checks["database"] = "ok"
+
+			pending, err := c.db.PendingMigrations(ctx)
+			switch {
+			case err != nil:
+				fail("migrations", "unable to read schema version")
+			case len(pending) > 0:
+				fail("migrations", "pending: "+strings.Join(pending, ", "))
+			default:
+				report.Checks["migrations"] = "ok"
+			}
+		}
+	}
+
+	if !ready {
+		report.Status = "unavailable"
+	}
+	return report, ready
+}
@@ -0,0 +1,180 @@
+package metrics
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	HTTPRequests = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total number of HTTP requests by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestDuration = promauto.With(Registry).NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds by method and route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	HTTPResponsesAborted = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "http_responses_aborted_total",
+		Help: "Responses cut off after they started, such as exports failing mid-stream, by method and route.",
+	}, []string{"method", "route"})
+
+	LoginAttempts = promauto.With(Registry).NewCounterVec(prometheus.CounterOpts{
+		Name: "auth_login_attempts_total",
+		Help: "Login attempts by result (success or failure).",
+	}, []string{"result"})
+)
+
+// ObserveRequest records one finished HTTP request
+func ObserveRequest(method, route string, status int, seconds float64) {
+	HTTPRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	HTTPRequestDuration.WithLabelValues(method, route).Observe(seconds)
+}
+
+// RegisterPoolStats exposes the connection pool counters of pool, calling it again
+// replaces the pool reported
+func RegisterPoolStats(pool *pgxpool.Pool) {
+	register(newPoolCollector(pool.Stat))
+}
+
+type poolMetric struct {
+	desc      *prometheus.Desc
+	valueType prometheus.ValueType
+	value     func(s *pgxpool.Stat) float64
+}
+
+// poolCollector reads one pool snapshot per scrape so the values are consistent
+// with each other
+type poolCollector struct {
+	stat    func() *pgxpool.Stat
+	metrics []poolMetric
+}
+
+func newPoolCollector(stat func() *pgxpool.Stat) *poolCollector {
+	gauge := func(name, help string, value func(s *pgxpool.Stat) float64) poolMetric {
+		return poolMetric{prometheus.NewDesc(name, help, nil, nil), prometheus.GaugeValue, value}
+	}
+	counter := func(name, help string, value func(s *pgxpool.Stat) float64) poolMetric {
+		return poolMetric{prometheus.NewDesc(name, help, nil, nil), prometheus.CounterValue, value}
+	}
+
+	return &poolCollector{stat: stat, metrics: []poolMetric{
+		gauge("db_pool_acquired_connections", "Connections currently checked out of the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.AcquiredConns()) }),
+		gauge("db_pool_idle_connections", "Idle connections in the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.IdleConns()) }),
+		gauge("db_pool_total_connections", "Total connections in the pool, including those being established.",
+			func(s *pgxpool.Stat) float64 { return float64(s.TotalConns()) }),
+		gauge("db_pool_max_connections", "Maximum size of the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.MaxConns()) }),
+		counter("db_pool_acquires_total", "Successful connection acquires from the pool.",
+			func(s *pgxpool.Stat) float64 { return float64(s.AcquireCount()) }),
+		counter("db_pool_empty_acquires_total", "Acquires that had to wait because the pool was empty.",
+			func(s *pgxpool.Stat) float64 { return float64(s.EmptyAcquireCount()) }),
+		counter("db_pool_acquire_duration_seconds_total", "Total time spent acquiring connections.",
+			func(s *pgxpool.Stat) float64 { return s.AcquireDuration().Seconds() }),
+		counter("db_pool_empty_acquire_wait_seconds_total", "Total time spent waiting for a connection when the pool was empty.",
+			func(s *pgxpool.Stat) float64 { return s.EmptyAcquireWaitTime().Seconds() }),
+	}}
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, m := range c.metrics {
+		ch <- m.desc
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.stat()
+	for _, m := range c.metrics {
+		ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, m.value(stat))
+	}
+}
+
+const (
+	// statusCountMaxAge is how long a ticket count is served before the next scrape
+	// queries the database again
+	statusCountMaxAge = 30 * time.Second
+	// statusCountTimeout bounds the count query run during a scrape
+	statusCountTimeout = 5 * time.Second
+)
+
+// RegisterTicketStatusGauge exposes the number of live tickets per status. The count
+// is cached for statusCountMaxAge so frequent or parallel scrapes do not each query
+// the database. Calling it again replaces the repository reported.
+func RegisterTicketStatusGauge(ticketRepo domain.TicketRepository) {
+	register(newStatusCollector(ticketRepo, statusCountMaxAge))
+}
+
+type statusCollector struct {
+	ticketRepo domain.TicketRepository
+	maxAge     time.Duration
+	desc       *prometheus.Desc
+
+	mu        sync.Mutex
+	counts    map[string]int
+	refreshed time.Time
+}
+
+func newStatusCollector(ticketRepo domain.TicketRepository, maxAge time.Duration) *statusCollector {
+	return &statusCollector{
+		ticketRepo: ticketRepo,
+		maxAge:     maxAge,
+		desc: prometheus.NewDesc("tickets_by_status",
+			"Number of tickets per status, excluding trashed tickets.", []string{"status"}, nil),
+	}
+}
+
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect reports every known status so empty columns show up as 0 instead of
+// disappearing
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.load()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for _, status := range domain.TicketStatuses {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[status]), status)
+	}
+	for status, count := range counts {
+		if !domain.IsValidTicketStatus(status) {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(count), status)
+		}
+	}
+}
+
+// load returns the cached counts, querying the repository when they are older than
+// maxAge. Concurrent scrapes wait for one query instead of running their own.
+func (c *statusCollector) load() (map[string]int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.counts != nil && time.Since(c.refreshed) < c.maxAge {
+		return c.counts, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), statusCountTimeout)
+	defer cancel()
+
+	counts, err := c.ticketRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.counts, c.refreshed = counts, time.Now()
+	return counts, nil
+}
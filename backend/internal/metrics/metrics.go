@@ -0,0 +1,55 @@
+// Package metrics holds the application's Prometheus metrics and serves them in the
+// exposition format through the Prometheus client library.
+package metrics
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry holds every metric exposed at /metrics, along with the Go runtime and
+// process collectors
+var Registry = prometheus.NewRegistry()
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler serves Registry. A collector that fails is logged and left out of the
+// response so one broken source does not hide the others.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{
+		ErrorLog:      errorLog{},
+		ErrorHandling: promhttp.ContinueOnError,
+	})
+}
+
+// register adds c to Registry, replacing the collector registered earlier for the
+// same metrics so setting up twice, as tests and a reopened pool do, does not panic
+func register(c prometheus.Collector) {
+	err := Registry.Register(c)
+	var existing prometheus.AlreadyRegisteredError
+	if errors.As(err, &existing) {
+		Registry.Unregister(existing.ExistingCollector)
+		err = Registry.Register(c)
+	}
+	if err != nil {
+		panic(fmt.Sprintf("metrics: %v", err))
+	}
+}
+
+// errorLog reports failed collections through slog
+type errorLog struct{}
+
+func (errorLog) Println(v ...any) {
+	slog.Warn("failed to collect metrics", "error", fmt.Sprint(v...))
+}
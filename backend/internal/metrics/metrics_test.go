@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/domain"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// countingTickets answers CountByStatus from counts and remembers how often it was asked
+type countingTickets struct {
+	domain.TicketRepository
+	counts map[string]int
+	err    error
+	calls  int
+}
+
+func (r *countingTickets) CountByStatus(context.Context) (map[string]int, error) {
+	r.calls++
+	return r.counts, r.err
+}
+
+func TestTicketStatusGauge(t *testing.T) {
+	repo := &countingTickets{counts: map[string]int{domain.StatusTodo: 2, "Archived": 1}}
+	collector := newStatusCollector(repo, time.Minute)
+
+	want := `
+# HELP tickets_by_status Number of tickets per status, excluding trashed tickets.
+# TYPE tickets_by_status gauge
+tickets_by_status{status="Archived"} 1
+tickets_by_status{status="Backlog"} 0
+tickets_by_status{status="Done"} 0
+tickets_by_status{status="In Progress"} 0
+tickets_by_status{status="Todo"} 2
+`
+	for range 3 {
+		if err := testutil.CollectAndCompare(collector, strings.NewReader(want)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if repo.calls != 1 {
+		t.Fatalf("counted %d times for 3 scrapes within max age", repo.calls)
+	}
+
+	collector.refreshed = time.Now().Add(-time.Hour)
+	repo.counts = map[string]int{domain.StatusDone: 5}
+	collector.Collect(make(chan prometheus.Metric, len(domain.TicketStatuses)))
+	if repo.calls != 2 {
+		t.Fatalf("stale count not refreshed, %d calls", repo.calls)
+	}
+}
+
+func TestHandlerKeepsServingWhenACollectorFails(t *testing.T) {
+	RegisterTicketStatusGauge(&countingTickets{err: errors.New("database is down")})
+	// registering again replaces the first collector instead of panicking
+	RegisterTicketStatusGauge(&countingTickets{err: errors.New("database is down")})
+	t.Cleanup(func() { RegisterTicketStatusGauge(&countingTickets{counts: map[string]int{}}) })
+
+	ObserveRequest("GET", "/api/tickets/:id", 404, 0.02)
+	LoginAttempts.WithLabelValues("failure").Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	if rec.Code != 200 || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("status %d, content type %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	for _, line := range []string{
+		`http_requests_total{method="GET",route="/api/tickets/:id",status="404"} 1`,
+		`http_request_duration_seconds_bucket{method="GET",route="/api/tickets/:id",le="0.025"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/api/tickets/:id"} 1`,
+		`auth_login_attempts_total{result="failure"} 1`,
+		`# TYPE go_goroutines gauge`,
+	} {
+		if !strings.Contains(string(body), line+"\n") {
+			t.Errorf("missing %q in\n%s", line, body)
+		}
+	}
+	if strings.Contains(string(body), "tickets_by_status") {
+		t.Error("failed collector reported values")
+	}
+}
@@ -1,14 +1,25 @@
 package middleware
 
 import (
+	"context"
+	"errors"
+	"go-todolist/internal/domain"
 	"go-todolist/internal/utils"
+	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// AuthMiddleware validates JWT token and sets user info in context
-func AuthMiddleware() gin.HandlerFunc {
+// UserLookup loads the account a token was issued to
+type UserLookup interface {
+	GetUserByID(ctx context.Context, userID int) (*domain.User, error)
+}
+
+// AuthMiddleware validates JWT token and sets user info in context. Tokens are
+// stateless, so the account is loaded on every request to refuse tokens of users
+// who were disabled or removed after the token was issued.
+func AuthMiddleware(users UserLookup) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Get token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -36,6 +47,24 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Check the account is still allowed in
+		user, err := users.GetUserByID(c.Request.Context(), claims.UserID)
+		if errors.Is(err, domain.ErrNotFound) {
+			utils.UnauthorizedResponse(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+		if user.Disabled() {
+			utils.CodedErrorResponse(c, http.StatusForbidden, "account_disabled", "This account has been disabled")
+			c.Abort()
+			return
+		}
+
 		// Set user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("username", claims.Username)
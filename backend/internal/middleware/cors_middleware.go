@@ -5,12 +5,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func CORSMiddleware() gin.HandlerFunc {
+// CORSMiddleware allows credentialed browser requests from the given origins. The list
+// must have passed config.Validate, which refuses "*" and origins without an http or
+// https scheme.
+func CORSMiddleware(allowedOrigins []string) gin.HandlerFunc {
 	config := cors.Config{
-		AllowOrigins:     []string{"http://localhost:3000", "http://localhost:3001", "http://localhost:5173"},
+		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "PATCH", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", RequestIDHeader, "traceparent"},
+		ExposeHeaders:    []string{"Content-Length", RequestIDHeader, "traceparent"},
 		AllowCredentials: true,
 	}
 
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/logging"
+	"go-todolist/internal/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusClientClosedRequest is the nginx convention for a client that hung up
+// before the response was ready
+const statusClientClosedRequest = 499
+
+// ErrorHandler renders the last error a handler attached with c.Error. Domain errors
+// are mapped to their status code and code, anything else becomes a generic 500 so
+// internal error text never reaches the client. Errors a request returns are logged
+// here and only here, the layers below wrap them. Failures that do not fail the
+// request, like a lost activity log entry, are logged where they happen.
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+
+		var domainErr *domain.Error
+		if errors.As(err, &domainErr) {
+			utils.CodedErrorResponse(c, statusForKind(domainErr.Kind), domainErr.Code, domainErr.Message)
+			return
+		}
+
+		logger := logging.FromContext(c.Request.Context())
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger.Warn("request timed out", "route", c.FullPath(), "error", err)
+			utils.CodedErrorResponse(c, http.StatusGatewayTimeout, "request_timeout", "Request timed out")
+			return
+		}
+
+		if errors.Is(err, context.Canceled) {
+			c.Status(statusClientClosedRequest)
+			return
+		}
+
+		logger.Error("internal error", "route", c.FullPath(), "error", err)
+		utils.CodedErrorResponse(c, http.StatusInternalServerError, "internal_error", "Internal server error")
+	}
+}
+
+func statusForKind(kind error) int {
+	switch kind {
+	case domain.ErrNotFound:
+		return http.StatusNotFound
+	case domain.ErrConflict:
+		return http.StatusConflict
+	case domain.ErrForbidden:
+		return http.StatusForbidden
+	case domain.ErrValidation:
+		return http.StatusBadRequest
+	case domain.ErrUnauthorized:
+		return http.StatusUnauthorized
+	default:
+		return http.StatusInternalServerError
+	}
+}
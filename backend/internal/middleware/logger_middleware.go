@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"go-todolist/internal/logging"
+	"go-todolist/internal/utils"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortedKey marks a request whose response RecoveryMiddleware cut off with
+// http.ErrAbortHandler
+const abortedKey = "response_aborted"
+
+// ResponseAborted reports whether the handler gave up on a response it had started
+func ResponseAborted(c *gin.Context) bool {
+	return c.GetBool(abortedKey)
+}
+
+// LoggerMiddleware writes one structured log line per request with its route, status,
+// latency and the authenticated user, server errors are logged at error level. It is
+// the outermost middleware with work after the handler, so it also passes an aborted
+// response on to net/http once it is logged.
+func LoggerMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"route", c.FullPath(),
+			"path", c.Request.URL.Path,
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+		}
+		if userID, ok := GetUserID(c); ok {
+			attrs = append(attrs, "user_id", userID)
+		}
+
+		aborted := ResponseAborted(c)
+		if aborted {
+			attrs = append(attrs, "aborted", true)
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case aborted, status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request completed", attrs...)
+
+		if aborted {
+			// net/http drops the connection without logging anything
+			panic(http.ErrAbortHandler)
+		}
+	}
+}
+
+// RecoveryMiddleware turns a panicking handler into a 500 response and logs the panic.
+// Streaming handlers panic with http.ErrAbortHandler when they fail after the response
+// has started. That request is marked instead, so the metrics, tracing and logging
+// middlewares still record it before LoggerMiddleware lets net/http drop the connection.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		if recovered == http.ErrAbortHandler {
+			c.Set(abortedKey, true)
+			c.Abort()
+			return
+		}
+		logging.FromContext(c.Request.Context()).Error("panic recovered", "panic", recovered, "route", c.FullPath())
+		utils.CodedErrorResponse(c, http.StatusInternalServerError, "internal_error", "Internal server error")
+		c.Abort()
+	})
+}
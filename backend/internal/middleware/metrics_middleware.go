@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"go-todolist/internal/metrics"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records request counts and latency per route. Requests that did not
+// match a route share one label so random paths cannot blow up the series count.
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		metrics.ObserveRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start).Seconds())
+		if ResponseAborted(c) {
+			metrics.HTTPResponsesAborted.WithLabelValues(c.Request.Method, route).Inc()
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware gives every request a deadline. The request context is passed down
+// to the repositories, so pgx aborts queries that outlive it or whose client went away.
+// Routes in overrides, keyed by their pattern such as "/api/tickets/export", get their
+// own timeout instead.
+func TimeoutMiddleware(timeout time.Duration, overrides map[string]time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := timeout
+		if override, ok := overrides[c.FullPath()]; ok {
+			timeout = override
+		}
+
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"go-todolist/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for each request with otelgin, continuing the
+// caller's trace when a W3C traceparent header is present. The span is tagged with the
+// request ID and its traceparent is echoed in the response so a client can look it up.
+// A response aborted mid-stream marks the span as failed.
+func TracingMiddleware(serviceName string) gin.HandlersChain {
+	return gin.HandlersChain{
+		otelgin.Middleware(serviceName, otelgin.WithPropagators(tracing.Propagator)),
+		func(c *gin.Context) {
+			ctx := c.Request.Context()
+			span := trace.SpanFromContext(ctx)
+			if requestID := c.GetString("request_id"); requestID != "" {
+				span.SetAttributes(attribute.String("request.id", requestID))
+			}
+			tracing.Propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+			c.Next()
+
+			if ResponseAborted(c) {
+				span.SetStatus(codes.Error, "response aborted")
+			}
+		},
+	}
+}
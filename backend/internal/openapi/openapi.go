@@ -0,0 +1,69 @@
+// Package openapi serves the OpenAPI 3 description of the HTTP API and a Redoc page
+// rendering it. The document is maintained by hand next to the router, the router
+// tests fail when a registered route is missing from it. The Redoc bundle is vendored
+// in redoc/ and served by the API itself so the page loads nothing from a CDN, run
+// go generate after changing redoc/VERSION and commit the bundle. The package does
+// not compile without it.
+package openapi
+
+//go:generate sh -c "curl -fsSL -o redoc/redoc.standalone.js https://cdn.redoc.ly/redoc/v$(cat redoc/VERSION)/bundles/redoc.standalone.js"
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+//go:embed openapi.json
+var document []byte
+
+//go:embed docs.html
+var docsPage []byte
+
+// redocBundle is the vendored Redoc release, see the go:generate line above
+//
+//go:embed redoc/redoc.standalone.js
+var redocBundle []byte
+
+// Document returns the raw OpenAPI JSON document
+func Document() []byte {
+	return document
+}
+
+// Spec is the part of the document needed to look up operations
+type Spec struct {
+	Paths map[string]map[string]json.RawMessage `json:"paths"`
+}
+
+// Parse decodes the embedded document
+func Parse() (*Spec, error) {
+	var spec Spec
+	if err := json.Unmarshal(document, &spec); err != nil {
+		return nil, err
+	}
+	return &spec, nil
+}
+
+// Handler serves the OpenAPI document
+func Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", document)
+	}
+}
+
+// DocsHandler serves the HTML API reference
+func DocsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+	}
+}
+
+// RedocHandler serves the Redoc bundle the docs page loads
+func RedocHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=86400")
+		c.Data(http.StatusOK, "text/javascript; charset=utf-8", redocBundle)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"go-todolist/internal/domain"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -17,7 +18,7 @@ func NewActivityLogRepository(db *pgxpool.Pool) domain.ActivityLogRepository {
 	return &activityLogRepository{db: db}
 }
 
-func (r *activityLogRepository) Create(log *domain.ActivityLog) error {
+func (r *activityLogRepository) Create(ctx context.Context, log *domain.ActivityLog) error {
 	query := `
 		INSERT INTO activity_logs (ticket_id, user_id, action, created_at)
 		VALUES ($1, $2, $3, $4)
@@ -25,7 +26,7 @@ func (r *activityLogRepository) Create(log *domain.ActivityLog) error {
 	`
 
 	err := r.db.QueryRow(
-		context.Background(),
+		ctx,
 		query,
 		log.TicketID,
 		log.UserID,
@@ -34,24 +35,39 @@ func (r *activityLogRepository) Create(log *domain.ActivityLog) error {
 	).Scan(&log.ID, &log.CreatedAt)
 
 	if err != nil {
-		return fmt.Errorf("failed to create activity log: %w", err)
+		return translateError(err, "failed to create activity log", nil)
 	}
 
 	return nil
 }
 
-func (r *activityLogRepository) FindAll() ([]domain.ActivityLog, error) {
-	query := `
+func (r *activityLogRepository) FindAll(ctx context.Context) ([]domain.ActivityLog, error) {
+	return r.list(ctx, `
 		SELECT al.id, al.ticket_id, al.user_id, u.username, al.action, al.created_at
 		FROM activity_logs al
 		JOIN users u ON al.user_id = u.id
-		ORDER BY al.created_at DESC
+		ORDER BY al.created_at DESC, al.id DESC
 		LIMIT 100
-	`
+	`)
+}
+
+// FindAfter orders by id alone so entries written in the same instant are never
+// skipped between pages
+func (r *activityLogRepository) FindAfter(ctx context.Context, afterID, limit int) ([]domain.ActivityLog, error) {
+	return r.list(ctx, `
+		SELECT al.id, al.ticket_id, al.user_id, u.username, al.action, al.created_at
+		FROM activity_logs al
+		JOIN users u ON al.user_id = u.id
+		WHERE al.id > $1
+		ORDER BY al.id
+		LIMIT $2
+	`, afterID, limit)
+}
 
-	rows, err := r.db.Query(context.Background(), query)
+func (r *activityLogRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.ActivityLog, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query activity logs: %w", err)
+		return nil, translateError(err, "failed to query activity logs", nil)
 	}
 	defer rows.Close()
 
@@ -67,10 +83,75 @@ func (r *activityLogRepository) FindAll() ([]domain.ActivityLog, error) {
 			&l.CreatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan activity log: %w", err)
+			return nil, translateError(err, "failed to scan activity log", nil)
 		}
 		logs = append(logs, l)
 	}
 
 	return logs, nil
 }
+
+// Stream calls fn for every activity log matching filter, newest first and without the
+// limit FindAll applies. Rows are read as they arrive.
+func (r *activityLogRepository) Stream(ctx context.Context, filter domain.ActivityLogFilter, fn func(*domain.ActivityLog) error) error {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.TicketID != nil {
+		addCondition("al.ticket_id = $%d", *filter.TicketID)
+	}
+	if filter.UserID != nil {
+		addCondition("al.user_id = $%d", *filter.UserID)
+	}
+	if filter.Since != nil {
+		addCondition("al.created_at >= $%d", *filter.Since)
+	}
+	if filter.Until != nil {
+		addCondition("al.created_at < $%d", *filter.Until)
+	}
+
+	query := `
+		SELECT al.id, al.ticket_id, al.user_id, u.username, al.action, al.created_at
+		FROM activity_logs al
+		JOIN users u ON al.user_id = u.id
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY al.created_at DESC, al.id DESC"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "failed to query activity logs", nil)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l domain.ActivityLog
+		err := rows.Scan(
+			&l.ID,
+			&l.TicketID,
+			&l.UserID,
+			&l.Username,
+			&l.Action,
+			&l.CreatedAt,
+		)
+		if err != nil {
+			return translateError(err, "failed to scan activity log", nil)
+		}
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return translateError(err, "failed to read activity logs", nil)
+	}
+
+	return nil
+}
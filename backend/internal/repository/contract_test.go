@@ -0,0 +1,45 @@
+package repository_test
+
+import (
+	"context"
+	"go-todolist/internal/config"
+	"go-todolist/internal/database"
+	"go-todolist/internal/repository"
+	"go-todolist/internal/repository/repotest"
+	"os"
+	"testing"
+)
+
+// TestRepositoryContract runs the shared suite against a real PostgreSQL database. It
+// needs TEST_DATABASE_URL and wipes every table in that database between tests.
+func TestRepositoryContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(dsn, config.DatabaseConfig{MaxConns: 4})
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	if err := database.Migrate(ctx, pool); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		_, err := pool.Exec(ctx, "TRUNCATE users, todos, tickets, activity_logs, saved_filters RESTART IDENTITY CASCADE")
+		if err != nil {
+			t.Fatalf("truncate: %v", err)
+		}
+		return repotest.Repositories{
+			Users:        repository.NewUserRepository(pool),
+			Tickets:      repository.NewTicketRepository(pool),
+			Todos:        repository.NewTodoRepository(pool),
+			ActivityLogs: repository.NewActivityLogRepository(pool),
+			SavedFilters: repository.NewSavedFilterRepository(pool),
+		}
+	})
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"go-todolist/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+// translateError turns pgx errors into domain errors: missing rows become notFound,
+// constraint violations become conflict or validation errors, anything else is
+// wrapped with op and left for the caller to log, ErrorHandler does for requests
+func translateError(err error, op string, notFound *domain.Error) error {
+	if errors.Is(err, pgx.ErrNoRows) && notFound != nil {
+		return notFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case pgUniqueViolation:
+			return &domain.Error{Kind: domain.ErrConflict, Code: "already_exists", Message: "Resource already exists", Err: err}
+		case pgForeignKeyViolation:
+			return &domain.Error{Kind: domain.ErrValidation, Code: "invalid_reference", Message: "Referenced record does not exist", Err: err}
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"slices"
+	"sort"
+	"time"
+)
+
+// activityLogLimit matches the LIMIT of the Postgres FindAll query
+const activityLogLimit = 100
+
+type activityLogRepository struct {
+	store *Store
+}
+
+func NewActivityLogRepository(store *Store) domain.ActivityLogRepository {
+	return &activityLogRepository{store: store}
+}
+
+func (r *activityLogRepository) Create(ctx context.Context, log *domain.ActivityLog) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.store.insertLog(log, now())
+}
+
+func (r *activityLogRepository) FindAll(ctx context.Context) ([]domain.ActivityLog, error) {
+	logs := r.list(domain.ActivityLogFilter{})
+	if len(logs) > activityLogLimit {
+		logs = logs[:activityLogLimit]
+	}
+
+	return logs, nil
+}
+
+// FindAfter orders by id alone so entries written in the same instant are never
+// skipped between pages
+func (r *activityLogRepository) FindAfter(ctx context.Context, afterID, limit int) ([]domain.ActivityLog, error) {
+	logs := r.list(domain.ActivityLogFilter{})
+	slices.SortFunc(logs, func(a, b domain.ActivityLog) int { return a.ID - b.ID })
+
+	var page []domain.ActivityLog
+	for _, l := range logs {
+		if len(page) == limit {
+			break
+		}
+		if l.ID > afterID {
+			page = append(page, l)
+		}
+	}
+	return page, nil
+}
+
+// Stream calls fn for every activity log matching filter, newest first and without the
+// limit FindAll applies. The matches are copied first so fn runs without holding the
+// store lock.
+func (r *activityLogRepository) Stream(ctx context.Context, filter domain.ActivityLogFilter, fn func(*domain.ActivityLog) error) error {
+	logs := r.list(filter)
+	for i := range logs {
+		if err := fn(&logs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// list returns the logs matching filter, newest first
+func (r *activityLogRepository) list(filter domain.ActivityLogFilter) []domain.ActivityLog {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var logs []domain.ActivityLog
+	for _, l := range r.store.logs {
+		if filter.TicketID != nil && (l.TicketID == nil || *l.TicketID != *filter.TicketID) {
+			continue
+		}
+		if filter.UserID != nil && l.UserID != *filter.UserID {
+			continue
+		}
+		if filter.Since != nil && l.CreatedAt.Before(*filter.Since) {
+			continue
+		}
+		if filter.Until != nil && !l.CreatedAt.Before(*filter.Until) {
+			continue
+		}
+
+		l.TicketID = cloneInt(l.TicketID)
+		l.Username = r.store.username(l.UserID)
+		logs = append(logs, l)
+	}
+
+	sort.Slice(logs, func(i, j int) bool {
+		if !logs[i].CreatedAt.Equal(logs[j].CreatedAt) {
+			return logs[i].CreatedAt.After(logs[j].CreatedAt)
+		}
+		return logs[i].ID > logs[j].ID
+	})
+
+	return logs
+}
+
+// insertLog checks references and stores log, the caller holds the write lock
+func (s *Store) insertLog(log *domain.ActivityLog, createdAt time.Time) error {
+	if !s.userExists(log.UserID) {
+		return errInvalidReference
+	}
+	if log.TicketID != nil {
+		if _, ok := s.tickets[*log.TicketID]; !ok {
+			return errInvalidReference
+		}
+	}
+
+	log.ID = s.nextID("activity_logs")
+	log.CreatedAt = createdAt
+
+	stored := *log
+	stored.TicketID = cloneInt(log.TicketID)
+	stored.Username = ""
+	s.logs[log.ID] = stored
+
+	return nil
+}
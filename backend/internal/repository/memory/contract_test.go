@@ -0,0 +1,19 @@
+package memory
+
+import (
+	"go-todolist/internal/repository/repotest"
+	"testing"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		store := NewStore()
+		return repotest.Repositories{
+			Users:        NewUserRepository(store),
+			Tickets:      NewTicketRepository(store),
+			Todos:        NewTodoRepository(store),
+			ActivityLogs: NewActivityLogRepository(store),
+			SavedFilters: NewSavedFilterRepository(store),
+		}
+	})
+}
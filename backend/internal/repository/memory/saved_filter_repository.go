@@ -0,0 +1,116 @@
+package memory
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"sort"
+)
+
+type savedFilterRepository struct {
+	store *Store
+}
+
+func NewSavedFilterRepository(store *Store) domain.SavedFilterRepository {
+	return &savedFilterRepository{store: store}
+}
+
+func (r *savedFilterRepository) Create(ctx context.Context, filter *domain.SavedFilter) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(filter.UserID) {
+		return errInvalidReference
+	}
+
+	now := now()
+	filter.ID = r.store.nextID("saved_filters")
+	filter.CreatedAt = now
+	filter.UpdatedAt = now
+	r.store.filters[filter.ID] = cloneSavedFilter(*filter)
+
+	return nil
+}
+
+func (r *savedFilterRepository) FindByID(ctx context.Context, id int) (*domain.SavedFilter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	f, ok := r.store.filters[id]
+	if !ok {
+		return nil, domain.ErrSavedFilterNotFound
+	}
+
+	f = cloneSavedFilter(f)
+	f.Username = r.store.username(f.UserID)
+	return &f, nil
+}
+
+func (r *savedFilterRepository) FindVisibleTo(ctx context.Context, userID int) ([]domain.SavedFilter, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var filters []domain.SavedFilter
+	for _, f := range r.store.filters {
+		if f.UserID != userID && !f.Shared {
+			continue
+		}
+		f = cloneSavedFilter(f)
+		f.Username = r.store.username(f.UserID)
+		filters = append(filters, f)
+	}
+
+	sort.Slice(filters, func(i, j int) bool {
+		if filters[i].Name != filters[j].Name {
+			return filters[i].Name < filters[j].Name
+		}
+		return filters[i].ID < filters[j].ID
+	})
+
+	return filters, nil
+}
+
+func (r *savedFilterRepository) Update(ctx context.Context, filter *domain.SavedFilter) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.filters[filter.ID]
+	if !ok {
+		return domain.ErrSavedFilterNotFound
+	}
+
+	// only name, criteria and shared are writable, like the UPDATE statement
+	existing.Name = filter.Name
+	existing.Criteria = filter.Criteria
+	existing.Shared = filter.Shared
+	existing.UpdatedAt = now()
+	filter.UpdatedAt = existing.UpdatedAt
+	r.store.filters[filter.ID] = cloneSavedFilter(existing)
+
+	return nil
+}
+
+func (r *savedFilterRepository) Delete(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.filters[id]; !ok {
+		return domain.ErrSavedFilterNotFound
+	}
+	delete(r.store.filters, id)
+
+	return nil
+}
+
+// cloneSavedFilter copies the criteria so callers can't modify stored rows
+func cloneSavedFilter(f domain.SavedFilter) domain.SavedFilter {
+	c := f.Criteria
+	c.Statuses = append([]string(nil), c.Statuses...)
+	c.Priorities = append([]string(nil), c.Priorities...)
+	c.AssigneeID = cloneInt(c.AssigneeID)
+	c.CreatorID = cloneInt(c.CreatorID)
+	c.DueWithinDays = cloneInt(c.DueWithinDays)
+	c.DueAfter = cloneTime(c.DueAfter)
+	c.DueBefore = cloneTime(c.DueBefore)
+	f.Criteria = c
+	return f
+}
@@ -0,0 +1,60 @@
+package memory
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/repository/textsearch"
+	"sort"
+)
+
+// Search approximates the Postgres full-text search with prefix matching on words of
+// the title and description
+func (r *ticketRepository) Search(ctx context.Context, params domain.TicketSearchParams) ([]domain.TicketSearchResult, error) {
+	terms := textsearch.Terms(params.Query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var results []domain.TicketSearchResult
+	for _, t := range r.store.tickets {
+		if t.DeletedAt != nil {
+			continue
+		}
+		if params.Status != "" && t.Status != params.Status {
+			continue
+		}
+		if params.AssigneeID != nil && (t.AssigneeID == nil || *t.AssigneeID != *params.AssigneeID) {
+			continue
+		}
+
+		match, ok := textsearch.Match(terms, t.Title, t.Description)
+		if !ok {
+			continue
+		}
+		results = append(results, domain.TicketSearchResult{
+			Ticket:         r.joined(t),
+			Rank:           match.Rank,
+			TitleHighlight: match.TitleHighlight,
+			Snippet:        match.Snippet,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		if !results[i].UpdatedAt.Equal(results[j].UpdatedAt) {
+			return results[i].UpdatedAt.After(results[j].UpdatedAt)
+		}
+		return results[i].ID > results[j].ID
+	})
+
+	if limit := max(params.Limit, 0); len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
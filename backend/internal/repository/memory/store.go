@@ -0,0 +1,87 @@
+// Package memory implements the domain repositories on top of plain maps guarded by a
+// mutex. It mirrors the PostgreSQL repositories closely enough to run the whole server
+// without a database (STORAGE=memory) and to back tests. Nothing is persisted.
+package memory
+
+import (
+	"go-todolist/internal/domain"
+	"sync"
+	"time"
+)
+
+// errInvalidReference is what the Postgres repositories return on a foreign key violation
+var errInvalidReference = &domain.Error{Kind: domain.ErrValidation, Code: "invalid_reference", Message: "Referenced record does not exist"}
+
+// Store holds every table. Repositories created from the same Store see each other's
+// rows, which is how usernames are joined and references are checked.
+type Store struct {
+	mu sync.RWMutex
+
+	users    map[int]domain.User
+	tickets  map[int]domain.Ticket
+	todos    map[int]domain.Todo
+	logs     map[int]domain.ActivityLog
+	filters  map[int]domain.SavedFilter
+	sequence map[string]int
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:    make(map[int]domain.User),
+		tickets:  make(map[int]domain.Ticket),
+		todos:    make(map[int]domain.Todo),
+		logs:     make(map[int]domain.ActivityLog),
+		filters:  make(map[int]domain.SavedFilter),
+		sequence: make(map[string]int),
+	}
+}
+
+// nextID works like a SERIAL column, ids are never reused. Callers hold s.mu.
+func (s *Store) nextID(table string) int {
+	s.sequence[table]++
+	return s.sequence[table]
+}
+
+// now matches what a TIMESTAMP column stores: UTC with microsecond precision
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func (s *Store) userExists(id int) bool {
+	_, ok := s.users[id]
+	return ok
+}
+
+func (s *Store) username(id int) string {
+	return s.users[id].Username
+}
+
+// cloneUser copies the pointer fields so callers never share memory with the store
+func cloneUser(u domain.User) domain.User {
+	u.DisabledAt = cloneTime(u.DisabledAt)
+	return u
+}
+
+func cloneInt(p *int) *int {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneTime(p *time.Time) *time.Time {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+func cloneString(p *string) *string {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
@@ -0,0 +1,79 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/domain"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentTicketCreates(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	users := NewUserRepository(store)
+	tickets := NewTicketRepository(store)
+
+	user := &domain.User{Username: "alice", Password: "hash"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := tickets.Create(ctx, &domain.Ticket{Title: "t", CreatorID: user.ID}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	all, err := tickets.FindAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 50 {
+		t.Fatalf("got %d tickets, want 50", len(all))
+	}
+
+	seen := make(map[int]bool)
+	for _, ticket := range all {
+		if seen[ticket.ID] {
+			t.Fatalf("duplicate id %d", ticket.ID)
+		}
+		seen[ticket.ID] = true
+	}
+}
+
+func TestPatchManyIsAtomic(t *testing.T) {
+	ctx := context.Background()
+	store := NewStore()
+	users := NewUserRepository(store)
+	tickets := NewTicketRepository(store)
+
+	user := &domain.User{Username: "alice", Password: "hash"}
+	if err := users.Create(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	ticket := &domain.Ticket{Title: "first", CreatorID: user.ID}
+	if err := tickets.Create(ctx, ticket); err != nil {
+		t.Fatal(err)
+	}
+
+	done := domain.StatusDone
+	err := tickets.PatchMany(ctx, []int{ticket.ID, 999}, domain.TicketChanges{Status: &done}, nil, domain.AnyEditor)
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("got %v, want not found", err)
+	}
+
+	stored, err := tickets.FindByID(ctx, ticket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Status != domain.StatusBacklog {
+		t.Fatalf("status changed to %q although the batch failed", stored.Status)
+	}
+}
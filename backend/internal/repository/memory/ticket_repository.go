@@ -0,0 +1,447 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"go-todolist/internal/domain"
+	"sort"
+	"time"
+)
+
+type ticketRepository struct {
+	store *Store
+}
+
+func NewTicketRepository(store *Store) domain.TicketRepository {
+	return &ticketRepository{store: store}
+}
+
+func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.checkReferences(ticket); err != nil {
+		return err
+	}
+
+	now := now()
+	ticket.CreatedAt = now
+	ticket.UpdatedAt = now
+
+	if ticket.Status == "" {
+		ticket.Status = domain.StatusBacklog
+	}
+	if ticket.Priority == "" {
+		ticket.Priority = domain.PriorityMedium
+	}
+
+	ticket.ID = r.store.nextID("tickets")
+	r.store.tickets[ticket.ID] = r.stored(*ticket)
+
+	return nil
+}
+
+func (r *ticketRepository) FindAll(ctx context.Context) ([]domain.Ticket, error) {
+	return r.list(func(t *domain.Ticket) bool { return true }), nil
+}
+
+// FindByFilter lists tickets matching every criterion set on filter. AssignedToMe and
+// DueWithinDays are relative and must be resolved by the caller before reaching here.
+func (r *ticketRepository) FindByFilter(ctx context.Context, filter domain.TicketFilter) ([]domain.Ticket, error) {
+	return r.list(func(t *domain.Ticket) bool {
+		if len(filter.Statuses) > 0 && !contains(filter.Statuses, t.Status) {
+			return false
+		}
+		if len(filter.Priorities) > 0 && !contains(filter.Priorities, t.Priority) {
+			return false
+		}
+		if filter.AssigneeID != nil && (t.AssigneeID == nil || *t.AssigneeID != *filter.AssigneeID) {
+			return false
+		}
+		if filter.CreatorID != nil && t.CreatorID != *filter.CreatorID {
+			return false
+		}
+		if filter.DueAfter != nil && (t.DueDate == nil || t.DueDate.Before(*filter.DueAfter)) {
+			return false
+		}
+		if filter.DueBefore != nil && (t.DueDate == nil || !t.DueDate.Before(*filter.DueBefore)) {
+			return false
+		}
+		return true
+	}), nil
+}
+
+func (r *ticketRepository) FindByStatus(ctx context.Context, status string) ([]domain.Ticket, error) {
+	return r.list(func(t *domain.Ticket) bool { return t.Status == status }), nil
+}
+
+// StreamByFilter calls fn for every ticket FindByFilter would return. The matches are
+// copied first so fn runs without holding the store lock.
+func (r *ticketRepository) StreamByFilter(ctx context.Context, filter domain.TicketFilter, fn func(*domain.Ticket) error) error {
+	tickets, err := r.FindByFilter(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	for i := range tickets {
+		if err := fn(&tickets[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ticketRepository) FindByID(ctx context.Context, id int) (*domain.Ticket, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	t, ok := r.store.tickets[id]
+	if !ok || t.DeletedAt != nil {
+		return nil, domain.ErrTicketNotFound
+	}
+
+	t = r.joined(t)
+	t.DeletedAt = nil
+	return &t, nil
+}
+
+func (r *ticketRepository) Update(ctx context.Context, ticket *domain.Ticket, editorID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, err := r.editable(ticket.ID, false, editorID, domain.ErrTicketNotFound); err != nil {
+		return err
+	}
+	return r.update(ticket, now())
+}
+
+// editable returns the ticket id refers to when it is trashed or live as expected and
+// editorID may modify it, the caller holds the write lock
+func (r *ticketRepository) editable(id int, trashed bool, editorID int, missing error) (domain.Ticket, error) {
+	t, ok := r.store.tickets[id]
+	if !ok || (t.DeletedAt != nil) != trashed {
+		return t, missing
+	}
+	if !t.EditableBy(editorID) {
+		return t, domain.ErrTicketForbidden
+	}
+	return t, nil
+}
+
+func (r *ticketRepository) Patch(ctx context.Context, id int, changes domain.TicketChanges, editorID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, err := r.editable(id, false, editorID, domain.ErrTicketNotFound)
+	if err != nil {
+		return err
+	}
+
+	changes.Apply(&existing)
+	if err := r.checkReferences(&existing); err != nil {
+		return err
+	}
+	existing.UpdatedAt = now()
+	r.store.tickets[id] = existing
+
+	return nil
+}
+
+func (r *ticketRepository) Delete(ctx context.Context, id int, editorID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, err := r.editable(id, false, editorID, domain.ErrTicketNotFound)
+	if err != nil {
+		return err
+	}
+
+	deletedAt := now()
+	t.DeletedAt = &deletedAt
+	r.store.tickets[id] = t
+
+	return nil
+}
+
+func (r *ticketRepository) FindTrashed(ctx context.Context) ([]domain.Ticket, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tickets []domain.Ticket
+	for _, t := range r.store.tickets {
+		if t.DeletedAt != nil {
+			tickets = append(tickets, r.joined(t))
+		}
+	}
+
+	sort.Slice(tickets, func(i, j int) bool {
+		if !tickets[i].DeletedAt.Equal(*tickets[j].DeletedAt) {
+			return tickets[i].DeletedAt.After(*tickets[j].DeletedAt)
+		}
+		return tickets[i].ID > tickets[j].ID
+	})
+
+	return tickets, nil
+}
+
+func (r *ticketRepository) Restore(ctx context.Context, id int, editorID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, err := r.editable(id, true, editorID, domain.NewNotFoundError("ticket_not_in_trash", "Ticket not found in trash"))
+	if err != nil {
+		return err
+	}
+
+	t.DeletedAt = nil
+	t.UpdatedAt = now()
+	r.store.tickets[id] = t
+
+	return nil
+}
+
+// Purge removes trashed tickets for good, their activity logs are kept without the
+// ticket reference like the ON DELETE SET NULL in the schema
+func (r *ticketRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var purged int64
+	for id, t := range r.store.tickets {
+		if t.DeletedAt == nil || !t.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+		delete(r.store.tickets, id)
+		purged++
+
+		for logID, l := range r.store.logs {
+			if l.TicketID != nil && *l.TicketID == id {
+				l.TicketID = nil
+				r.store.logs[logID] = l
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+func (r *ticketRepository) UpdateStatus(ctx context.Context, id int, status string, editorID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	t, err := r.editable(id, false, editorID, domain.ErrTicketNotFound)
+	if err != nil {
+		return err
+	}
+
+	t.Status = status
+	t.UpdatedAt = now()
+	r.store.tickets[id] = t
+
+	return nil
+}
+
+// PatchMany applies the same changes to every ticket in ids and stores logs
+// atomically, everything is checked before the first write so either all of them
+// are stored or none are
+func (r *ticketRepository) PatchMany(ctx context.Context, ids []int, changes domain.TicketChanges, logs []*domain.ActivityLog, editorID int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	patched := make([]domain.Ticket, len(ids))
+	for i, id := range ids {
+		t, err := r.editable(id, false, editorID, domain.NewNotFoundError("ticket_not_found", fmt.Sprintf("Ticket %d not found", id)))
+		if err != nil {
+			return err
+		}
+		changes.Apply(&t)
+		if err := r.checkReferences(&t); err != nil {
+			return err
+		}
+		patched[i] = t
+	}
+	for _, log := range logs {
+		if !r.store.userExists(log.UserID) {
+			return errInvalidReference
+		}
+		if log.TicketID != nil {
+			if _, ok := r.store.tickets[*log.TicketID]; !ok {
+				return errInvalidReference
+			}
+		}
+	}
+
+	now := now()
+	for _, t := range patched {
+		t.UpdatedAt = now
+		r.store.tickets[t.ID] = t
+	}
+	for _, log := range logs {
+		if err := r.store.insertLog(log, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Import stores tickets and their history with the timestamps they carry, filling
+// in the ids. Every reference is checked before the first write.
+func (r *ticketRepository) Import(ctx context.Context, tickets []domain.TicketImport) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, item := range tickets {
+		if err := r.checkReferences(item.Ticket); err != nil {
+			return err
+		}
+		for _, log := range item.History {
+			if !r.store.userExists(log.UserID) {
+				return errInvalidReference
+			}
+		}
+	}
+
+	for _, item := range tickets {
+		ticket := item.Ticket
+		if ticket.Status == "" {
+			ticket.Status = domain.StatusBacklog
+		}
+		if ticket.Priority == "" {
+			ticket.Priority = domain.PriorityMedium
+		}
+		ticket.CreatedAt = ticket.CreatedAt.UTC().Truncate(time.Microsecond)
+		ticket.UpdatedAt = ticket.UpdatedAt.UTC().Truncate(time.Microsecond)
+
+		ticket.ID = r.store.nextID("tickets")
+		r.store.tickets[ticket.ID] = r.stored(*ticket)
+
+		for _, log := range item.History {
+			log.TicketID = &ticket.ID
+			if err := r.store.insertLog(log, log.CreatedAt.UTC().Truncate(time.Microsecond)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// CountByStatus returns the number of live tickets per status
+func (r *ticketRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, t := range r.store.tickets {
+		if t.DeletedAt == nil {
+			counts[t.Status]++
+		}
+	}
+
+	return counts, nil
+}
+
+func (r *ticketRepository) CountTrashed(ctx context.Context) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, t := range r.store.tickets {
+		if t.DeletedAt != nil {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// update writes the mutable columns of a live ticket, the caller holds the write lock
+func (r *ticketRepository) update(ticket *domain.Ticket, updatedAt time.Time) error {
+	existing, ok := r.store.tickets[ticket.ID]
+	if !ok || existing.DeletedAt != nil {
+		return domain.ErrTicketNotFound
+	}
+	if err := r.checkReferences(ticket); err != nil {
+		return err
+	}
+
+	ticket.UpdatedAt = updatedAt
+
+	existing.Title = ticket.Title
+	existing.Description = ticket.Description
+	existing.Status = ticket.Status
+	existing.Priority = ticket.Priority
+	existing.DueDate = cloneTime(ticket.DueDate)
+	existing.AssigneeID = cloneInt(ticket.AssigneeID)
+	existing.UpdatedAt = updatedAt
+	r.store.tickets[ticket.ID] = existing
+
+	return nil
+}
+
+func (r *ticketRepository) checkReferences(ticket *domain.Ticket) error {
+	if !r.store.userExists(ticket.CreatorID) {
+		return errInvalidReference
+	}
+	if ticket.AssigneeID != nil && !r.store.userExists(*ticket.AssigneeID) {
+		return errInvalidReference
+	}
+	return nil
+}
+
+// list returns live tickets accepted by match, newest first
+func (r *ticketRepository) list(match func(t *domain.Ticket) bool) []domain.Ticket {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var tickets []domain.Ticket
+	for _, t := range r.store.tickets {
+		if t.DeletedAt == nil && match(&t) {
+			tickets = append(tickets, r.joined(t))
+		}
+	}
+
+	sortNewestFirst(tickets)
+	return tickets
+}
+
+// stored copies the columns a tickets row holds, dropping joined usernames
+func (r *ticketRepository) stored(t domain.Ticket) domain.Ticket {
+	t.AssigneeID = cloneInt(t.AssigneeID)
+	t.DueDate = cloneTime(t.DueDate)
+	t.DeletedAt = cloneTime(t.DeletedAt)
+	t.CreatorUsername = ""
+	t.AssigneeUsername = nil
+	return t
+}
+
+// joined returns a copy of t with creator and assignee usernames filled in
+func (r *ticketRepository) joined(t domain.Ticket) domain.Ticket {
+	t = r.stored(t)
+	t.CreatorUsername = r.store.username(t.CreatorID)
+	if t.AssigneeID != nil {
+		if u, ok := r.store.users[*t.AssigneeID]; ok {
+			t.AssigneeUsername = cloneString(&u.Username)
+		}
+	}
+	return t
+}
+
+func sortNewestFirst(tickets []domain.Ticket) {
+	sort.Slice(tickets, func(i, j int) bool {
+		if !tickets[i].CreatedAt.Equal(tickets[j].CreatedAt) {
+			return tickets[i].CreatedAt.After(tickets[j].CreatedAt)
+		}
+		return tickets[i].ID > tickets[j].ID
+	})
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
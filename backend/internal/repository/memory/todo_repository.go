@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"sort"
+)
+
+type todoRepository struct {
+	store *Store
+}
+
+func NewTodoRepository(store *Store) domain.TodoRepository {
+	return &todoRepository{store: store}
+}
+
+func (r *todoRepository) Create(ctx context.Context, todo *domain.Todo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if !r.store.userExists(todo.UserID) {
+		return errInvalidReference
+	}
+
+	now := now()
+	todo.ID = r.store.nextID("todos")
+	todo.CreatedAt = now
+	todo.UpdatedAt = now
+	r.store.todos[todo.ID] = *todo
+
+	return nil
+}
+
+func (r *todoRepository) FindAll(ctx context.Context) ([]domain.Todo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var todos []domain.Todo
+	for _, t := range r.store.todos {
+		todos = append(todos, t)
+	}
+
+	sort.Slice(todos, func(i, j int) bool {
+		return todos[i].ID < todos[j].ID
+	})
+
+	return todos, nil
+}
+
+func (r *todoRepository) FindById(ctx context.Context, id int) (*domain.Todo, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	todo, ok := r.store.todos[id]
+	if !ok {
+		return nil, domain.ErrTodoNotFound
+	}
+
+	return &todo, nil
+}
+
+func (r *todoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.todos[todo.ID]
+	if !ok {
+		return domain.ErrTodoNotFound
+	}
+	if !r.store.userExists(todo.UserID) {
+		return errInvalidReference
+	}
+
+	todo.CreatedAt = existing.CreatedAt
+	todo.UpdatedAt = now()
+	r.store.todos[todo.ID] = *todo
+
+	return nil
+}
+
+func (r *todoRepository) Delete(ctx context.Context, id int) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.todos[id]; !ok {
+		return domain.ErrTodoNotFound
+	}
+	delete(r.store.todos, id)
+
+	return nil
+}
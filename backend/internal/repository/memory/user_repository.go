@@ -0,0 +1,140 @@
+package memory
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"sort"
+	"time"
+)
+
+type userRepository struct {
+	store *Store
+}
+
+func NewUserRepository(store *Store) domain.UserRepository {
+	return &userRepository{store: store}
+}
+
+var errUsernameTaken = domain.NewConflictError("username_taken", "Username already exists")
+
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.findByUsername(user.Username) != nil {
+		return errUsernameTaken
+	}
+
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+
+	now := now()
+	user.ID = r.store.nextID("users")
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	r.store.users[user.ID] = cloneUser(*user)
+
+	return nil
+}
+
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user := r.findByUsername(username)
+	if user == nil {
+		return nil, domain.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, domain.ErrUserNotFound
+	}
+
+	user = cloneUser(user)
+	return &user, nil
+}
+
+// FindAll leaves out password hashes, like the Postgres implementation
+func (r *userRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var users []domain.User
+	for _, u := range r.store.users {
+		u.Password = ""
+		users = append(users, cloneUser(u))
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	return users, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.users[user.ID]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+	if other := r.findByUsername(user.Username); other != nil && other.ID != user.ID {
+		return errUsernameTaken
+	}
+
+	existing.Username = user.Username
+	existing.Password = user.Password
+	existing.ProfilePhoto = user.ProfilePhoto
+	existing.UpdatedAt = now()
+	user.UpdatedAt = existing.UpdatedAt
+	r.store.users[user.ID] = existing
+
+	return nil
+}
+
+func (r *userRepository) SetRole(ctx context.Context, id int, role string) error {
+	return r.set(id, func(u *domain.User) { u.Role = role })
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, id int, disabledAt *time.Time) error {
+	return r.set(id, func(u *domain.User) { u.DisabledAt = cloneTime(disabledAt) })
+}
+
+// set changes one field of a stored user
+func (r *userRepository) set(id int, change func(u *domain.User)) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.users[id]
+	if !ok {
+		return domain.ErrUserNotFound
+	}
+
+	change(&existing)
+	existing.UpdatedAt = now()
+	r.store.users[id] = existing
+
+	return nil
+}
+
+// findByUsername expects the caller to hold the store lock
+func (r *userRepository) findByUsername(username string) *domain.User {
+	for _, u := range r.store.users {
+		if u.Username == username {
+			u = cloneUser(u)
+			return &u
+		}
+	}
+	return nil
+}
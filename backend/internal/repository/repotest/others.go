@@ -0,0 +1,321 @@
+package repotest
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"testing"
+	"time"
+)
+
+// RunTodoTests checks a domain.TodoRepository
+func RunTodoTests(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("CreateAssignsID", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		todo := &domain.Todo{UserID: alice.ID, Title: "Buy milk", Status: "open"}
+		mustNoError(t, repos.Todos.Create(ctx, todo))
+		if todo.ID == 0 {
+			t.Fatal("id not set")
+		}
+		if todo.UserID != alice.ID {
+			t.Fatalf("user id overwritten with %d", todo.UserID)
+		}
+
+		found, err := repos.Todos.FindById(ctx, todo.ID)
+		mustNoError(t, err)
+		if found.Title != "Buy milk" || found.UserID != alice.ID {
+			t.Fatalf("got %+v", found)
+		}
+	})
+
+	t.Run("FindAllInInsertionOrder", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		var want []int
+		for _, title := range []string{"one", "two", "three"} {
+			todo := &domain.Todo{UserID: alice.ID, Title: title, Status: "open"}
+			mustNoError(t, repos.Todos.Create(ctx, todo))
+			want = append(want, todo.ID)
+		}
+
+		todos, err := repos.Todos.FindAll(ctx)
+		mustNoError(t, err)
+		var got []int
+		for _, todo := range todos {
+			got = append(got, todo.ID)
+		}
+		if !equalInts(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		todo := &domain.Todo{UserID: alice.ID, Title: "Draft", Status: "open"}
+		mustNoError(t, repos.Todos.Create(ctx, todo))
+
+		todo.Title = "Final"
+		todo.Status = "done"
+		mustNoError(t, repos.Todos.Update(ctx, todo))
+		found, err := repos.Todos.FindById(ctx, todo.ID)
+		mustNoError(t, err)
+		if found.Title != "Final" || found.Status != "done" {
+			t.Fatalf("got %+v", found)
+		}
+
+		mustNoError(t, repos.Todos.Delete(ctx, todo.ID))
+		_, err = repos.Todos.FindById(ctx, todo.ID)
+		expectKind(t, err, domain.ErrNotFound)
+		expectCode(t, err, "todo_not_found")
+	})
+
+	t.Run("MissingTodo", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		expectKind(t, repos.Todos.Update(ctx, &domain.Todo{ID: 4242, UserID: alice.ID, Title: "x", Status: "open"}), domain.ErrNotFound)
+		expectKind(t, repos.Todos.Delete(ctx, 4242), domain.ErrNotFound)
+	})
+}
+
+// RunActivityLogTests checks a domain.ActivityLogRepository
+func RunActivityLogTests(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("CreateAndListNewestFirst", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Logged", CreatorID: alice.ID})
+
+		first := &domain.ActivityLog{TicketID: intPtr(ticket.ID), UserID: alice.ID, Action: "created ticket: Logged"}
+		mustNoError(t, repos.ActivityLogs.Create(ctx, first))
+		second := &domain.ActivityLog{UserID: alice.ID, Action: "updated profile"}
+		mustNoError(t, repos.ActivityLogs.Create(ctx, second))
+
+		if first.ID == 0 || first.CreatedAt.IsZero() {
+			t.Fatalf("log not stored: %+v", first)
+		}
+
+		logs, err := repos.ActivityLogs.FindAll(ctx)
+		mustNoError(t, err)
+		if len(logs) != 2 || logs[0].ID != second.ID || logs[1].ID != first.ID {
+			t.Fatalf("got %+v, want newest first", logs)
+		}
+		if logs[0].Username != "alice" {
+			t.Fatalf("username %q, want alice", logs[0].Username)
+		}
+		if logs[0].TicketID != nil {
+			t.Fatalf("ticket id %v, want nil", *logs[0].TicketID)
+		}
+		if logs[1].TicketID == nil || *logs[1].TicketID != ticket.ID {
+			t.Fatalf("ticket id %v, want %d", logs[1].TicketID, ticket.ID)
+		}
+	})
+
+	t.Run("FindAllKeepsTheLatestHundred", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		var last *domain.ActivityLog
+		for i := 0; i < 105; i++ {
+			last = &domain.ActivityLog{UserID: alice.ID, Action: "tick"}
+			mustNoError(t, repos.ActivityLogs.Create(ctx, last))
+		}
+
+		logs, err := repos.ActivityLogs.FindAll(ctx)
+		mustNoError(t, err)
+		if len(logs) != 100 || logs[0].ID != last.ID {
+			t.Fatalf("got %d logs starting at %d, want 100 starting at %d", len(logs), logs[0].ID, last.ID)
+		}
+	})
+
+	t.Run("FindAfterPagesOldestFirst", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		var ids []int
+		for i := 0; i < 5; i++ {
+			log := &domain.ActivityLog{UserID: alice.ID, Action: "tick"}
+			mustNoError(t, repos.ActivityLogs.Create(ctx, log))
+			ids = append(ids, log.ID)
+		}
+
+		page, err := repos.ActivityLogs.FindAfter(ctx, ids[0], 3)
+		mustNoError(t, err)
+		if len(page) != 3 || page[0].ID != ids[1] || page[2].ID != ids[3] || page[0].Username != "alice" {
+			t.Fatalf("got %+v, want ids %v", page, ids[1:4])
+		}
+
+		page, err = repos.ActivityLogs.FindAfter(ctx, page[2].ID, 3)
+		mustNoError(t, err)
+		if len(page) != 1 || page[0].ID != ids[4] {
+			t.Fatalf("got %+v, want only id %d", page, ids[4])
+		}
+	})
+
+	t.Run("UnknownTicketFails", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		err := repos.ActivityLogs.Create(ctx, &domain.ActivityLog{TicketID: intPtr(4242), UserID: alice.ID, Action: "x"})
+		expectKind(t, err, domain.ErrValidation)
+	})
+
+	t.Run("StreamFiltersWithoutLimit", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		// 150 entries an hour apart, alternating between alice and bob, on two tickets
+		items := []domain.TicketImport{
+			{Ticket: &domain.Ticket{Title: "First", CreatorID: alice.ID, CreatedAt: start, UpdatedAt: start}},
+			{Ticket: &domain.Ticket{Title: "Second", CreatorID: alice.ID, CreatedAt: start, UpdatedAt: start}},
+		}
+		for i := 0; i < 150; i++ {
+			user := alice.ID
+			if i%2 == 1 {
+				user = bob.ID
+			}
+			item := &items[i/75]
+			item.History = append(item.History, &domain.ActivityLog{UserID: user, Action: "tick", CreatedAt: start.Add(time.Duration(i) * time.Hour)})
+		}
+		mustNoError(t, repos.Tickets.Import(ctx, items))
+
+		since := start.Add(10 * time.Hour)
+		until := start.Add(20 * time.Hour)
+		cases := []struct {
+			name   string
+			filter domain.ActivityLogFilter
+			want   int
+		}{
+			{"all", domain.ActivityLogFilter{}, 150},
+			{"ticket", domain.ActivityLogFilter{TicketID: intPtr(items[1].Ticket.ID)}, 75},
+			{"user", domain.ActivityLogFilter{UserID: intPtr(bob.ID)}, 75},
+			{"range", domain.ActivityLogFilter{Since: &since, Until: &until}, 10},
+			{"combined", domain.ActivityLogFilter{UserID: intPtr(alice.ID), Since: &since, Until: &until}, 5},
+		}
+
+		for _, tc := range cases {
+			var logs []domain.ActivityLog
+			mustNoError(t, repos.ActivityLogs.Stream(ctx, tc.filter, func(l *domain.ActivityLog) error {
+				logs = append(logs, *l)
+				return nil
+			}))
+			if len(logs) != tc.want {
+				t.Errorf("%s: got %d logs, want %d", tc.name, len(logs), tc.want)
+				continue
+			}
+			for i := 1; i < len(logs); i++ {
+				if logs[i].CreatedAt.After(logs[i-1].CreatedAt) {
+					t.Errorf("%s: not newest first at %d", tc.name, i)
+					break
+				}
+			}
+		}
+
+		var first domain.ActivityLog
+		mustNoError(t, repos.ActivityLogs.Stream(ctx, domain.ActivityLogFilter{Since: &since, Until: &until}, func(l *domain.ActivityLog) error {
+			if first.ID == 0 {
+				first = *l
+			}
+			return nil
+		}))
+		if first.Username != "bob" || !sameInstant(first.CreatedAt, start.Add(19*time.Hour)) || first.TicketID == nil || *first.TicketID != items[0].Ticket.ID {
+			t.Fatalf("newest log in range %+v", first)
+		}
+	})
+}
+
+// RunSavedFilterTests checks a domain.SavedFilterRepository
+func RunSavedFilterTests(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("CriteriaRoundTrip", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		filter := &domain.SavedFilter{
+			UserID: alice.ID,
+			Name:   "My urgent",
+			Criteria: domain.TicketFilter{
+				Statuses:     []string{domain.StatusTodo, domain.StatusInProgress},
+				Priorities:   []string{domain.PriorityHigh},
+				AssignedToMe: true,
+			},
+		}
+		mustNoError(t, repos.SavedFilters.Create(ctx, filter))
+		if filter.ID == 0 || filter.CreatedAt.IsZero() {
+			t.Fatalf("filter not stored: %+v", filter)
+		}
+
+		found, err := repos.SavedFilters.FindByID(ctx, filter.ID)
+		mustNoError(t, err)
+		if found.Username != "alice" || found.Name != "My urgent" || found.Shared {
+			t.Fatalf("got %+v", found)
+		}
+		c := found.Criteria
+		if len(c.Statuses) != 2 || c.Statuses[1] != domain.StatusInProgress || len(c.Priorities) != 1 || !c.AssignedToMe || c.AssigneeID != nil {
+			t.Fatalf("criteria %+v", c)
+		}
+	})
+
+	t.Run("FindVisibleToOwnAndShared", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+
+		create := func(owner *domain.User, name string, shared bool) *domain.SavedFilter {
+			f := &domain.SavedFilter{UserID: owner.ID, Name: name, Shared: shared}
+			mustNoError(t, repos.SavedFilters.Create(ctx, f))
+			return f
+		}
+		mine := create(alice, "b mine", false)
+		shared := create(bob, "a team", true)
+		create(bob, "c private", false)
+
+		filters, err := repos.SavedFilters.FindVisibleTo(ctx, alice.ID)
+		mustNoError(t, err)
+		if len(filters) != 2 || filters[0].ID != shared.ID || filters[1].ID != mine.ID {
+			t.Fatalf("got %+v, want [a team, b mine]", filters)
+		}
+		if filters[0].Username != "bob" {
+			t.Fatalf("username %q, want bob", filters[0].Username)
+		}
+	})
+
+	t.Run("UpdateAndDelete", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		filter := &domain.SavedFilter{UserID: alice.ID, Name: "Before"}
+		mustNoError(t, repos.SavedFilters.Create(ctx, filter))
+
+		filter.Name = "After"
+		filter.Shared = true
+		filter.Criteria.CreatorID = intPtr(alice.ID)
+		mustNoError(t, repos.SavedFilters.Update(ctx, filter))
+
+		found, err := repos.SavedFilters.FindByID(ctx, filter.ID)
+		mustNoError(t, err)
+		if found.Name != "After" || !found.Shared || found.Criteria.CreatorID == nil || *found.Criteria.CreatorID != alice.ID {
+			t.Fatalf("got %+v", found)
+		}
+
+		mustNoError(t, repos.SavedFilters.Delete(ctx, filter.ID))
+		_, err = repos.SavedFilters.FindByID(ctx, filter.ID)
+		expectKind(t, err, domain.ErrNotFound)
+		expectCode(t, err, "saved_filter_not_found")
+	})
+
+	t.Run("MissingFilter", func(t *testing.T) {
+		repos := newRepos(t)
+
+		expectKind(t, repos.SavedFilters.Update(ctx, &domain.SavedFilter{ID: 4242, Name: "x"}), domain.ErrNotFound)
+		expectKind(t, repos.SavedFilters.Delete(ctx, 4242), domain.ErrNotFound)
+	})
+}
@@ -0,0 +1,114 @@
+// Package repotest is a conformance suite for implementations of the domain
+// repositories. A storage backend passes it by handing Run a constructor that returns
+// repositories over an empty store:
+//
+//	func TestContract(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) repotest.Repositories {
+//			store := memory.NewStore()
+//			return repotest.Repositories{Users: memory.NewUserRepository(store), ...}
+//		})
+//	}
+package repotest
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/domain"
+	"testing"
+	"time"
+)
+
+// Repositories is one storage backend. All of them must share the same store since
+// tickets reference users and logs reference tickets.
+type Repositories struct {
+	Users        domain.UserRepository
+	Tickets      domain.TicketRepository
+	Todos        domain.TodoRepository
+	ActivityLogs domain.ActivityLogRepository
+	SavedFilters domain.SavedFilterRepository
+}
+
+// Run executes every contract test, calling newRepos for each so tests never see each
+// other's rows
+func Run(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	t.Run("Users", func(t *testing.T) { RunUserTests(t, newRepos) })
+	t.Run("Tickets", func(t *testing.T) { RunTicketTests(t, newRepos) })
+	t.Run("Todos", func(t *testing.T) { RunTodoTests(t, newRepos) })
+	t.Run("ActivityLogs", func(t *testing.T) { RunActivityLogTests(t, newRepos) })
+	t.Run("SavedFilters", func(t *testing.T) { RunSavedFilterTests(t, newRepos) })
+}
+
+func createUser(t *testing.T, repos Repositories, username string) *domain.User {
+	t.Helper()
+
+	user := &domain.User{Username: username, Password: "hashed-" + username}
+	if err := repos.Users.Create(context.Background(), user); err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	return user
+}
+
+func createTicket(t *testing.T, repos Repositories, ticket *domain.Ticket) *domain.Ticket {
+	t.Helper()
+
+	if err := repos.Tickets.Create(context.Background(), ticket); err != nil {
+		t.Fatalf("create ticket %q: %v", ticket.Title, err)
+	}
+	return ticket
+}
+
+// expectKind fails unless err matches one of the domain error kinds
+func expectKind(t *testing.T, err error, kind error) {
+	t.Helper()
+
+	if !errors.Is(err, kind) {
+		t.Fatalf("got error %v, want %v", err, kind)
+	}
+}
+
+// expectCode fails unless err is a *domain.Error with the given code
+func expectCode(t *testing.T, err error, code string) {
+	t.Helper()
+
+	var domainErr *domain.Error
+	if !errors.As(err, &domainErr) || domainErr.Code != code {
+		t.Fatalf("got error %v, want code %q", err, code)
+	}
+}
+
+func mustNoError(t *testing.T, err error) {
+	t.Helper()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// sameInstant compares timestamps at the microsecond precision databases keep
+func sameInstant(a, b time.Time) bool {
+	return a.Truncate(time.Microsecond).Equal(b.Truncate(time.Microsecond))
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func ids(tickets []domain.Ticket) []int {
+	out := make([]int, 0, len(tickets))
+	for _, t := range tickets {
+		out = append(out, t.ID)
+	}
+	return out
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,582 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"go-todolist/internal/domain"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RunTicketTests checks a domain.TicketRepository
+func RunTicketTests(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("CreateAppliesDefaults", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "First", CreatorID: alice.ID})
+
+		if ticket.ID == 0 || ticket.CreatedAt.IsZero() {
+			t.Fatalf("id or created_at not set: %+v", ticket)
+		}
+		if ticket.Status != domain.StatusBacklog || ticket.Priority != domain.PriorityMedium {
+			t.Fatalf("got status %q priority %q, want Backlog/Medium", ticket.Status, ticket.Priority)
+		}
+	})
+
+	t.Run("FindByIDJoinsUsernamesAndKeepsNulls", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Unassigned", Description: "no due date", CreatorID: alice.ID})
+
+		found, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.CreatorUsername != "alice" {
+			t.Fatalf("creator username %q, want alice", found.CreatorUsername)
+		}
+		if found.AssigneeID != nil || found.AssigneeUsername != nil || found.DueDate != nil || found.DeletedAt != nil {
+			t.Fatalf("expected nil assignee, due date and deleted_at: %+v", found)
+		}
+		if found.Description != "no due date" || !sameInstant(found.CreatedAt, ticket.CreatedAt) {
+			t.Fatalf("got %+v, want %+v", found, ticket)
+		}
+	})
+
+	t.Run("AssigneeAndDueDateRoundTrip", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		due := time.Date(2030, 5, 17, 9, 30, 0, 0, time.UTC)
+		ticket := createTicket(t, repos, &domain.Ticket{
+			Title:      "Assigned",
+			Status:     domain.StatusTodo,
+			Priority:   domain.PriorityHigh,
+			CreatorID:  alice.ID,
+			AssigneeID: intPtr(bob.ID),
+			DueDate:    &due,
+		})
+
+		found, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.AssigneeID == nil || *found.AssigneeID != bob.ID {
+			t.Fatalf("assignee id %v, want %d", found.AssigneeID, bob.ID)
+		}
+		if found.AssigneeUsername == nil || *found.AssigneeUsername != "bob" {
+			t.Fatalf("assignee username %v, want bob", found.AssigneeUsername)
+		}
+		if found.DueDate == nil || !found.DueDate.Equal(due) {
+			t.Fatalf("due date %v, want %v", found.DueDate, due)
+		}
+		if found.Status != domain.StatusTodo || found.Priority != domain.PriorityHigh {
+			t.Fatalf("got status %q priority %q", found.Status, found.Priority)
+		}
+	})
+
+	t.Run("CreateWithUnknownCreatorFails", func(t *testing.T) {
+		repos := newRepos(t)
+
+		err := repos.Tickets.Create(ctx, &domain.Ticket{Title: "Orphan", CreatorID: 4242})
+		expectKind(t, err, domain.ErrValidation)
+		expectCode(t, err, "invalid_reference")
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Tickets.FindByID(ctx, 4242)
+		expectKind(t, err, domain.ErrNotFound)
+		expectCode(t, err, "ticket_not_found")
+	})
+
+	t.Run("FindAllNewestFirst", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		first := createTicket(t, repos, &domain.Ticket{Title: "First", CreatorID: alice.ID})
+		second := createTicket(t, repos, &domain.Ticket{Title: "Second", CreatorID: alice.ID})
+		third := createTicket(t, repos, &domain.Ticket{Title: "Third", CreatorID: alice.ID})
+
+		tickets, err := repos.Tickets.FindAll(ctx)
+		mustNoError(t, err)
+		if got, want := ids(tickets), []int{third.ID, second.ID, first.ID}; !equalInts(got, want) {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("FindByFilter", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		soon := time.Now().UTC().Add(24 * time.Hour).Truncate(time.Second)
+		later := soon.Add(30 * 24 * time.Hour)
+
+		urgent := createTicket(t, repos, &domain.Ticket{Title: "Urgent", Status: domain.StatusTodo, Priority: domain.PriorityHigh, CreatorID: alice.ID, AssigneeID: intPtr(bob.ID), DueDate: &soon})
+		backlog := createTicket(t, repos, &domain.Ticket{Title: "Backlog", CreatorID: bob.ID, DueDate: &later})
+		done := createTicket(t, repos, &domain.Ticket{Title: "Done", Status: domain.StatusDone, CreatorID: alice.ID})
+
+		cases := []struct {
+			name   string
+			filter domain.TicketFilter
+			want   []int
+		}{
+			{"empty", domain.TicketFilter{}, []int{done.ID, backlog.ID, urgent.ID}},
+			{"statuses", domain.TicketFilter{Statuses: []string{domain.StatusTodo, domain.StatusDone}}, []int{done.ID, urgent.ID}},
+			{"priority", domain.TicketFilter{Priorities: []string{domain.PriorityHigh}}, []int{urgent.ID}},
+			{"assignee", domain.TicketFilter{AssigneeID: intPtr(bob.ID)}, []int{urgent.ID}},
+			{"creator", domain.TicketFilter{CreatorID: intPtr(bob.ID)}, []int{backlog.ID}},
+			{"due before", domain.TicketFilter{DueBefore: &later}, []int{urgent.ID}},
+			{"due after", domain.TicketFilter{DueAfter: &later}, []int{backlog.ID}},
+			{"combined", domain.TicketFilter{CreatorID: intPtr(alice.ID), Statuses: []string{domain.StatusDone}}, []int{done.ID}},
+		}
+
+		for _, tc := range cases {
+			tickets, err := repos.Tickets.FindByFilter(ctx, tc.filter)
+			mustNoError(t, err)
+			if got := ids(tickets); !equalInts(got, tc.want) {
+				t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+			}
+
+			var streamed []domain.Ticket
+			mustNoError(t, repos.Tickets.StreamByFilter(ctx, tc.filter, func(ticket *domain.Ticket) error {
+				streamed = append(streamed, *ticket)
+				return nil
+			}))
+			if got := ids(streamed); !equalInts(got, tc.want) {
+				t.Errorf("%s: streamed %v, want %v", tc.name, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("StreamByFilterStopsOnError", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		for _, title := range []string{"A", "B", "C"} {
+			createTicket(t, repos, &domain.Ticket{Title: title, CreatorID: alice.ID})
+		}
+
+		stop := errors.New("stop")
+		calls := 0
+		err := repos.Tickets.StreamByFilter(ctx, domain.TicketFilter{}, func(*domain.Ticket) error {
+			calls++
+			return stop
+		})
+		if err != stop || calls != 1 {
+			t.Fatalf("got %v after %d calls, want the callback error after 1", err, calls)
+		}
+	})
+
+	t.Run("FindByStatus", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		createTicket(t, repos, &domain.Ticket{Title: "Backlog", CreatorID: alice.ID})
+		todo := createTicket(t, repos, &domain.Ticket{Title: "Todo", Status: domain.StatusTodo, CreatorID: alice.ID})
+
+		tickets, err := repos.Tickets.FindByStatus(ctx, domain.StatusTodo)
+		mustNoError(t, err)
+		if got := ids(tickets); !equalInts(got, []int{todo.ID}) {
+			t.Fatalf("got %v, want [%d]", got, todo.ID)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Before", CreatorID: alice.ID})
+
+		ticket.Title = "After"
+		ticket.Status = domain.StatusInProgress
+		ticket.AssigneeID = intPtr(bob.ID)
+		mustNoError(t, repos.Tickets.Update(ctx, ticket, domain.AnyEditor))
+
+		found, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.Title != "After" || found.Status != domain.StatusInProgress || found.AssigneeUsername == nil || *found.AssigneeUsername != "bob" {
+			t.Fatalf("got %+v", found)
+		}
+
+		// clearing the assignee stores NULL again
+		ticket.AssigneeID = nil
+		mustNoError(t, repos.Tickets.Update(ctx, ticket, domain.AnyEditor))
+		found, err = repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.AssigneeID != nil || found.AssigneeUsername != nil {
+			t.Fatalf("assignee not cleared: %+v", found)
+		}
+	})
+
+	t.Run("UpdateMissingTicket", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+
+		err := repos.Tickets.Update(ctx, &domain.Ticket{ID: 4242, Title: "Ghost", Status: domain.StatusTodo, Priority: domain.PriorityLow, CreatorID: alice.ID}, domain.AnyEditor)
+		expectKind(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("PatchWritesOnlyChangedFields", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		due := time.Date(2030, 5, 17, 9, 30, 0, 0, time.UTC)
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Before", Description: "keep me", Priority: domain.PriorityHigh, CreatorID: alice.ID, AssigneeID: intPtr(bob.ID), DueDate: &due})
+
+		// a concurrent writer changes the status after the patch was prepared
+		mustNoError(t, repos.Tickets.UpdateStatus(ctx, ticket.ID, domain.StatusInProgress, domain.AnyEditor))
+
+		title := "After"
+		mustNoError(t, repos.Tickets.Patch(ctx, ticket.ID, domain.TicketChanges{
+			Title:      &title,
+			AssigneeID: domain.NullableChange[int]{Set: true},
+		}, domain.AnyEditor))
+
+		found, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.Title != "After" || found.AssigneeID != nil || found.AssigneeUsername != nil {
+			t.Fatalf("patch not applied: %+v", found)
+		}
+		if found.Status != domain.StatusInProgress || found.Description != "keep me" || found.Priority != domain.PriorityHigh || found.DueDate == nil || !sameInstant(*found.DueDate, due) {
+			t.Fatalf("fields outside the patch changed: %+v", found)
+		}
+		if found.UpdatedAt.Before(ticket.UpdatedAt) {
+			t.Fatalf("updated_at not bumped: %v", found.UpdatedAt)
+		}
+
+		expectKind(t, repos.Tickets.Patch(ctx, 4242, domain.TicketChanges{Title: &title}, domain.AnyEditor), domain.ErrNotFound)
+		expectKind(t, repos.Tickets.Patch(ctx, ticket.ID, domain.TicketChanges{AssigneeID: domain.NullableChange[int]{Set: true, Value: intPtr(4242)}}, domain.AnyEditor), domain.ErrValidation)
+		mustNoError(t, repos.Tickets.Delete(ctx, ticket.ID, domain.AnyEditor))
+		expectKind(t, repos.Tickets.Patch(ctx, ticket.ID, domain.TicketChanges{Title: &title}, domain.AnyEditor), domain.ErrNotFound)
+	})
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Move me", CreatorID: alice.ID})
+
+		mustNoError(t, repos.Tickets.UpdateStatus(ctx, ticket.ID, domain.StatusDone, domain.AnyEditor))
+		found, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.Status != domain.StatusDone {
+			t.Fatalf("status %q, want Done", found.Status)
+		}
+
+		expectKind(t, repos.Tickets.UpdateStatus(ctx, 4242, domain.StatusDone, domain.AnyEditor), domain.ErrNotFound)
+	})
+
+	t.Run("SoftDeleteAndRestore", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Trash me", CreatorID: alice.ID})
+
+		mustNoError(t, repos.Tickets.Delete(ctx, ticket.ID, domain.AnyEditor))
+
+		_, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		expectKind(t, err, domain.ErrNotFound)
+		expectKind(t, repos.Tickets.Delete(ctx, ticket.ID, domain.AnyEditor), domain.ErrNotFound)
+		expectKind(t, repos.Tickets.UpdateStatus(ctx, ticket.ID, domain.StatusDone, domain.AnyEditor), domain.ErrNotFound)
+
+		live, err := repos.Tickets.FindAll(ctx)
+		mustNoError(t, err)
+		if len(live) != 0 {
+			t.Fatalf("trashed ticket still listed: %v", ids(live))
+		}
+
+		trashed, err := repos.Tickets.FindTrashed(ctx)
+		mustNoError(t, err)
+		if len(trashed) != 1 || trashed[0].ID != ticket.ID || trashed[0].DeletedAt == nil {
+			t.Fatalf("got trash %+v", trashed)
+		}
+
+		mustNoError(t, repos.Tickets.Restore(ctx, ticket.ID, domain.AnyEditor))
+		if _, err := repos.Tickets.FindByID(ctx, ticket.ID); err != nil {
+			t.Fatalf("restored ticket not found: %v", err)
+		}
+
+		err = repos.Tickets.Restore(ctx, ticket.ID, domain.AnyEditor)
+		expectKind(t, err, domain.ErrNotFound)
+		expectCode(t, err, "ticket_not_in_trash")
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		old := createTicket(t, repos, &domain.Ticket{Title: "Old", CreatorID: alice.ID})
+		live := createTicket(t, repos, &domain.Ticket{Title: "Live", CreatorID: alice.ID})
+		mustNoError(t, repos.ActivityLogs.Create(ctx, &domain.ActivityLog{TicketID: intPtr(old.ID), UserID: alice.ID, Action: "created ticket: Old"}))
+		mustNoError(t, repos.Tickets.Delete(ctx, old.ID, domain.AnyEditor))
+
+		purged, err := repos.Tickets.Purge(ctx, time.Now().UTC().Add(-time.Hour))
+		mustNoError(t, err)
+		if purged != 0 {
+			t.Fatalf("purged %d tickets deleted less than an hour ago", purged)
+		}
+
+		purged, err = repos.Tickets.Purge(ctx, time.Now().UTC().Add(time.Minute))
+		mustNoError(t, err)
+		if purged != 1 {
+			t.Fatalf("purged %d, want 1", purged)
+		}
+
+		trashed, err := repos.Tickets.FindTrashed(ctx)
+		mustNoError(t, err)
+		if len(trashed) != 0 {
+			t.Fatalf("trash not empty: %v", ids(trashed))
+		}
+		if _, err := repos.Tickets.FindByID(ctx, live.ID); err != nil {
+			t.Fatalf("live ticket purged: %v", err)
+		}
+
+		logs, err := repos.ActivityLogs.FindAll(ctx)
+		mustNoError(t, err)
+		if len(logs) != 1 || logs[0].TicketID != nil || logs[0].Action != "created ticket: Old" {
+			t.Fatalf("activity of a purged ticket not kept without its ticket: %+v", logs)
+		}
+	})
+
+	t.Run("WritesNeedCreatorOrAssignee", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		carol := createUser(t, repos, "carol")
+		ticket := createTicket(t, repos, &domain.Ticket{Title: "Mine", CreatorID: alice.ID, AssigneeID: intPtr(bob.ID)})
+		title := "Taken over"
+
+		for name, err := range map[string]error{
+			"Update":       repos.Tickets.Update(ctx, &domain.Ticket{ID: ticket.ID, Title: title, Status: domain.StatusTodo, Priority: domain.PriorityLow}, carol.ID),
+			"Patch":        repos.Tickets.Patch(ctx, ticket.ID, domain.TicketChanges{Title: &title}, carol.ID),
+			"UpdateStatus": repos.Tickets.UpdateStatus(ctx, ticket.ID, domain.StatusDone, carol.ID),
+			"Delete":       repos.Tickets.Delete(ctx, ticket.ID, carol.ID),
+			"PatchMany":    repos.Tickets.PatchMany(ctx, []int{ticket.ID}, domain.TicketChanges{Title: &title}, nil, carol.ID),
+		} {
+			if !errors.Is(err, domain.ErrForbidden) {
+				t.Errorf("%s by a stranger: got %v, want forbidden", name, err)
+			}
+		}
+		found, err := repos.Tickets.FindByID(ctx, ticket.ID)
+		mustNoError(t, err)
+		if found.Title != "Mine" || found.Status != domain.StatusBacklog {
+			t.Fatalf("refused writes changed the ticket: %+v", found)
+		}
+
+		// the assignee and the creator may, a missing ticket is still not found
+		mustNoError(t, repos.Tickets.UpdateStatus(ctx, ticket.ID, domain.StatusDone, bob.ID))
+		expectKind(t, repos.Tickets.UpdateStatus(ctx, 4242, domain.StatusDone, carol.ID), domain.ErrNotFound)
+		mustNoError(t, repos.Tickets.Delete(ctx, ticket.ID, alice.ID))
+		expectKind(t, repos.Tickets.Restore(ctx, ticket.ID, carol.ID), domain.ErrForbidden)
+		mustNoError(t, repos.Tickets.Restore(ctx, ticket.ID, bob.ID))
+	})
+
+	t.Run("PatchManyIsAllOrNothing", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		a := createTicket(t, repos, &domain.Ticket{Title: "A", CreatorID: alice.ID})
+		b := createTicket(t, repos, &domain.Ticket{Title: "B", CreatorID: alice.ID})
+
+		done := domain.StatusDone
+		logs := []*domain.ActivityLog{
+			{TicketID: intPtr(a.ID), UserID: alice.ID, Action: "bulk a"},
+			{TicketID: intPtr(b.ID), UserID: alice.ID, Action: "bulk b"},
+		}
+		mustNoError(t, repos.Tickets.PatchMany(ctx, []int{a.ID, b.ID}, domain.TicketChanges{Status: &done}, logs, domain.AnyEditor))
+		for _, l := range logs {
+			if l.ID == 0 || l.CreatedAt.IsZero() {
+				t.Fatalf("log not stored: %+v", l)
+			}
+		}
+
+		counts, err := repos.Tickets.CountByStatus(ctx)
+		mustNoError(t, err)
+		if counts[domain.StatusDone] != 2 {
+			t.Fatalf("got counts %v, want 2 done", counts)
+		}
+
+		todo := domain.StatusTodo
+		err = repos.Tickets.PatchMany(ctx, []int{a.ID, 4242}, domain.TicketChanges{Status: &todo}, []*domain.ActivityLog{{TicketID: intPtr(a.ID), UserID: alice.ID, Action: "rolled back"}}, domain.AnyEditor)
+		expectKind(t, err, domain.ErrNotFound)
+
+		found, err := repos.Tickets.FindByID(ctx, a.ID)
+		mustNoError(t, err)
+		if found.Status != domain.StatusDone {
+			t.Fatalf("failed batch changed ticket %d to %q", a.ID, found.Status)
+		}
+		all, err := repos.ActivityLogs.FindAll(ctx)
+		mustNoError(t, err)
+		if len(all) != 2 {
+			t.Fatalf("failed batch left %d logs, want 2", len(all))
+		}
+	})
+
+	t.Run("PatchManyWritesOnlyChangedFields", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		a := createTicket(t, repos, &domain.Ticket{Title: "A", Priority: domain.PriorityLow, CreatorID: alice.ID})
+		b := createTicket(t, repos, &domain.Ticket{Title: "B", Priority: domain.PriorityLow, CreatorID: alice.ID})
+
+		// a concurrent writer renames a ticket after the batch was prepared
+		a.Title = "A renamed"
+		mustNoError(t, repos.Tickets.Update(ctx, a, domain.AnyEditor))
+
+		high := domain.PriorityHigh
+		changes := domain.TicketChanges{Priority: &high, AssigneeID: domain.NullableChange[int]{Set: true, Value: &bob.ID}}
+		mustNoError(t, repos.Tickets.PatchMany(ctx, []int{a.ID, b.ID}, changes, nil, domain.AnyEditor))
+
+		for _, want := range []struct {
+			id    int
+			title string
+		}{{a.ID, "A renamed"}, {b.ID, "B"}} {
+			found, err := repos.Tickets.FindByID(ctx, want.id)
+			mustNoError(t, err)
+			if found.Title != want.title || found.Priority != domain.PriorityHigh || found.AssigneeUsername == nil || *found.AssigneeUsername != "bob" {
+				t.Fatalf("got %+v", found)
+			}
+		}
+
+		err := repos.Tickets.PatchMany(ctx, []int{a.ID}, domain.TicketChanges{AssigneeID: domain.NullableChange[int]{Set: true, Value: intPtr(4242)}}, nil, domain.AnyEditor)
+		expectKind(t, err, domain.ErrValidation)
+	})
+
+	t.Run("ImportKeepsTimestampsAndHistory", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+		created := time.Date(2024, 3, 4, 9, 15, 0, 0, time.UTC)
+		done := created.Add(50 * time.Hour)
+		trashed := done.Add(time.Hour)
+
+		items := []domain.TicketImport{
+			{
+				Ticket: &domain.Ticket{Title: "Imported", Status: domain.StatusDone, Priority: domain.PriorityHigh, CreatorID: alice.ID, AssigneeID: intPtr(bob.ID), CreatedAt: created, UpdatedAt: done},
+				History: []*domain.ActivityLog{
+					{UserID: alice.ID, Action: "created ticket: Imported", CreatedAt: created},
+					{UserID: bob.ID, Action: "changed status to Done", CreatedAt: done},
+				},
+			},
+			{
+				Ticket:  &domain.Ticket{Title: "Trashed", CreatorID: bob.ID, CreatedAt: created, UpdatedAt: trashed, DeletedAt: &trashed},
+				History: []*domain.ActivityLog{{UserID: bob.ID, Action: "moved ticket to trash: Trashed", CreatedAt: trashed}},
+			},
+		}
+		mustNoError(t, repos.Tickets.Import(ctx, items))
+
+		imported := items[0].Ticket
+		if imported.ID == 0 || items[1].Ticket.ID == 0 || imported.ID == items[1].Ticket.ID {
+			t.Fatalf("ids not assigned: %d, %d", imported.ID, items[1].Ticket.ID)
+		}
+		found, err := repos.Tickets.FindByID(ctx, imported.ID)
+		mustNoError(t, err)
+		if !sameInstant(found.CreatedAt, created) || !sameInstant(found.UpdatedAt, done) || found.Status != domain.StatusDone {
+			t.Fatalf("got %+v", found)
+		}
+		if found.AssigneeUsername == nil || *found.AssigneeUsername != "bob" {
+			t.Fatalf("assignee username %v, want bob", found.AssigneeUsername)
+		}
+		if items[1].Ticket.Status != domain.StatusBacklog || items[1].Ticket.Priority != domain.PriorityMedium {
+			t.Fatalf("defaults not applied: %+v", items[1].Ticket)
+		}
+
+		trash, err := repos.Tickets.FindTrashed(ctx)
+		mustNoError(t, err)
+		if len(trash) != 1 || trash[0].ID != items[1].Ticket.ID {
+			t.Fatalf("got trash %+v", trash)
+		}
+
+		logs, err := repos.ActivityLogs.FindAll(ctx)
+		mustNoError(t, err)
+		if len(logs) != 3 {
+			t.Fatalf("got %d logs, want 3", len(logs))
+		}
+		if logs[0].Action != "moved ticket to trash: Trashed" || !sameInstant(logs[0].CreatedAt, trashed) {
+			t.Fatalf("newest log %+v", logs[0])
+		}
+		if logs[2].TicketID == nil || *logs[2].TicketID != imported.ID || !sameInstant(logs[2].CreatedAt, created) {
+			t.Fatalf("oldest log %+v", logs[2])
+		}
+
+		// tickets created afterwards must not collide with the imported ids
+		next := createTicket(t, repos, &domain.Ticket{Title: "After", CreatorID: alice.ID})
+		if next.ID == imported.ID || next.ID == items[1].Ticket.ID {
+			t.Fatalf("new ticket reused id %d", next.ID)
+		}
+	})
+
+	t.Run("ImportIsAllOrNothing", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		now := time.Now().UTC()
+
+		err := repos.Tickets.Import(ctx, []domain.TicketImport{
+			{Ticket: &domain.Ticket{Title: "Fine", CreatorID: alice.ID, CreatedAt: now, UpdatedAt: now}},
+			{Ticket: &domain.Ticket{Title: "Orphan", CreatorID: 4242, CreatedAt: now, UpdatedAt: now}},
+		})
+		expectKind(t, err, domain.ErrValidation)
+
+		all, err := repos.Tickets.FindAll(ctx)
+		mustNoError(t, err)
+		if len(all) != 0 {
+			t.Fatalf("failed import left %d tickets", len(all))
+		}
+	})
+
+	t.Run("CountByStatusIgnoresTrash", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		createTicket(t, repos, &domain.Ticket{Title: "A", CreatorID: alice.ID})
+		createTicket(t, repos, &domain.Ticket{Title: "B", Status: domain.StatusTodo, CreatorID: alice.ID})
+		trashed := createTicket(t, repos, &domain.Ticket{Title: "C", Status: domain.StatusTodo, CreatorID: alice.ID})
+		mustNoError(t, repos.Tickets.Delete(ctx, trashed.ID, domain.AnyEditor))
+
+		counts, err := repos.Tickets.CountByStatus(ctx)
+		mustNoError(t, err)
+		if counts[domain.StatusBacklog] != 1 || counts[domain.StatusTodo] != 1 || len(counts) != 2 {
+			t.Fatalf("got %v", counts)
+		}
+
+		inTrash, err := repos.Tickets.CountTrashed(ctx)
+		mustNoError(t, err)
+		if inTrash != 1 {
+			t.Fatalf("counted %d trashed tickets, want 1", inTrash)
+		}
+	})
+
+	t.Run("SearchMatchesPrefixes", func(t *testing.T) {
+		repos := newRepos(t)
+		alice := createUser(t, repos, "alice")
+		login := createTicket(t, repos, &domain.Ticket{Title: "Fix login bug", Description: "Users get logged out", CreatorID: alice.ID})
+		createTicket(t, repos, &domain.Ticket{Title: "Write docs", Description: "Explain the setup", CreatorID: alice.ID})
+		doneLogin := createTicket(t, repos, &domain.Ticket{Title: "Login page copy", Status: domain.StatusDone, CreatorID: alice.ID})
+		trashed := createTicket(t, repos, &domain.Ticket{Title: "Login redesign", CreatorID: alice.ID})
+		mustNoError(t, repos.Tickets.Delete(ctx, trashed.ID, domain.AnyEditor))
+
+		results, err := repos.Tickets.Search(ctx, domain.TicketSearchParams{Query: "log", Limit: 10})
+		mustNoError(t, err)
+		got := make(map[int]domain.TicketSearchResult)
+		for _, r := range results {
+			got[r.ID] = r
+		}
+		if len(got) != 2 || got[login.ID].ID == 0 || got[doneLogin.ID].ID == 0 {
+			t.Fatalf("got %d results %v, want tickets %d and %d", len(results), got, login.ID, doneLogin.ID)
+		}
+		if !strings.Contains(got[login.ID].TitleHighlight, "<mark>") {
+			t.Fatalf("title not highlighted: %q", got[login.ID].TitleHighlight)
+		}
+
+		results, err = repos.Tickets.Search(ctx, domain.TicketSearchParams{Query: "login fix", Status: domain.StatusBacklog, Limit: 10})
+		mustNoError(t, err)
+		if len(results) != 1 || results[0].ID != login.ID {
+			t.Fatalf("every term must match: got %d results", len(results))
+		}
+
+		results, err = repos.Tickets.Search(ctx, domain.TicketSearchParams{Query: "log", Limit: 1})
+		mustNoError(t, err)
+		if len(results) != 1 {
+			t.Fatalf("limit ignored: got %d results", len(results))
+		}
+
+		results, err = repos.Tickets.Search(ctx, domain.TicketSearchParams{Query: "!!", Limit: 10})
+		mustNoError(t, err)
+		if len(results) != 0 {
+			t.Fatalf("punctuation-only query matched %d tickets", len(results))
+		}
+	})
+}
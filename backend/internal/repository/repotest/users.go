@@ -0,0 +1,183 @@
+package repotest
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"testing"
+	"time"
+)
+
+// RunUserTests checks a domain.UserRepository
+func RunUserTests(t *testing.T, newRepos func(t *testing.T) Repositories) {
+	ctx := context.Background()
+
+	t.Run("CreateAssignsIDAndTimestamps", func(t *testing.T) {
+		repos := newRepos(t)
+		user := createUser(t, repos, "alice")
+
+		if user.ID == 0 {
+			t.Fatal("id not set")
+		}
+		if user.CreatedAt.IsZero() || user.UpdatedAt.IsZero() {
+			t.Fatal("timestamps not set")
+		}
+
+		found, err := repos.Users.FindByID(ctx, user.ID)
+		mustNoError(t, err)
+		if found.Username != "alice" || found.Password != "hashed-alice" {
+			t.Fatalf("got %+v", found)
+		}
+	})
+
+	t.Run("DuplicateUsernameConflicts", func(t *testing.T) {
+		repos := newRepos(t)
+		createUser(t, repos, "alice")
+
+		err := repos.Users.Create(ctx, &domain.User{Username: "alice", Password: "x"})
+		expectKind(t, err, domain.ErrConflict)
+		expectCode(t, err, "username_taken")
+	})
+
+	t.Run("FindByUsername", func(t *testing.T) {
+		repos := newRepos(t)
+		user := createUser(t, repos, "alice")
+
+		found, err := repos.Users.FindByUsername(ctx, "alice")
+		mustNoError(t, err)
+		if found.ID != user.ID {
+			t.Fatalf("got id %d, want %d", found.ID, user.ID)
+		}
+
+		_, err = repos.Users.FindByUsername(ctx, "nobody")
+		expectKind(t, err, domain.ErrNotFound)
+		expectCode(t, err, "user_not_found")
+	})
+
+	t.Run("FindByIDNotFound", func(t *testing.T) {
+		repos := newRepos(t)
+
+		_, err := repos.Users.FindByID(ctx, 4242)
+		expectKind(t, err, domain.ErrNotFound)
+	})
+
+	t.Run("FindAllSortedWithoutPasswords", func(t *testing.T) {
+		repos := newRepos(t)
+		createUser(t, repos, "carol")
+		createUser(t, repos, "alice")
+		createUser(t, repos, "bob")
+
+		users, err := repos.Users.FindAll(ctx)
+		mustNoError(t, err)
+
+		var names []string
+		for _, u := range users {
+			names = append(names, u.Username)
+			if u.Password != "" {
+				t.Fatalf("FindAll leaked the password of %s", u.Username)
+			}
+		}
+		if len(names) != 3 || names[0] != "alice" || names[1] != "bob" || names[2] != "carol" {
+			t.Fatalf("got %v, want [alice bob carol]", names)
+		}
+	})
+
+	t.Run("FindAllEmpty", func(t *testing.T) {
+		repos := newRepos(t)
+
+		users, err := repos.Users.FindAll(ctx)
+		mustNoError(t, err)
+		if len(users) != 0 {
+			t.Fatalf("got %d users, want none", len(users))
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repos := newRepos(t)
+		user := createUser(t, repos, "alice")
+
+		user.Username = "alice2"
+		user.ProfilePhoto = "https://example.com/a.png"
+		mustNoError(t, repos.Users.Update(ctx, user))
+
+		found, err := repos.Users.FindByID(ctx, user.ID)
+		mustNoError(t, err)
+		if found.Username != "alice2" || found.ProfilePhoto != "https://example.com/a.png" {
+			t.Fatalf("got %+v", found)
+		}
+	})
+
+	t.Run("RoleAndDisabledRoundTrip", func(t *testing.T) {
+		repos := newRepos(t)
+		user := createUser(t, repos, "alice")
+		if user.Role != domain.RoleUser || user.Disabled() {
+			t.Fatalf("new user has role %q, disabled_at %v", user.Role, user.DisabledAt)
+		}
+
+		disabledAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		mustNoError(t, repos.Users.SetRole(ctx, user.ID, domain.RoleAdmin))
+		mustNoError(t, repos.Users.SetDisabled(ctx, user.ID, &disabledAt))
+
+		found, err := repos.Users.FindByUsername(ctx, "alice")
+		mustNoError(t, err)
+		if found.Role != domain.RoleAdmin || found.DisabledAt == nil || !found.DisabledAt.Equal(disabledAt) {
+			t.Fatalf("got role %q, disabled_at %v", found.Role, found.DisabledAt)
+		}
+
+		all, err := repos.Users.FindAll(ctx)
+		mustNoError(t, err)
+		if len(all) != 1 || all[0].Role != domain.RoleAdmin || !all[0].Disabled() {
+			t.Fatalf("got %+v", all)
+		}
+
+		mustNoError(t, repos.Users.SetDisabled(ctx, user.ID, nil))
+		found, err = repos.Users.FindByID(ctx, user.ID)
+		mustNoError(t, err)
+		if found.Disabled() {
+			t.Fatalf("account still disabled: %v", found.DisabledAt)
+		}
+
+		expectKind(t, repos.Users.SetRole(ctx, 4242, domain.RoleAdmin), domain.ErrNotFound)
+		expectKind(t, repos.Users.SetDisabled(ctx, 4242, nil), domain.ErrNotFound)
+	})
+
+	t.Run("UpdateKeepsRoleAndDisabled", func(t *testing.T) {
+		repos := newRepos(t)
+		createUser(t, repos, "alice")
+
+		// a profile change works on a copy loaded before the account was disabled
+		stale, err := repos.Users.FindByUsername(ctx, "alice")
+		mustNoError(t, err)
+		disabledAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+		mustNoError(t, repos.Users.SetRole(ctx, stale.ID, domain.RoleAdmin))
+		mustNoError(t, repos.Users.SetDisabled(ctx, stale.ID, &disabledAt))
+
+		stale.ProfilePhoto = "https://example.com/a.png"
+		mustNoError(t, repos.Users.Update(ctx, stale))
+
+		found, err := repos.Users.FindByID(ctx, stale.ID)
+		mustNoError(t, err)
+		if found.ProfilePhoto != "https://example.com/a.png" {
+			t.Fatalf("profile not updated: %+v", found)
+		}
+		if found.Role != domain.RoleAdmin || !found.Disabled() {
+			t.Fatalf("stale profile update reset role %q, disabled_at %v", found.Role, found.DisabledAt)
+		}
+	})
+
+	t.Run("UpdateToTakenUsernameConflicts", func(t *testing.T) {
+		repos := newRepos(t)
+		createUser(t, repos, "alice")
+		bob := createUser(t, repos, "bob")
+
+		bob.Username = "alice"
+		err := repos.Users.Update(ctx, bob)
+		expectCode(t, err, "username_taken")
+	})
+
+	t.Run("UpdateMissingUser", func(t *testing.T) {
+		repos := newRepos(t)
+
+		err := repos.Users.Update(ctx, &domain.User{ID: 4242, Username: "ghost", Password: "x"})
+		expectKind(t, err, domain.ErrNotFound)
+	})
+}
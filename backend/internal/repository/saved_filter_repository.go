@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type savedFilterRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSavedFilterRepository(db *pgxpool.Pool) domain.SavedFilterRepository {
+	return &savedFilterRepository{db: db}
+}
+
+func (r *savedFilterRepository) Create(ctx context.Context, filter *domain.SavedFilter) error {
+	query := `
+		INSERT INTO saved_filters (user_id, name, criteria, shared, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	now := time.Now().UTC()
+	filter.CreatedAt = now
+	filter.UpdatedAt = now
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		filter.UserID,
+		filter.Name,
+		filter.Criteria,
+		filter.Shared,
+		filter.CreatedAt,
+		filter.UpdatedAt,
+	).Scan(&filter.ID)
+
+	if err != nil {
+		return translateError(err, "failed to create saved filter", nil)
+	}
+
+	return nil
+}
+
+func (r *savedFilterRepository) FindByID(ctx context.Context, id int) (*domain.SavedFilter, error) {
+	query := `
+		SELECT f.id, f.user_id, u.username, f.name, f.criteria, f.shared, f.created_at, f.updated_at
+		FROM saved_filters f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.id = $1
+	`
+
+	f := &domain.SavedFilter{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&f.ID,
+		&f.UserID,
+		&f.Username,
+		&f.Name,
+		&f.Criteria,
+		&f.Shared,
+		&f.CreatedAt,
+		&f.UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, translateError(err, "failed to find saved filter", domain.ErrSavedFilterNotFound)
+	}
+
+	return f, nil
+}
+
+func (r *savedFilterRepository) FindVisibleTo(ctx context.Context, userID int) ([]domain.SavedFilter, error) {
+	query := `
+		SELECT f.id, f.user_id, u.username, f.name, f.criteria, f.shared, f.created_at, f.updated_at
+		FROM saved_filters f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.user_id = $1 OR f.shared
+		ORDER BY f.name ASC, f.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, translateError(err, "failed to query saved filters", nil)
+	}
+	defer rows.Close()
+
+	var filters []domain.SavedFilter
+	for rows.Next() {
+		var f domain.SavedFilter
+		err := rows.Scan(
+			&f.ID,
+			&f.UserID,
+			&f.Username,
+			&f.Name,
+			&f.Criteria,
+			&f.Shared,
+			&f.CreatedAt,
+			&f.UpdatedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan saved filter", nil)
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, nil
+}
+
+func (r *savedFilterRepository) Update(ctx context.Context, filter *domain.SavedFilter) error {
+	query := `
+		UPDATE saved_filters
+		SET name = $1, criteria = $2, shared = $3, updated_at = $4
+		WHERE id = $5
+	`
+
+	filter.UpdatedAt = time.Now().UTC()
+
+	tag, err := r.db.Exec(
+		ctx,
+		query,
+		filter.Name,
+		filter.Criteria,
+		filter.Shared,
+		filter.UpdatedAt,
+		filter.ID,
+	)
+
+	if err != nil {
+		return translateError(err, "failed to update saved filter", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSavedFilterNotFound
+	}
+
+	return nil
+}
+
+func (r *savedFilterRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM saved_filters WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, id)
+	if err != nil {
+		return translateError(err, "failed to delete saved filter", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrSavedFilterNotFound
+	}
+
+	return nil
+}
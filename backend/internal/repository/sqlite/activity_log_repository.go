@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"go-todolist/internal/domain"
+	"strings"
+)
+
+type activityLogRepository struct {
+	db *sql.DB
+}
+
+func NewActivityLogRepository(db *sql.DB) domain.ActivityLogRepository {
+	return &activityLogRepository{db: db}
+}
+
+const insertActivityLog = `
+	INSERT INTO activity_logs (ticket_id, user_id, action, created_at)
+	VALUES (?, ?, ?, ?)
+	RETURNING id
+`
+
+func (r *activityLogRepository) Create(ctx context.Context, log *domain.ActivityLog) error {
+	log.CreatedAt = now()
+
+	err := r.db.QueryRowContext(
+		ctx,
+		insertActivityLog,
+		log.TicketID,
+		log.UserID,
+		log.Action,
+		formatTime(log.CreatedAt),
+	).Scan(&log.ID)
+
+	if err != nil {
+		return translateError(err, "failed to create activity log", nil)
+	}
+
+	return nil
+}
+
+func (r *activityLogRepository) FindAll(ctx context.Context) ([]domain.ActivityLog, error) {
+	return r.list(ctx, `
+		SELECT al.id, al.ticket_id, al.user_id, u.username, al.action, al.created_at
+		FROM activity_logs al
+		JOIN users u ON al.user_id = u.id
+		ORDER BY al.created_at DESC, al.id DESC
+		LIMIT 100
+	`)
+}
+
+// FindAfter orders by id alone so entries written in the same instant are never
+// skipped between pages
+func (r *activityLogRepository) FindAfter(ctx context.Context, afterID, limit int) ([]domain.ActivityLog, error) {
+	return r.list(ctx, `
+		SELECT al.id, al.ticket_id, al.user_id, u.username, al.action, al.created_at
+		FROM activity_logs al
+		JOIN users u ON al.user_id = u.id
+		WHERE al.id > ?
+		ORDER BY al.id
+		LIMIT ?
+	`, afterID, limit)
+}
+
+func (r *activityLogRepository) list(ctx context.Context, query string, args ...any) ([]domain.ActivityLog, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, translateError(err, "failed to query activity logs", nil)
+	}
+	defer rows.Close()
+
+	var logs []domain.ActivityLog
+	for rows.Next() {
+		var l domain.ActivityLog
+		var ticketID sql.NullInt64
+		err := rows.Scan(
+			&l.ID,
+			&ticketID,
+			&l.UserID,
+			&l.Username,
+			&l.Action,
+			scanTime(&l.CreatedAt),
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan activity log", nil)
+		}
+		l.TicketID = nullInt(ticketID)
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
+
+// Stream calls fn for every activity log matching filter, newest first and without the
+// limit FindAll applies. Rows are read as they arrive.
+func (r *activityLogRepository) Stream(ctx context.Context, filter domain.ActivityLogFilter, fn func(*domain.ActivityLog) error) error {
+	var conditions []string
+	var args []any
+
+	if filter.TicketID != nil {
+		conditions = append(conditions, "al.ticket_id = ?")
+		args = append(args, *filter.TicketID)
+	}
+	if filter.UserID != nil {
+		conditions = append(conditions, "al.user_id = ?")
+		args = append(args, *filter.UserID)
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "al.created_at >= ?")
+		args = append(args, formatTime(*filter.Since))
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "al.created_at < ?")
+		args = append(args, formatTime(*filter.Until))
+	}
+
+	query := `
+		SELECT al.id, al.ticket_id, al.user_id, u.username, al.action, al.created_at
+		FROM activity_logs al
+		JOIN users u ON al.user_id = u.id
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY al.created_at DESC, al.id DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "failed to query activity logs", nil)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l domain.ActivityLog
+		var ticketID sql.NullInt64
+		err := rows.Scan(
+			&l.ID,
+			&ticketID,
+			&l.UserID,
+			&l.Username,
+			&l.Action,
+			scanTime(&l.CreatedAt),
+		)
+		if err != nil {
+			return translateError(err, "failed to scan activity log", nil)
+		}
+		l.TicketID = nullInt(ticketID)
+		if err := fn(&l); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return translateError(err, "failed to read activity logs", nil)
+	}
+	return nil
+}
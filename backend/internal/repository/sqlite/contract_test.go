@@ -0,0 +1,50 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"go-todolist/internal/repository/repotest"
+	"path/filepath"
+	"testing"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repositories {
+		db, err := Open(context.Background(), filepath.Join(t.TempDir(), "contract.db"))
+		if err != nil {
+			t.Fatalf("open: %v", err)
+		}
+		t.Cleanup(func() { db.Close() })
+
+		return repotest.Repositories{
+			Users:        NewUserRepository(db),
+			Tickets:      NewTicketRepository(db),
+			Todos:        NewTodoRepository(db),
+			ActivityLogs: NewActivityLogRepository(db),
+			SavedFilters: NewSavedFilterRepository(db),
+		}
+	})
+}
+
+func TestPendingMigrations(t *testing.T) {
+	ctx := context.Background()
+	db, err := Open(ctx, filepath.Join(t.TempDir(), "migrations.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+
+	pending, err := PendingMigrations(ctx, db)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("pending after Open = %v, %v, want none", pending, err)
+	}
+
+	if _, err := db.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = '0003_keep_purged_activity'"); err != nil {
+		t.Fatal(err)
+	}
+	pending, err = PendingMigrations(ctx, db)
+	if err != nil || len(pending) != 1 || pending[0] != "0003_keep_purged_activity" {
+		t.Fatalf("pending = %v, %v, want the removed version", pending, err)
+	}
+}
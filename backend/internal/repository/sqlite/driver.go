@@ -0,0 +1,11 @@
+//go:build !sqlite
+
+package sqlite
+
+// driverName is empty in builds without the sqlite tag, Open then reports that SQLite
+// support was not compiled in
+const driverName = ""
+
+func dataSourceName(path string) string {
+	return path
+}
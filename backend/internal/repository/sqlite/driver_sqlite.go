@@ -0,0 +1,21 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"net/url"
+
+	// pure Go driver, no cgo needed
+	_ "modernc.org/sqlite"
+)
+
+const driverName = "sqlite"
+
+// dataSourceName turns on foreign keys and WAL for every connection the pool opens
+func dataSourceName(path string) string {
+	params := url.Values{}
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "busy_timeout(5000)")
+	return "file:" + path + "?" + params.Encode()
+}
@@ -0,0 +1,3 @@
+-- User roles and disabled accounts, managed with "server admin"
+ALTER TABLE users ADD COLUMN role TEXT NOT NULL DEFAULT 'user';
+ALTER TABLE users ADD COLUMN disabled_at TEXT;
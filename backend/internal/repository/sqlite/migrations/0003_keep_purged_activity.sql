@@ -0,0 +1,16 @@
+-- Keep the activity history of purged tickets, their entries lose the ticket reference
+-- instead of being deleted with the ticket. SQLite cannot change a foreign key in
+-- place, so the table is rebuilt.
+CREATE TABLE activity_logs_new (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_id INTEGER REFERENCES tickets(id) ON DELETE SET NULL,
+    user_id INTEGER REFERENCES users(id),
+    action TEXT NOT NULL,
+    created_at TEXT NOT NULL
+);
+
+INSERT INTO activity_logs_new (id, ticket_id, user_id, action, created_at)
+SELECT id, ticket_id, user_id, action, created_at FROM activity_logs;
+
+DROP TABLE activity_logs;
+ALTER TABLE activity_logs_new RENAME TO activity_logs;
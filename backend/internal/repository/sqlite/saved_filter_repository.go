@@ -0,0 +1,172 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"go-todolist/internal/domain"
+)
+
+type savedFilterRepository struct {
+	db *sql.DB
+}
+
+func NewSavedFilterRepository(db *sql.DB) domain.SavedFilterRepository {
+	return &savedFilterRepository{db: db}
+}
+
+func (r *savedFilterRepository) Create(ctx context.Context, filter *domain.SavedFilter) error {
+	query := `
+		INSERT INTO saved_filters (user_id, name, criteria, shared, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	criteria, err := json.Marshal(filter.Criteria)
+	if err != nil {
+		return translateError(err, "failed to encode filter criteria", nil)
+	}
+
+	now := now()
+	filter.CreatedAt = now
+	filter.UpdatedAt = now
+
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		filter.UserID,
+		filter.Name,
+		string(criteria),
+		filter.Shared,
+		formatTime(filter.CreatedAt),
+		formatTime(filter.UpdatedAt),
+	).Scan(&filter.ID)
+
+	if err != nil {
+		return translateError(err, "failed to create saved filter", nil)
+	}
+
+	return nil
+}
+
+func (r *savedFilterRepository) FindByID(ctx context.Context, id int) (*domain.SavedFilter, error) {
+	query := `
+		SELECT f.id, f.user_id, u.username, f.name, f.criteria, f.shared, f.created_at, f.updated_at
+		FROM saved_filters f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.id = ?
+	`
+
+	f := &domain.SavedFilter{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&f.ID,
+		&f.UserID,
+		&f.Username,
+		&f.Name,
+		&criteriaColumn{&f.Criteria},
+		&f.Shared,
+		scanTime(&f.CreatedAt),
+		scanTime(&f.UpdatedAt),
+	)
+
+	if err != nil {
+		return nil, translateError(err, "failed to find saved filter", domain.ErrSavedFilterNotFound)
+	}
+
+	return f, nil
+}
+
+func (r *savedFilterRepository) FindVisibleTo(ctx context.Context, userID int) ([]domain.SavedFilter, error) {
+	query := `
+		SELECT f.id, f.user_id, u.username, f.name, f.criteria, f.shared, f.created_at, f.updated_at
+		FROM saved_filters f
+		JOIN users u ON f.user_id = u.id
+		WHERE f.user_id = ? OR f.shared
+		ORDER BY f.name ASC, f.id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, translateError(err, "failed to query saved filters", nil)
+	}
+	defer rows.Close()
+
+	var filters []domain.SavedFilter
+	for rows.Next() {
+		var f domain.SavedFilter
+		err := rows.Scan(
+			&f.ID,
+			&f.UserID,
+			&f.Username,
+			&f.Name,
+			&criteriaColumn{&f.Criteria},
+			&f.Shared,
+			scanTime(&f.CreatedAt),
+			scanTime(&f.UpdatedAt),
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan saved filter", nil)
+		}
+		filters = append(filters, f)
+	}
+
+	return filters, rows.Err()
+}
+
+func (r *savedFilterRepository) Update(ctx context.Context, filter *domain.SavedFilter) error {
+	query := `
+		UPDATE saved_filters
+		SET name = ?, criteria = ?, shared = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	criteria, err := json.Marshal(filter.Criteria)
+	if err != nil {
+		return translateError(err, "failed to encode filter criteria", nil)
+	}
+
+	filter.UpdatedAt = now()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		filter.Name,
+		string(criteria),
+		filter.Shared,
+		formatTime(filter.UpdatedAt),
+		filter.ID,
+	)
+
+	if err != nil {
+		return translateError(err, "failed to update saved filter", nil)
+	}
+
+	return expectRow(result, domain.ErrSavedFilterNotFound)
+}
+
+func (r *savedFilterRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_filters WHERE id = ?`, id)
+	if err != nil {
+		return translateError(err, "failed to delete saved filter", nil)
+	}
+
+	return expectRow(result, domain.ErrSavedFilterNotFound)
+}
+
+// criteriaColumn decodes the JSON criteria column
+type criteriaColumn struct {
+	dest *domain.TicketFilter
+}
+
+func (c *criteriaColumn) Scan(src any) error {
+	var raw []byte
+	switch v := src.(type) {
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return json.Unmarshal([]byte("{}"), c.dest)
+	}
+	return json.Unmarshal(raw, c.dest)
+}
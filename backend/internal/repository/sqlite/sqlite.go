@@ -0,0 +1,284 @@
+// Package sqlite implements the domain repositories on SQLite through database/sql.
+//
+// The modernc.org/sqlite driver is only linked in when building with -tags sqlite, so
+// default builds stay small; config.Validate rejects storage sqlite without it.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"go-todolist/internal/domain"
+	"io/fs"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// ErrNotCompiled is returned by Open in builds without the sqlite tag
+var ErrNotCompiled = errors.New("sqlite support is not compiled in, rebuild with -tags sqlite")
+
+// Open opens the database file at path and applies pending migrations. SQLite allows
+// one writer at a time, so the pool is limited to a single connection.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if driverName == "" {
+		return nil, ErrNotCompiled
+	}
+
+	db, err := sql.Open(driverName, dataSourceName(path))
+	if err != nil {
+		return nil, fmt.Errorf("unable to open sqlite database: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to open sqlite database: %w", err)
+	}
+
+	if err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	slog.Info("opened sqlite database", "path", path)
+	return db, nil
+}
+
+// Migrate applies every embedded migration not yet recorded in schema_migrations,
+// each in its own transaction and in file name order
+func Migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version TEXT PRIMARY KEY,
+			applied_at TEXT NOT NULL
+		)
+	`); err != nil {
+		return fmt.Errorf("unable to create schema_migrations: %w", err)
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return err
+	}
+	versions, err := migrationVersions()
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if applied[version] {
+			continue
+		}
+
+		content, err := migrationFiles.ReadFile("migrations/" + version + ".sql")
+		if err != nil {
+			return fmt.Errorf("unable to read migration %s: %w", version, err)
+		}
+
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("unable to begin migration %s: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, string(content)); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %s failed: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, applied_at) VALUES (?, ?)", version, formatTime(time.Now())); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("unable to record migration %s: %w", version, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("unable to commit migration %s: %w", version, err)
+		}
+
+		slog.Info("applied migration", "version", version)
+	}
+
+	return nil
+}
+
+// PendingMigrations lists the embedded migrations the database has not applied yet
+func PendingMigrations(ctx context.Context, db *sql.DB) ([]string, error) {
+	versions, err := migrationVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations')").Scan(&exists); err != nil {
+		return nil, fmt.Errorf("unable to check schema_migrations: %w", err)
+	}
+	if !exists {
+		return versions, nil
+	}
+
+	applied, err := appliedVersions(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, version := range versions {
+		if !applied[version] {
+			pending = append(pending, version)
+		}
+	}
+	return pending, nil
+}
+
+// migrationVersions lists the embedded migrations in file name order
+func migrationVersions() ([]string, error) {
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("unable to read migrations: %w", err)
+	}
+
+	var versions []string
+	for _, entry := range entries {
+		versions = append(versions, strings.TrimSuffix(entry.Name(), ".sql"))
+	}
+	sort.Strings(versions)
+	return versions, nil
+}
+
+func appliedVersions(ctx context.Context, db *sql.DB) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("unable to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("unable to scan migration version: %w", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// translateError mirrors the Postgres repositories: missing rows become notFound and
+// constraint violations become conflict or validation errors. Drivers differ in error
+// types but agree on SQLite's messages, so those are matched.
+func translateError(err error, op string, notFound *domain.Error) error {
+	if errors.Is(err, sql.ErrNoRows) && notFound != nil {
+		return notFound
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed"):
+		return &domain.Error{Kind: domain.ErrConflict, Code: "already_exists", Message: "Resource already exists", Err: err}
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return &domain.Error{Kind: domain.ErrValidation, Code: "invalid_reference", Message: "Referenced record does not exist", Err: err}
+	}
+
+	return fmt.Errorf("%s: %w", op, err)
+}
+
+// timeLayout is fixed width so text comparison orders timestamps chronologically
+const timeLayout = "2006-01-02 15:04:05.000000"
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+func formatNullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return formatTime(*t)
+}
+
+// now matches the precision of the stored text
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// timeColumn scans a timestamp column into a *time.Time, or a **time.Time for
+// nullable columns
+type timeColumn struct {
+	dest     *time.Time
+	nullable **time.Time
+}
+
+func scanTime(dest *time.Time) *timeColumn      { return &timeColumn{dest: dest} }
+func scanNullTime(dest **time.Time) *timeColumn { return &timeColumn{nullable: dest} }
+
+func (c *timeColumn) Scan(src any) error {
+	var t time.Time
+	switch v := src.(type) {
+	case nil:
+		if c.nullable == nil {
+			return errors.New("unexpected NULL timestamp")
+		}
+		*c.nullable = nil
+		return nil
+	case time.Time:
+		t = v.UTC()
+	case string:
+		parsed, err := time.Parse(timeLayout, v)
+		if err != nil {
+			return err
+		}
+		t = parsed
+	case []byte:
+		parsed, err := time.Parse(timeLayout, string(v))
+		if err != nil {
+			return err
+		}
+		t = parsed
+	default:
+		return fmt.Errorf("cannot scan %T into a timestamp", src)
+	}
+
+	if c.nullable != nil {
+		*c.nullable = &t
+	} else {
+		*c.dest = t
+	}
+	return nil
+}
+
+// nullString scans a nullable TEXT column into a plain string
+type nullString struct {
+	dest *string
+}
+
+func (n *nullString) Scan(src any) error {
+	var s sql.NullString
+	if err := s.Scan(src); err != nil {
+		return err
+	}
+	*n.dest = s.String
+	return nil
+}
+
+// expectRow returns notFound when an UPDATE or DELETE touched no row
+func expectRow(result sql.Result, notFound error) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return notFound
+	}
+	return nil
+}
+
+func nullInt(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int64)
+	return &i
+}
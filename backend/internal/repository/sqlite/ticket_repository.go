@@ -0,0 +1,586 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/repository/textsearch"
+	"sort"
+	"strings"
+	"time"
+)
+
+type ticketRepository struct {
+	db *sql.DB
+}
+
+func NewTicketRepository(db *sql.DB) domain.TicketRepository {
+	return &ticketRepository{db: db}
+}
+
+const selectTickets = `
+	SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username AS creator_username, t.assignee_id, u2.username AS assignee_username, t.created_at, t.updated_at, t.deleted_at
+	FROM tickets t
+	JOIN users u1 ON t.creator_id = u1.id
+	LEFT JOIN users u2 ON t.assignee_id = u2.id
+`
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTicket(row rowScanner) (domain.Ticket, error) {
+	var t domain.Ticket
+	var assigneeID sql.NullInt64
+	var assigneeUsername sql.NullString
+
+	err := row.Scan(
+		&t.ID,
+		&t.Title,
+		&nullString{&t.Description},
+		&t.Status,
+		&t.Priority,
+		scanNullTime(&t.DueDate),
+		&t.CreatorID,
+		&t.CreatorUsername,
+		&assigneeID,
+		&assigneeUsername,
+		scanTime(&t.CreatedAt),
+		scanTime(&t.UpdatedAt),
+		scanNullTime(&t.DeletedAt),
+	)
+	if err != nil {
+		return t, err
+	}
+
+	t.AssigneeID = nullInt(assigneeID)
+	if assigneeUsername.Valid {
+		t.AssigneeUsername = &assigneeUsername.String
+	}
+	return t, nil
+}
+
+func (r *ticketRepository) queryTickets(ctx context.Context, op string, query string, args ...any) ([]domain.Ticket, error) {
+	var tickets []domain.Ticket
+	err := r.streamTickets(ctx, op, query, args, func(t *domain.Ticket) error {
+		tickets = append(tickets, *t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// streamTickets calls fn for each row of query, errors from fn are returned as is
+func (r *ticketRepository) streamTickets(ctx context.Context, op string, query string, args []any, fn func(*domain.Ticket) error) error {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return translateError(err, op, nil)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t, err := scanTicket(rows)
+		if err != nil {
+			return translateError(err, "failed to scan ticket", nil)
+		}
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return translateError(err, op, nil)
+	}
+	return nil
+}
+
+func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) error {
+	query := `
+		INSERT INTO tickets (title, description, status, priority, due_date, creator_id, assignee_id, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	now := now()
+	ticket.CreatedAt = now
+	ticket.UpdatedAt = now
+
+	if ticket.Status == "" {
+		ticket.Status = domain.StatusBacklog
+	}
+	if ticket.Priority == "" {
+		ticket.Priority = domain.PriorityMedium
+	}
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		ticket.Title,
+		ticket.Description,
+		ticket.Status,
+		ticket.Priority,
+		formatNullTime(ticket.DueDate),
+		ticket.CreatorID,
+		ticket.AssigneeID,
+		formatTime(ticket.CreatedAt),
+		formatTime(ticket.UpdatedAt),
+	).Scan(&ticket.ID)
+
+	if err != nil {
+		return translateError(err, "failed to create ticket", nil)
+	}
+
+	return nil
+}
+
+func (r *ticketRepository) FindAll(ctx context.Context) ([]domain.Ticket, error) {
+	query := selectTickets + `
+		WHERE t.deleted_at IS NULL
+		ORDER BY t.created_at DESC, t.id DESC
+	`
+	return r.queryTickets(ctx, "failed to query tickets", query)
+}
+
+// FindByFilter lists tickets matching every criterion set on filter. AssignedToMe and
+// DueWithinDays are relative and must be resolved by the caller before reaching here.
+func (r *ticketRepository) FindByFilter(ctx context.Context, filter domain.TicketFilter) ([]domain.Ticket, error) {
+	query, args := filterQuery(filter)
+	return r.queryTickets(ctx, "failed to query tickets", query, args...)
+}
+
+// StreamByFilter calls fn for every ticket FindByFilter would return, reading the rows
+// as they arrive. An error from fn stops the iteration and is returned as is.
+func (r *ticketRepository) StreamByFilter(ctx context.Context, filter domain.TicketFilter, fn func(*domain.Ticket) error) error {
+	query, args := filterQuery(filter)
+	return r.streamTickets(ctx, "failed to query tickets", query, args, fn)
+}
+
+func filterQuery(filter domain.TicketFilter) (string, []any) {
+	conditions := []string{"t.deleted_at IS NULL"}
+	var args []any
+
+	if len(filter.Statuses) > 0 {
+		conditions = append(conditions, "t.status IN ("+placeholders(len(filter.Statuses))+")")
+		for _, s := range filter.Statuses {
+			args = append(args, s)
+		}
+	}
+	if len(filter.Priorities) > 0 {
+		conditions = append(conditions, "t.priority IN ("+placeholders(len(filter.Priorities))+")")
+		for _, p := range filter.Priorities {
+			args = append(args, p)
+		}
+	}
+	if filter.AssigneeID != nil {
+		conditions = append(conditions, "t.assignee_id = ?")
+		args = append(args, *filter.AssigneeID)
+	}
+	if filter.CreatorID != nil {
+		conditions = append(conditions, "t.creator_id = ?")
+		args = append(args, *filter.CreatorID)
+	}
+	if filter.DueAfter != nil {
+		conditions = append(conditions, "t.due_date >= ?")
+		args = append(args, formatTime(*filter.DueAfter))
+	}
+	if filter.DueBefore != nil {
+		conditions = append(conditions, "t.due_date < ?")
+		args = append(args, formatTime(*filter.DueBefore))
+	}
+
+	query := selectTickets + `
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY t.created_at DESC, t.id DESC
+	`
+	return query, args
+}
+
+func (r *ticketRepository) FindByStatus(ctx context.Context, status string) ([]domain.Ticket, error) {
+	query := selectTickets + `
+		WHERE t.status = ? AND t.deleted_at IS NULL
+		ORDER BY t.created_at DESC, t.id DESC
+	`
+	return r.queryTickets(ctx, "failed to query tickets by status", query, status)
+}
+
+func (r *ticketRepository) FindByID(ctx context.Context, id int) (*domain.Ticket, error) {
+	query := selectTickets + `WHERE t.id = ? AND t.deleted_at IS NULL`
+
+	t, err := scanTicket(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		return nil, translateError(err, "failed to find ticket", domain.ErrTicketNotFound)
+	}
+
+	return &t, nil
+}
+
+func (r *ticketRepository) Update(ctx context.Context, ticket *domain.Ticket, editorID int) error {
+	return r.update(ctx, r.db, ticket, editorID, now())
+}
+
+func (r *ticketRepository) Patch(ctx context.Context, id int, changes domain.TicketChanges, editorID int) error {
+	return r.patch(ctx, r.db, id, changes, editorID, now(), domain.ErrTicketNotFound)
+}
+
+func (r *ticketRepository) patch(ctx context.Context, db execer, id int, changes domain.TicketChanges, editorID int, updatedAt time.Time, missing error) error {
+	set, args := ticketChangeSet(changes, updatedAt)
+	query := `UPDATE tickets SET ` + strings.Join(set, ", ") + ` WHERE id = ? AND deleted_at IS NULL AND ` + editableBy
+
+	result, err := db.ExecContext(ctx, query, append(args, id, editorID, editorID, editorID)...)
+	if err != nil {
+		return translateError(err, "failed to patch ticket", nil)
+	}
+
+	return expectEditableRow(ctx, db, result, id, false, missing)
+}
+
+// editableBy is the condition limiting a write to the tickets the editor may modify,
+// see domain.Ticket.EditableBy. It takes the editor ID three times.
+const editableBy = `(? = 0 OR creator_id = ? OR assignee_id = ?)`
+
+// expectEditableRow checks that a write of ticket id matched a row, explaining a miss:
+// the ticket is missing, or not in the trash state the write expects, or the editor
+// may not modify it
+func expectEditableRow(ctx context.Context, db execer, result sql.Result, id int, trashed bool, missing error) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+
+	var found bool
+	err = db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM tickets WHERE id = ? AND (deleted_at IS NOT NULL) = ?)`, id, trashed).Scan(&found)
+	if err != nil {
+		return translateError(err, "failed to check ticket", nil)
+	}
+	if found {
+		return domain.ErrTicketForbidden
+	}
+	return missing
+}
+
+// ticketChangeSet renders the assignments of a partial update with their arguments,
+// always including updated_at
+func ticketChangeSet(changes domain.TicketChanges, updatedAt time.Time) ([]string, []any) {
+	var set []string
+	var args []any
+	assign := func(column string, value any) {
+		set = append(set, column+" = ?")
+		args = append(args, value)
+	}
+
+	if changes.Title != nil {
+		assign("title", *changes.Title)
+	}
+	if changes.Description != nil {
+		assign("description", *changes.Description)
+	}
+	if changes.Status != nil {
+		assign("status", *changes.Status)
+	}
+	if changes.Priority != nil {
+		assign("priority", *changes.Priority)
+	}
+	if changes.DueDate.Set {
+		assign("due_date", formatNullTime(changes.DueDate.Value))
+	}
+	if changes.AssigneeID.Set {
+		assign("assignee_id", changes.AssigneeID.Value)
+	}
+	assign("updated_at", formatTime(updatedAt))
+
+	return set, args
+}
+
+// execer is satisfied by *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func (r *ticketRepository) update(ctx context.Context, db execer, ticket *domain.Ticket, editorID int, updatedAt time.Time) error {
+	query := `
+		UPDATE tickets
+		SET title = ?, description = ?, status = ?, priority = ?, due_date = ?, assignee_id = ?, updated_at = ?
+		WHERE id = ? AND deleted_at IS NULL AND ` + editableBy
+
+	ticket.UpdatedAt = updatedAt
+
+	result, err := db.ExecContext(
+		ctx,
+		query,
+		ticket.Title,
+		ticket.Description,
+		ticket.Status,
+		ticket.Priority,
+		formatNullTime(ticket.DueDate),
+		ticket.AssigneeID,
+		formatTime(ticket.UpdatedAt),
+		ticket.ID,
+		editorID, editorID, editorID,
+	)
+
+	if err != nil {
+		return translateError(err, "failed to update ticket", nil)
+	}
+
+	return expectEditableRow(ctx, db, result, ticket.ID, false, domain.ErrTicketNotFound)
+}
+
+func (r *ticketRepository) Delete(ctx context.Context, id int, editorID int) error {
+	query := `UPDATE tickets SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL AND ` + editableBy
+
+	result, err := r.db.ExecContext(ctx, query, formatTime(now()), id, editorID, editorID, editorID)
+	if err != nil {
+		return translateError(err, "failed to delete ticket", nil)
+	}
+
+	return expectEditableRow(ctx, r.db, result, id, false, domain.ErrTicketNotFound)
+}
+
+func (r *ticketRepository) FindTrashed(ctx context.Context) ([]domain.Ticket, error) {
+	query := selectTickets + `
+		WHERE t.deleted_at IS NOT NULL
+		ORDER BY t.deleted_at DESC, t.id DESC
+	`
+	return r.queryTickets(ctx, "failed to query trashed tickets", query)
+}
+
+func (r *ticketRepository) Restore(ctx context.Context, id int, editorID int) error {
+	query := `UPDATE tickets SET deleted_at = NULL, updated_at = ? WHERE id = ? AND deleted_at IS NOT NULL AND ` + editableBy
+
+	result, err := r.db.ExecContext(ctx, query, formatTime(now()), id, editorID, editorID, editorID)
+	if err != nil {
+		return translateError(err, "failed to restore ticket", nil)
+	}
+
+	return expectEditableRow(ctx, r.db, result, id, true, domain.NewNotFoundError("ticket_not_in_trash", "Ticket not found in trash"))
+}
+
+func (r *ticketRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `DELETE FROM tickets WHERE deleted_at IS NOT NULL AND deleted_at < ?`
+
+	result, err := r.db.ExecContext(ctx, query, formatTime(deletedBefore))
+	if err != nil {
+		return 0, translateError(err, "failed to purge tickets", nil)
+	}
+
+	return result.RowsAffected()
+}
+
+func (r *ticketRepository) UpdateStatus(ctx context.Context, id int, status string, editorID int) error {
+	query := `UPDATE tickets SET status = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL AND ` + editableBy
+
+	result, err := r.db.ExecContext(ctx, query, status, formatTime(now()), id, editorID, editorID, editorID)
+	if err != nil {
+		return translateError(err, "failed to update ticket status", nil)
+	}
+
+	return expectEditableRow(ctx, r.db, result, id, false, domain.ErrTicketNotFound)
+}
+
+// PatchMany applies the same changes to every ticket in ids and stores logs in a
+// single transaction, rolled back when one of the tickets is missing or may not be
+// modified by editorID
+func (r *ticketRepository) PatchMany(ctx context.Context, ids []int, changes domain.TicketChanges, logs []*domain.ActivityLog, editorID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err, "failed to begin transaction", nil)
+	}
+	defer tx.Rollback()
+
+	now := now()
+	for _, id := range ids {
+		missing := domain.NewNotFoundError("ticket_not_found", fmt.Sprintf("Ticket %d not found", id))
+		if err := r.patch(ctx, tx, id, changes, editorID, now, missing); err != nil {
+			return err
+		}
+	}
+
+	for _, log := range logs {
+		log.CreatedAt = now
+		err := tx.QueryRowContext(ctx, insertActivityLog, log.TicketID, log.UserID, log.Action, formatTime(now)).Scan(&log.ID)
+		if err != nil {
+			return translateError(err, "failed to create activity log", nil)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return translateError(err, "failed to commit bulk update", nil)
+	}
+
+	return nil
+}
+
+// Import stores tickets and their history with the timestamps they carry in a single
+// transaction, filling in the ids
+func (r *ticketRepository) Import(ctx context.Context, tickets []domain.TicketImport) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return translateError(err, "failed to begin transaction", nil)
+	}
+	defer tx.Rollback()
+
+	insertTicket, err := tx.PrepareContext(ctx, `
+		INSERT INTO tickets (title, description, status, priority, due_date, creator_id, assignee_id, created_at, updated_at, deleted_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`)
+	if err != nil {
+		return translateError(err, "failed to prepare ticket import", nil)
+	}
+	defer insertTicket.Close()
+
+	insertLog, err := tx.PrepareContext(ctx, insertActivityLog)
+	if err != nil {
+		return translateError(err, "failed to prepare activity log import", nil)
+	}
+	defer insertLog.Close()
+
+	for _, item := range tickets {
+		ticket := item.Ticket
+		if ticket.Status == "" {
+			ticket.Status = domain.StatusBacklog
+		}
+		if ticket.Priority == "" {
+			ticket.Priority = domain.PriorityMedium
+		}
+
+		err := insertTicket.QueryRowContext(
+			ctx,
+			ticket.Title,
+			ticket.Description,
+			ticket.Status,
+			ticket.Priority,
+			formatNullTime(ticket.DueDate),
+			ticket.CreatorID,
+			ticket.AssigneeID,
+			formatTime(ticket.CreatedAt),
+			formatTime(ticket.UpdatedAt),
+			formatNullTime(ticket.DeletedAt),
+		).Scan(&ticket.ID)
+		if err != nil {
+			return translateError(err, "failed to import ticket", nil)
+		}
+
+		for _, log := range item.History {
+			log.TicketID = &ticket.ID
+			err := insertLog.QueryRowContext(ctx, log.TicketID, log.UserID, log.Action, formatTime(log.CreatedAt)).Scan(&log.ID)
+			if err != nil {
+				return translateError(err, "failed to import activity log", nil)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return translateError(err, "failed to commit import", nil)
+	}
+
+	return nil
+}
+
+// Search has no tsvector to lean on: SQL narrows candidates with LIKE, then
+// textsearch applies the prefix matching, ranking and highlighting
+func (r *ticketRepository) Search(ctx context.Context, params domain.TicketSearchParams) ([]domain.TicketSearchResult, error) {
+	terms := textsearch.Terms(params.Query)
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	conditions := []string{"t.deleted_at IS NULL"}
+	var args []any
+	for _, term := range terms {
+		conditions = append(conditions, "(lower(t.title) LIKE ? ESCAPE '\\' OR lower(COALESCE(t.description, '')) LIKE ? ESCAPE '\\')")
+		pattern := "%" + escapeLike(term) + "%"
+		args = append(args, pattern, pattern)
+	}
+	if params.Status != "" {
+		conditions = append(conditions, "t.status = ?")
+		args = append(args, params.Status)
+	}
+	if params.AssigneeID != nil {
+		conditions = append(conditions, "t.assignee_id = ?")
+		args = append(args, *params.AssigneeID)
+	}
+
+	candidates, err := r.queryTickets(ctx, "failed to search tickets", selectTickets+"WHERE "+strings.Join(conditions, " AND "), args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []domain.TicketSearchResult
+	for _, t := range candidates {
+		match, ok := textsearch.Match(terms, t.Title, t.Description)
+		if !ok {
+			continue
+		}
+		results = append(results, domain.TicketSearchResult{
+			Ticket:         t,
+			Rank:           match.Rank,
+			TitleHighlight: match.TitleHighlight,
+			Snippet:        match.Snippet,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		if !results[i].UpdatedAt.Equal(results[j].UpdatedAt) {
+			return results[i].UpdatedAt.After(results[j].UpdatedAt)
+		}
+		return results[i].ID > results[j].ID
+	})
+
+	if limit := max(params.Limit, 0); len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
+
+// CountByStatus returns the number of live tickets per status
+func (r *ticketRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT status, COUNT(*) FROM tickets WHERE deleted_at IS NULL GROUP BY status`)
+	if err != nil {
+		return nil, translateError(err, "failed to count tickets by status", nil)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, translateError(err, "failed to scan ticket count", nil)
+		}
+		counts[status] = count
+	}
+
+	return counts, rows.Err()
+}
+
+func (r *ticketRepository) CountTrashed(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM tickets WHERE deleted_at IS NOT NULL`).Scan(&count)
+	if err != nil {
+		return 0, translateError(err, "failed to count trashed tickets", nil)
+	}
+
+	return count, nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"go-todolist/internal/domain"
+)
+
+type todoRepository struct {
+	db *sql.DB
+}
+
+func NewTodoRepository(db *sql.DB) domain.TodoRepository {
+	return &todoRepository{db: db}
+}
+
+func (r *todoRepository) Create(ctx context.Context, todo *domain.Todo) error {
+	query := `
+		INSERT INTO todos (user_id, title, description, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	now := now()
+	todo.CreatedAt = now
+	todo.UpdatedAt = now
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		todo.UserID,
+		todo.Title,
+		todo.Description,
+		todo.Status,
+		formatTime(todo.CreatedAt),
+		formatTime(todo.UpdatedAt),
+	).Scan(&todo.ID)
+
+	if err != nil {
+		return translateError(err, "failed to create todo", nil)
+	}
+
+	return nil
+}
+
+func (r *todoRepository) FindAll(ctx context.Context) ([]domain.Todo, error) {
+	query := `
+		SELECT id, user_id, title, description, status, created_at, updated_at
+		FROM todos
+		ORDER BY id ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "failed to query todos", nil)
+	}
+	defer rows.Close()
+
+	var todos []domain.Todo
+	for rows.Next() {
+		var todo domain.Todo
+		err := rows.Scan(
+			&todo.ID,
+			&todo.UserID,
+			&todo.Title,
+			&nullString{&todo.Description},
+			&todo.Status,
+			scanTime(&todo.CreatedAt),
+			scanTime(&todo.UpdatedAt),
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan todo", nil)
+		}
+		todos = append(todos, todo)
+	}
+
+	return todos, rows.Err()
+}
+
+func (r *todoRepository) FindById(ctx context.Context, id int) (*domain.Todo, error) {
+	query := `
+		SELECT id, user_id, title, description, status, created_at, updated_at
+		FROM todos
+		WHERE id = ?
+	`
+
+	todo := &domain.Todo{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&todo.ID,
+		&todo.UserID,
+		&todo.Title,
+		&nullString{&todo.Description},
+		&todo.Status,
+		scanTime(&todo.CreatedAt),
+		scanTime(&todo.UpdatedAt),
+	)
+
+	if err != nil {
+		return nil, translateError(err, "failed to find todo", domain.ErrTodoNotFound)
+	}
+
+	return todo, nil
+}
+
+func (r *todoRepository) Update(ctx context.Context, todo *domain.Todo) error {
+	query := `
+		UPDATE todos
+		SET user_id = ?, title = ?, description = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	todo.UpdatedAt = now()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		todo.UserID,
+		todo.Title,
+		todo.Description,
+		todo.Status,
+		formatTime(todo.UpdatedAt),
+		todo.ID,
+	)
+
+	if err != nil {
+		return translateError(err, "failed to update todo", nil)
+	}
+
+	return expectRow(result, domain.ErrTodoNotFound)
+}
+
+func (r *todoRepository) Delete(ctx context.Context, id int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM todos WHERE id = ?`, id)
+	if err != nil {
+		return translateError(err, "failed to delete todo", nil)
+	}
+
+	return expectRow(result, domain.ErrTodoNotFound)
+}
@@ -0,0 +1,189 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go-todolist/internal/domain"
+	"time"
+)
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) domain.UserRepository {
+	return &userRepository{db: db}
+}
+
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
+	query := `
+		INSERT INTO users (username, password, profile_photo, role, disabled_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`
+
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+
+	now := now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	err := r.db.QueryRowContext(
+		ctx,
+		query,
+		user.Username,
+		user.Password,
+		user.ProfilePhoto,
+		user.Role,
+		formatNullTime(user.DisabledAt),
+		formatTime(user.CreatedAt),
+		formatTime(user.UpdatedAt),
+	).Scan(&user.ID)
+
+	if err != nil {
+		err = translateError(err, "failed to create user", nil)
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.NewConflictError("username_taken", "Username already exists")
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
+	query := `
+		SELECT id, username, password, profile_photo, role, disabled_at, created_at, updated_at
+		FROM users
+		WHERE username = ?
+	`
+
+	user := &domain.User{}
+	err := r.db.QueryRowContext(ctx, query, username).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&nullString{&user.ProfilePhoto},
+		&user.Role,
+		scanNullTime(&user.DisabledAt),
+		scanTime(&user.CreatedAt),
+		scanTime(&user.UpdatedAt),
+	)
+
+	if err != nil {
+		return nil, translateError(err, "failed to find user", domain.ErrUserNotFound)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id int) (*domain.User, error) {
+	query := `
+		SELECT id, username, password, profile_photo, role, disabled_at, created_at, updated_at
+		FROM users
+		WHERE id = ?
+	`
+
+	user := &domain.User{}
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&user.ID,
+		&user.Username,
+		&user.Password,
+		&nullString{&user.ProfilePhoto},
+		&user.Role,
+		scanNullTime(&user.DisabledAt),
+		scanTime(&user.CreatedAt),
+		scanTime(&user.UpdatedAt),
+	)
+
+	if err != nil {
+		return nil, translateError(err, "failed to find user", domain.ErrUserNotFound)
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+	query := `
+		SELECT id, username, profile_photo, role, disabled_at, created_at, updated_at
+		FROM users
+		ORDER BY username ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "failed to query users", nil)
+	}
+	defer rows.Close()
+
+	var users []domain.User
+	for rows.Next() {
+		var u domain.User
+		err := rows.Scan(
+			&u.ID,
+			&u.Username,
+			&nullString{&u.ProfilePhoto},
+			&u.Role,
+			scanNullTime(&u.DisabledAt),
+			scanTime(&u.CreatedAt),
+			scanTime(&u.UpdatedAt),
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan user", nil)
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
+	query := `
+		UPDATE users
+		SET username = ?, password = ?, profile_photo = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	user.UpdatedAt = now()
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		user.Username,
+		user.Password,
+		user.ProfilePhoto,
+		formatTime(user.UpdatedAt),
+		user.ID,
+	)
+
+	if err != nil {
+		err = translateError(err, "failed to update user", nil)
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.NewConflictError("username_taken", "Username already exists")
+		}
+		return err
+	}
+
+	return expectRow(result, domain.ErrUserNotFound)
+}
+
+func (r *userRepository) SetRole(ctx context.Context, id int, role string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET role = ?, updated_at = ? WHERE id = ?`, role, formatTime(now()), id)
+	if err != nil {
+		return translateError(err, "failed to set user role", nil)
+	}
+
+	return expectRow(result, domain.ErrUserNotFound)
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, id int, disabledAt *time.Time) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET disabled_at = ?, updated_at = ? WHERE id = ?`, formatNullTime(disabledAt), formatTime(now()), id)
+	if err != nil {
+		return translateError(err, "failed to set user disabled", nil)
+	}
+
+	return expectRow(result, domain.ErrUserNotFound)
+}
@@ -0,0 +1,156 @@
+// Package textsearch approximates the PostgreSQL ticket search for storage backends
+// without tsvector support: every query word must prefix-match a word of the title or
+// description, title hits rank above description hits, and matches are wrapped in
+// <mark> like ts_headline does.
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	titleWeight       = 1.0
+	descriptionWeight = 0.4
+
+	// snippetWords mirrors MaxWords=30 of the ts_headline call
+	snippetWords = 30
+
+	markStart = "<mark>"
+	markStop  = "</mark>"
+)
+
+// Terms splits free text into lowercase search terms, matching buildPrefixTSQuery
+func Terms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), isSeparator)
+}
+
+// Result is the outcome of matching one ticket
+type Result struct {
+	Rank           float32
+	TitleHighlight string
+	Snippet        string
+}
+
+// Match reports whether every term prefix-matches a word of title or description and,
+// if so, ranks and highlights the ticket
+func Match(terms []string, title, description string) (Result, bool) {
+	if len(terms) == 0 {
+		return Result{}, false
+	}
+
+	titleWords := words(title)
+	descWords := words(description)
+
+	var rank float64
+	for _, term := range terms {
+		titleHits := countPrefixed(titleWords, term)
+		descHits := countPrefixed(descWords, term)
+		if titleHits == 0 && descHits == 0 {
+			return Result{}, false
+		}
+		rank += titleWeight*float64(titleHits) + descriptionWeight*float64(descHits)
+	}
+
+	return Result{
+		Rank:           float32(rank / float64(len(terms)+len(titleWords)+len(descWords))),
+		TitleHighlight: highlight(title, titleWords, terms, 0, len(titleWords)),
+		Snippet:        snippet(description, descWords, terms),
+	}, true
+}
+
+// word is the byte range of one word in the original text
+type word struct {
+	start, end int
+	lower      string
+}
+
+func words(text string) []word {
+	var out []word
+	start := -1
+	for i, r := range text {
+		if isSeparator(r) {
+			if start >= 0 {
+				out = append(out, word{start, i, strings.ToLower(text[start:i])})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		out = append(out, word{start, len(text), strings.ToLower(text[start:])})
+	}
+	return out
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+func countPrefixed(ws []word, term string) int {
+	n := 0
+	for _, w := range ws {
+		if strings.HasPrefix(w.lower, term) {
+			n++
+		}
+	}
+	return n
+}
+
+func matchesAny(w word, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(w.lower, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// highlight returns the text spanning words[from:to] with matching words marked
+func highlight(text string, ws []word, terms []string, from, to int) string {
+	if from >= to {
+		return text
+	}
+
+	var b strings.Builder
+	pos := ws[from].start
+	if from == 0 {
+		pos = 0
+	}
+	for _, w := range ws[from:to] {
+		b.WriteString(text[pos:w.start])
+		if matchesAny(w, terms) {
+			b.WriteString(markStart + text[w.start:w.end] + markStop)
+		} else {
+			b.WriteString(text[w.start:w.end])
+		}
+		pos = w.end
+	}
+	if to == len(ws) {
+		b.WriteString(text[pos:])
+	}
+	return b.String()
+}
+
+// snippet picks a window of description words around the first match
+func snippet(text string, ws []word, terms []string) string {
+	if len(ws) <= snippetWords {
+		return highlight(text, ws, terms, 0, len(ws))
+	}
+
+	first := 0
+	for i, w := range ws {
+		if matchesAny(w, terms) {
+			first = i
+			break
+		}
+	}
+
+	from := max(first-snippetWords/3, 0)
+	to := min(from+snippetWords, len(ws))
+	from = max(to-snippetWords, 0)
+	return highlight(text, ws, terms, from, to)
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"go-todolist/internal/domain"
+	"strings"
 	"time"
+	"unicode"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,7 +20,7 @@ func NewTicketRepository(db *pgxpool.Pool) domain.TicketRepository {
 	return &ticketRepository{db: db}
 }
 
-func (r *ticketRepository) Create(ticket *domain.Ticket) error {
+func (r *ticketRepository) Create(ctx context.Context, ticket *domain.Ticket) error {
 	query := `
 		INSERT INTO tickets (title, description, status, priority, due_date, creator_id, assignee_id, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
@@ -29,14 +32,14 @@ func (r *ticketRepository) Create(ticket *domain.Ticket) error {
 	ticket.UpdatedAt = now
 
 	if ticket.Status == "" {
-		ticket.Status = "Backlog"
+		ticket.Status = domain.StatusBacklog
 	}
 	if ticket.Priority == "" {
-		ticket.Priority = "Medium"
+		ticket.Priority = domain.PriorityMedium
 	}
 
 	err := r.db.QueryRow(
-		context.Background(),
+		ctx,
 		query,
 		ticket.Title,
 		ticket.Description,
@@ -50,24 +53,25 @@ func (r *ticketRepository) Create(ticket *domain.Ticket) error {
 	).Scan(&ticket.ID)
 
 	if err != nil {
-		return fmt.Errorf("failed to create ticket: %w", err)
+		return translateError(err, "failed to create ticket", nil)
 	}
 
 	return nil
 }
 
-func (r *ticketRepository) FindAll() ([]domain.Ticket, error) {
+func (r *ticketRepository) FindAll(ctx context.Context) ([]domain.Ticket, error) {
 	query := `
 		SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username as creator_username, t.assignee_id, u2.username as assignee_username, t.created_at, t.updated_at
 		FROM tickets t
 		JOIN users u1 ON t.creator_id = u1.id
 		LEFT JOIN users u2 ON t.assignee_id = u2.id
-		ORDER BY t.created_at DESC
+		WHERE t.deleted_at IS NULL
+		ORDER BY t.created_at DESC, t.id DESC
 	`
 
-	rows, err := r.db.Query(context.Background(), query)
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query tickets: %w", err)
+		return nil, translateError(err, "failed to query tickets", nil)
 	}
 	defer rows.Close()
 
@@ -89,7 +93,7 @@ func (r *ticketRepository) FindAll() ([]domain.Ticket, error) {
 			&t.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+			return nil, translateError(err, "failed to scan ticket", nil)
 		}
 		tickets = append(tickets, t)
 	}
@@ -97,17 +101,108 @@ func (r *ticketRepository) FindAll() ([]domain.Ticket, error) {
 	return tickets, nil
 }
 
-func (r *ticketRepository) FindByID(id int) (*domain.Ticket, error) {
+// FindByFilter lists tickets matching every criterion set on filter. AssignedToMe and
+// DueWithinDays are relative and must be resolved by the caller before reaching here.
+func (r *ticketRepository) FindByFilter(ctx context.Context, filter domain.TicketFilter) ([]domain.Ticket, error) {
+	var tickets []domain.Ticket
+	err := r.StreamByFilter(ctx, filter, func(t *domain.Ticket) error {
+		tickets = append(tickets, *t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tickets, nil
+}
+
+// StreamByFilter calls fn for every ticket FindByFilter would return, reading the rows
+// as they arrive. An error from fn stops the iteration and is returned as is.
+func (r *ticketRepository) StreamByFilter(ctx context.Context, filter domain.TicketFilter, fn func(*domain.Ticket) error) error {
+	conditions := []string{"t.deleted_at IS NULL"}
+	var args []interface{}
+
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if len(filter.Statuses) > 0 {
+		addCondition("t.status = ANY($%d)", filter.Statuses)
+	}
+	if len(filter.Priorities) > 0 {
+		addCondition("t.priority = ANY($%d)", filter.Priorities)
+	}
+	if filter.AssigneeID != nil {
+		addCondition("t.assignee_id = $%d", *filter.AssigneeID)
+	}
+	if filter.CreatorID != nil {
+		addCondition("t.creator_id = $%d", *filter.CreatorID)
+	}
+	if filter.DueAfter != nil {
+		addCondition("t.due_date >= $%d", *filter.DueAfter)
+	}
+	if filter.DueBefore != nil {
+		addCondition("t.due_date < $%d", *filter.DueBefore)
+	}
+
+	query := `
+		SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username as creator_username, t.assignee_id, u2.username as assignee_username, t.created_at, t.updated_at
+		FROM tickets t
+		JOIN users u1 ON t.creator_id = u1.id
+		LEFT JOIN users u2 ON t.assignee_id = u2.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY t.created_at DESC, t.id DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "failed to query tickets", nil)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t domain.Ticket
+		err := rows.Scan(
+			&t.ID,
+			&t.Title,
+			&t.Description,
+			&t.Status,
+			&t.Priority,
+			&t.DueDate,
+			&t.CreatorID,
+			&t.CreatorUsername,
+			&t.AssigneeID,
+			&t.AssigneeUsername,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+		)
+		if err != nil {
+			return translateError(err, "failed to scan ticket", nil)
+		}
+		if err := fn(&t); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return translateError(err, "failed to read tickets", nil)
+	}
+
+	return nil
+}
+
+func (r *ticketRepository) FindByID(ctx context.Context, id int) (*domain.Ticket, error) {
 	query := `
 		SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username as creator_username, t.assignee_id, u2.username as assignee_username, t.created_at, t.updated_at
 		FROM tickets t
 		JOIN users u1 ON t.creator_id = u1.id
 		LEFT JOIN users u2 ON t.assignee_id = u2.id
-		WHERE t.id = $1
+		WHERE t.id = $1 AND t.deleted_at IS NULL
 	`
 
 	t := &domain.Ticket{}
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := r.db.QueryRow(ctx, query, id).Scan(
 		&t.ID,
 		&t.Title,
 		&t.Description,
@@ -123,23 +218,47 @@ func (r *ticketRepository) FindByID(id int) (*domain.Ticket, error) {
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("ticket not found: %w", err)
+		return nil, translateError(err, "failed to find ticket", domain.ErrTicketNotFound)
 	}
 
 	return t, nil
 }
 
-func (r *ticketRepository) Update(ticket *domain.Ticket) error {
+// editableBy is the condition limiting a write to the tickets the editor in
+// placeholder n may modify, see domain.Ticket.EditableBy
+func editableBy(n int) string {
+	return fmt.Sprintf("($%[1]d = 0 OR creator_id = $%[1]d OR assignee_id = $%[1]d)", n)
+}
+
+// refusal explains a write of ticket id that matched no row: the ticket is missing,
+// or not in the trash state the write expects, or the editor may not modify it
+func refusal(ctx context.Context, db querier, id int, trashed bool, missing error) error {
+	var found bool
+	err := db.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM tickets WHERE id = $1 AND (deleted_at IS NOT NULL) = $2)`, id, trashed).Scan(&found)
+	if err != nil {
+		return translateError(err, "failed to check ticket", nil)
+	}
+	if found {
+		return domain.ErrTicketForbidden
+	}
+	return missing
+}
+
+// querier is satisfied by *pgxpool.Pool and pgx.Tx
+type querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *ticketRepository) Update(ctx context.Context, ticket *domain.Ticket, editorID int) error {
 	query := `
 		UPDATE tickets
 		SET title = $1, description = $2, status = $3, priority = $4, due_date = $5, assignee_id = $6, updated_at = $7
-		WHERE id = $8
-	`
+		WHERE id = $8 AND deleted_at IS NULL AND ` + editableBy(9)
 
 	ticket.UpdatedAt = time.Now().UTC()
 
-	_, err := r.db.Exec(
-		context.Background(),
+	tag, err := r.db.Exec(
+		ctx,
 		query,
 		ticket.Title,
 		ticket.Description,
@@ -149,39 +268,165 @@ func (r *ticketRepository) Update(ticket *domain.Ticket) error {
 		ticket.AssigneeID,
 		ticket.UpdatedAt,
 		ticket.ID,
+		editorID,
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to update ticket: %w", err)
+		return translateError(err, "failed to update ticket", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return refusal(ctx, r.db, ticket.ID, false, domain.ErrTicketNotFound)
+	}
+
+	return nil
+}
+
+func (r *ticketRepository) Patch(ctx context.Context, id int, changes domain.TicketChanges, editorID int) error {
+	set, args := ticketChangeSet(changes, []any{id, editorID})
+	query := `UPDATE tickets SET ` + strings.Join(set, ", ") + ` WHERE id = $1 AND deleted_at IS NULL AND ` + editableBy(2)
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "failed to patch ticket", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return refusal(ctx, r.db, id, false, domain.ErrTicketNotFound)
 	}
 
 	return nil
 }
 
-func (r *ticketRepository) Delete(id int) error {
-	query := `DELETE FROM tickets WHERE id = $1`
+// ticketChangeSet renders the assignments of a partial update, always including
+// updated_at. The placeholders continue after the arguments already in args.
+func ticketChangeSet(changes domain.TicketChanges, args []any) ([]string, []any) {
+	var set []string
+	assign := func(column string, value any) {
+		args = append(args, value)
+		set = append(set, fmt.Sprintf("%s = $%d", column, len(args)))
+	}
+
+	if changes.Title != nil {
+		assign("title", *changes.Title)
+	}
+	if changes.Description != nil {
+		assign("description", *changes.Description)
+	}
+	if changes.Status != nil {
+		assign("status", *changes.Status)
+	}
+	if changes.Priority != nil {
+		assign("priority", *changes.Priority)
+	}
+	if changes.DueDate.Set {
+		assign("due_date", changes.DueDate.Value)
+	}
+	if changes.AssigneeID.Set {
+		assign("assignee_id", changes.AssigneeID.Value)
+	}
+	assign("updated_at", time.Now().UTC())
+
+	return set, args
+}
+
+func (r *ticketRepository) Delete(ctx context.Context, id int, editorID int) error {
+	query := `UPDATE tickets SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL AND ` + editableBy(3)
+
+	tag, err := r.db.Exec(ctx, query, time.Now().UTC(), id, editorID)
+	if err != nil {
+		return translateError(err, "failed to delete ticket", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return refusal(ctx, r.db, id, false, domain.ErrTicketNotFound)
+	}
+
+	return nil
+}
+
+func (r *ticketRepository) FindTrashed(ctx context.Context) ([]domain.Ticket, error) {
+	query := `
+		SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username as creator_username, t.assignee_id, u2.username as assignee_username, t.created_at, t.updated_at, t.deleted_at
+		FROM tickets t
+		JOIN users u1 ON t.creator_id = u1.id
+		LEFT JOIN users u2 ON t.assignee_id = u2.id
+		WHERE t.deleted_at IS NOT NULL
+		ORDER BY t.deleted_at DESC, t.id DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "failed to query trashed tickets", nil)
+	}
+	defer rows.Close()
+
+	var tickets []domain.Ticket
+	for rows.Next() {
+		var t domain.Ticket
+		err := rows.Scan(
+			&t.ID,
+			&t.Title,
+			&t.Description,
+			&t.Status,
+			&t.Priority,
+			&t.DueDate,
+			&t.CreatorID,
+			&t.CreatorUsername,
+			&t.AssigneeID,
+			&t.AssigneeUsername,
+			&t.CreatedAt,
+			&t.UpdatedAt,
+			&t.DeletedAt,
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan ticket", nil)
+		}
+		tickets = append(tickets, t)
+	}
+
+	return tickets, nil
+}
+
+func (r *ticketRepository) Restore(ctx context.Context, id int, editorID int) error {
+	query := `UPDATE tickets SET deleted_at = NULL, updated_at = $1 WHERE id = $2 AND deleted_at IS NOT NULL AND ` + editableBy(3)
 
-	_, err := r.db.Exec(context.Background(), query, id)
+	tag, err := r.db.Exec(ctx, query, time.Now().UTC(), id, editorID)
 	if err != nil {
-		return fmt.Errorf("failed to delete ticket: %w", err)
+		return translateError(err, "failed to restore ticket", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return refusal(ctx, r.db, id, true, domain.NewNotFoundError("ticket_not_in_trash", "Ticket not found in trash"))
 	}
 
 	return nil
 }
 
-func (r *ticketRepository) FindByStatus(status string) ([]domain.Ticket, error) {
+func (r *ticketRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	query := `DELETE FROM tickets WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	tag, err := r.db.Exec(ctx, query, deletedBefore)
+	if err != nil {
+		return 0, translateError(err, "failed to purge tickets", nil)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *ticketRepository) FindByStatus(ctx context.Context, status string) ([]domain.Ticket, error) {
 	query := `
 		SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username as creator_username, t.assignee_id, u2.username as assignee_username, t.created_at, t.updated_at
 		FROM tickets t
 		JOIN users u1 ON t.creator_id = u1.id
 		LEFT JOIN users u2 ON t.assignee_id = u2.id
-		WHERE t.status = $1
-		ORDER BY t.created_at DESC
+		WHERE t.status = $1 AND t.deleted_at IS NULL
+		ORDER BY t.created_at DESC, t.id DESC
 	`
 
-	rows, err := r.db.Query(context.Background(), query, status)
+	rows, err := r.db.Query(ctx, query, status)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query tickets by status: %w", err)
+		return nil, translateError(err, "failed to query tickets by status", nil)
 	}
 	defer rows.Close()
 
@@ -203,7 +448,7 @@ func (r *ticketRepository) FindByStatus(status string) ([]domain.Ticket, error)
 			&t.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan ticket: %w", err)
+			return nil, translateError(err, "failed to scan ticket", nil)
 		}
 		tickets = append(tickets, t)
 	}
@@ -211,13 +456,278 @@ func (r *ticketRepository) FindByStatus(status string) ([]domain.Ticket, error)
 	return tickets, nil
 }
 
-func (r *ticketRepository) UpdateStatus(id int, status string) error {
-	query := `UPDATE tickets SET status = $1, updated_at = $2 WHERE id = $3`
+func (r *ticketRepository) UpdateStatus(ctx context.Context, id int, status string, editorID int) error {
+	query := `UPDATE tickets SET status = $1, updated_at = $2 WHERE id = $3 AND deleted_at IS NULL AND ` + editableBy(4)
 
-	_, err := r.db.Exec(context.Background(), query, status, time.Now().UTC(), id)
+	tag, err := r.db.Exec(ctx, query, status, time.Now().UTC(), id, editorID)
 	if err != nil {
-		return fmt.Errorf("failed to update ticket status: %w", err)
+		return translateError(err, "failed to update ticket status", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return refusal(ctx, r.db, id, false, domain.ErrTicketNotFound)
 	}
 
 	return nil
 }
+
+func (r *ticketRepository) Search(ctx context.Context, params domain.TicketSearchParams) ([]domain.TicketSearchResult, error) {
+	tsQuery := buildPrefixTSQuery(params.Query)
+	if tsQuery == "" {
+		return nil, nil
+	}
+
+	query := `
+		SELECT t.id, t.title, t.description, t.status, t.priority, t.due_date, t.creator_id, u1.username as creator_username, t.assignee_id, u2.username as assignee_username, t.created_at, t.updated_at,
+			ts_rank(t.search_vector, q) AS rank,
+			ts_headline('simple', t.title, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+			ts_headline('simple', COALESCE(t.description, ''), q, 'StartSel=<mark>, StopSel=</mark>, MaxWords=30, MinWords=10, MaxFragments=2')
+		FROM tickets t
+		JOIN users u1 ON t.creator_id = u1.id
+		LEFT JOIN users u2 ON t.assignee_id = u2.id,
+		to_tsquery('simple', $1) q
+		WHERE t.deleted_at IS NULL
+			AND t.search_vector @@ q
+			AND ($2 = '' OR t.status = $2)
+			AND ($3::int IS NULL OR t.assignee_id = $3)
+		ORDER BY rank DESC, t.updated_at DESC, t.id DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, tsQuery, params.Status, params.AssigneeID, params.Limit)
+	if err != nil {
+		return nil, translateError(err, "failed to search tickets", nil)
+	}
+	defer rows.Close()
+
+	var results []domain.TicketSearchResult
+	for rows.Next() {
+		var res domain.TicketSearchResult
+		err := rows.Scan(
+			&res.ID,
+			&res.Title,
+			&res.Description,
+			&res.Status,
+			&res.Priority,
+			&res.DueDate,
+			&res.CreatorID,
+			&res.CreatorUsername,
+			&res.AssigneeID,
+			&res.AssigneeUsername,
+			&res.CreatedAt,
+			&res.UpdatedAt,
+			&res.Rank,
+			&res.TitleHighlight,
+			&res.Snippet,
+		)
+		if err != nil {
+			return nil, translateError(err, "failed to scan search result", nil)
+		}
+		results = append(results, res)
+	}
+
+	return results, nil
+}
+
+// buildPrefixTSQuery turns free text into a tsquery where every word must match
+// and the words are matched as prefixes, so "fix log" finds "fixed login"
+func buildPrefixTSQuery(input string) string {
+	words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, w+":*")
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+// PatchMany applies the same changes to every ticket in ids and stores logs in one
+// transaction. Nothing is written when one of the tickets does not exist or may not
+// be modified by editorID, the update matching fewer rows than ids rolls it back.
+func (r *ticketRepository) PatchMany(ctx context.Context, ids []int, changes domain.TicketChanges, logs []*domain.ActivityLog, editorID int) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return translateError(err, "failed to begin transaction", nil)
+	}
+	defer tx.Rollback(ctx)
+
+	set, args := ticketChangeSet(changes, []any{ids, editorID})
+	query := `UPDATE tickets SET ` + strings.Join(set, ", ") + ` WHERE id = ANY($1) AND deleted_at IS NULL AND ` + editableBy(2) + ` RETURNING id`
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return translateError(err, "failed to update tickets", nil)
+	}
+	updated, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return translateError(err, "failed to update tickets", nil)
+	}
+	if missing, ok := firstMissing(ids, updated); ok {
+		return refusal(ctx, tx, missing, false, domain.NewNotFoundError("ticket_not_found", fmt.Sprintf("Ticket %d not found", missing)))
+	}
+
+	logQuery := `
+		INSERT INTO activity_logs (ticket_id, user_id, action, created_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+	now := time.Now().UTC()
+
+	for _, log := range logs {
+		err := tx.QueryRow(ctx, logQuery, log.TicketID, log.UserID, log.Action, now).Scan(&log.ID, &log.CreatedAt)
+		if err != nil {
+			return translateError(err, "failed to create activity log", nil)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return translateError(err, "failed to commit bulk update", nil)
+	}
+
+	return nil
+}
+
+// firstMissing returns the first of ids that is not in found
+func firstMissing(ids, found []int) (int, bool) {
+	seen := make(map[int]bool, len(found))
+	for _, id := range found {
+		seen[id] = true
+	}
+	for _, id := range ids {
+		if !seen[id] {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// Import stores tickets and their history with the timestamps they carry in a single
+// transaction. Rows are loaded with COPY, which cannot return generated ids, so the
+// ids are reserved from the sequences first.
+func (r *ticketRepository) Import(ctx context.Context, tickets []domain.TicketImport) error {
+	if len(tickets) == 0 {
+		return nil
+	}
+
+	var logs []*domain.ActivityLog
+	for _, item := range tickets {
+		logs = append(logs, item.History...)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return translateError(err, "failed to begin transaction", nil)
+	}
+	defer tx.Rollback(ctx)
+
+	ticketIDs, err := reserveIDs(ctx, tx, "tickets", len(tickets))
+	if err != nil {
+		return err
+	}
+	logIDs, err := reserveIDs(ctx, tx, "activity_logs", len(logs))
+	if err != nil {
+		return err
+	}
+
+	n := 0
+	for i, item := range tickets {
+		ticket := item.Ticket
+		ticket.ID = ticketIDs[i]
+		if ticket.Status == "" {
+			ticket.Status = domain.StatusBacklog
+		}
+		if ticket.Priority == "" {
+			ticket.Priority = domain.PriorityMedium
+		}
+		for _, log := range item.History {
+			log.ID = logIDs[n]
+			log.TicketID = &ticket.ID
+			n++
+		}
+	}
+
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"tickets"},
+		[]string{"id", "title", "description", "status", "priority", "due_date", "creator_id", "assignee_id", "created_at", "updated_at", "deleted_at"},
+		pgx.CopyFromSlice(len(tickets), func(i int) ([]any, error) {
+			t := tickets[i].Ticket
+			return []any{t.ID, t.Title, t.Description, t.Status, t.Priority, t.DueDate, t.CreatorID, t.AssigneeID, t.CreatedAt, t.UpdatedAt, t.DeletedAt}, nil
+		}),
+	)
+	if err != nil {
+		return translateError(err, "failed to copy tickets", nil)
+	}
+
+	_, err = tx.CopyFrom(
+		ctx,
+		pgx.Identifier{"activity_logs"},
+		[]string{"id", "ticket_id", "user_id", "action", "created_at"},
+		pgx.CopyFromSlice(len(logs), func(i int) ([]any, error) {
+			l := logs[i]
+			return []any{l.ID, l.TicketID, l.UserID, l.Action, l.CreatedAt}, nil
+		}),
+	)
+	if err != nil {
+		return translateError(err, "failed to copy activity logs", nil)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return translateError(err, "failed to commit import", nil)
+	}
+
+	return nil
+}
+
+// reserveIDs takes n values from the id sequence of table
+func reserveIDs(ctx context.Context, tx pgx.Tx, table string, n int) ([]int, error) {
+	if n == 0 {
+		return nil, nil
+	}
+
+	rows, err := tx.Query(ctx, `SELECT nextval(pg_get_serial_sequence($1, 'id'))::int FROM generate_series(1, $2)`, table, n)
+	if err != nil {
+		return nil, translateError(err, "failed to reserve "+table+" ids", nil)
+	}
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int])
+	if err != nil {
+		return nil, translateError(err, "failed to reserve "+table+" ids", nil)
+	}
+	return ids, nil
+}
+
+// CountByStatus returns the number of live tickets per status
+func (r *ticketRepository) CountByStatus(ctx context.Context) (map[string]int, error) {
+	query := `SELECT status, COUNT(*) FROM tickets WHERE deleted_at IS NULL GROUP BY status`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, translateError(err, "failed to count tickets by status", nil)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var status string
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, translateError(err, "failed to scan ticket count", nil)
+		}
+		counts[status] = count
+	}
+
+	return counts, nil
+}
+
+func (r *ticketRepository) CountTrashed(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM tickets WHERE deleted_at IS NOT NULL`).Scan(&count)
+	if err != nil {
+		return 0, translateError(err, "failed to count trashed tickets", nil)
+	}
+
+	return count, nil
+}
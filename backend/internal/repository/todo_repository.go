@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"fmt"
 	"go-todolist/internal/domain"
 	"time"
 
@@ -17,7 +16,7 @@ func NewTodoRepository(db *pgxpool.Pool) domain.TodoRepository {
 	return &todoRepository{db: db}
 }
 
-func (r *todoRepository) Create(todo *domain.Todo) error {
+func (r *todoRepository) Create(ctx context.Context, todo *domain.Todo) error {
 	query := `
 		INSERT INTO todos (user_id, title, description, status, created_at, updated_at)
 		VALUES ($1, $2, $3, $4, $5, $6)
@@ -29,7 +28,7 @@ func (r *todoRepository) Create(todo *domain.Todo) error {
 	todo.UpdatedAt = now
 
 	err := r.db.QueryRow(
-		context.Background(),
+		ctx,
 		query,
 		todo.UserID,
 		todo.Title,
@@ -37,24 +36,25 @@ func (r *todoRepository) Create(todo *domain.Todo) error {
 		todo.Status,
 		todo.CreatedAt,
 		todo.UpdatedAt,
-	).Scan(&todo.UserID)
+	).Scan(&todo.ID)
 
 	if err != nil {
-		return fmt.Errorf("failed to create todo: %w", err)
+		return translateError(err, "failed to create todo", nil)
 	}
 
 	return nil
 }
 
-func (r *todoRepository) FindAll() ([]domain.Todo, error) {
+func (r *todoRepository) FindAll(ctx context.Context) ([]domain.Todo, error) {
 	query := `
 		SELECT id, user_id, title, description, status, created_at, updated_at
 		FROM todos
+		ORDER BY id ASC
 	`
 
-	rows, err := r.db.Query(context.Background(), query)
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query todos: %w", err)
+		return nil, translateError(err, "failed to query todos", nil)
 	}
 	defer rows.Close()
 
@@ -71,7 +71,7 @@ func (r *todoRepository) FindAll() ([]domain.Todo, error) {
 			&todo.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan todo: %w", err)
+			return nil, translateError(err, "failed to scan todo", nil)
 		}
 		todos = append(todos, todo)
 	}
@@ -79,7 +79,7 @@ func (r *todoRepository) FindAll() ([]domain.Todo, error) {
 	return todos, nil
 }
 
-func (r *todoRepository) FindById(id int) (*domain.Todo, error) {
+func (r *todoRepository) FindById(ctx context.Context, id int) (*domain.Todo, error) {
 	query := `
 		SELECT id, user_id, title, description, status, created_at, updated_at
 		FROM todos
@@ -87,7 +87,7 @@ func (r *todoRepository) FindById(id int) (*domain.Todo, error) {
 	`
 
 	todo := &domain.Todo{}
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := r.db.QueryRow(ctx, query, id).Scan(
 		&todo.ID,
 		&todo.UserID,
 		&todo.Title,
@@ -98,13 +98,13 @@ func (r *todoRepository) FindById(id int) (*domain.Todo, error) {
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("todo not found: %w", err)
+		return nil, translateError(err, "failed to find todo", domain.ErrTodoNotFound)
 	}
 
 	return todo, nil
 }
 
-func (r *todoRepository) Update(todo *domain.Todo) error {
+func (r *todoRepository) Update(ctx context.Context, todo *domain.Todo) error {
 	query := `
 		UPDATE todos
 		SET user_id = $1, title = $2, description = $3, status = $4, updated_at = $5
@@ -114,8 +114,8 @@ func (r *todoRepository) Update(todo *domain.Todo) error {
 	now := time.Now()
 	todo.UpdatedAt = now
 
-	err := r.db.QueryRow(
-		context.Background(),
+	tag, err := r.db.Exec(
+		ctx,
 		query,
 		todo.UserID,
 		todo.Title,
@@ -123,25 +123,33 @@ func (r *todoRepository) Update(todo *domain.Todo) error {
 		todo.Status,
 		todo.UpdatedAt,
 		todo.ID,
-	).Scan(&todo.ID)
+	)
 
 	if err != nil {
-		return fmt.Errorf("failed to update todo: %w", err)
+		return translateError(err, "failed to update todo", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTodoNotFound
 	}
 
 	return nil
 }
 
-func (r *todoRepository) Delete(id int) error {
+func (r *todoRepository) Delete(ctx context.Context, id int) error {
 	query := `
 		DELETE FROM todos
 		WHERE id = $1
 	`
 
-	err := r.db.QueryRow(context.Background(), query, id).Scan(&id)
+	tag, err := r.db.Exec(ctx, query, id)
 
 	if err != nil {
-		return fmt.Errorf("failed to delete todo: %w", err)
+		return translateError(err, "failed to delete todo", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTodoNotFound
 	}
 
 	return nil
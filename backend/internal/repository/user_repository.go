@@ -2,7 +2,7 @@ package repository
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"go-todolist/internal/domain"
 	"time"
 
@@ -17,89 +17,105 @@ func NewUserRepository(db *pgxpool.Pool) domain.UserRepository {
 	return &userRepository{db: db}
 }
 
-func (r *userRepository) Create(user *domain.User) error {
+func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
-		INSERT INTO users (username, password, created_at, updated_at)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (username, password, role, disabled_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		RETURNING id
 	`
 
+	if user.Role == "" {
+		user.Role = domain.RoleUser
+	}
+
 	now := time.Now()
 	user.CreatedAt = now
 	user.UpdatedAt = now
 
 	err := r.db.QueryRow(
-		context.Background(),
+		ctx,
 		query,
 		user.Username,
 		user.Password,
+		user.Role,
+		user.DisabledAt,
 		user.CreatedAt,
 		user.UpdatedAt,
 	).Scan(&user.ID)
 
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		err = translateError(err, "failed to create user", nil)
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.NewConflictError("username_taken", "Username already exists")
+		}
+		return err
 	}
 
 	return nil
 }
 
-func (r *userRepository) FindByUsername(username string) (*domain.User, error) {
+func (r *userRepository) FindByUsername(ctx context.Context, username string) (*domain.User, error) {
 	query := `
-		SELECT id, username, password, created_at, updated_at
+		SELECT id, username, password, COALESCE(profile_photo, ''), role, disabled_at, created_at, updated_at
 		FROM users 
 		WHERE username = $1
 	`
 
 	user := &domain.User{}
-	err := r.db.QueryRow(context.Background(), query, username).Scan(
+	err := r.db.QueryRow(ctx, query, username).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Password,
+		&user.ProfilePhoto,
+		&user.Role,
+		&user.DisabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, translateError(err, "failed to find user", domain.ErrUserNotFound)
 	}
 
 	return user, nil
 }
 
-func (r *userRepository) FindByID(id int) (*domain.User, error) {
+func (r *userRepository) FindByID(ctx context.Context, id int) (*domain.User, error) {
 	query := `
-		SELECT id, username, password, created_at, updated_at
+		SELECT id, username, password, COALESCE(profile_photo, ''), role, disabled_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 
 	user := &domain.User{}
-	err := r.db.QueryRow(context.Background(), query, id).Scan(
+	err := r.db.QueryRow(ctx, query, id).Scan(
 		&user.ID,
 		&user.Username,
 		&user.Password,
+		&user.ProfilePhoto,
+		&user.Role,
+		&user.DisabledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
 
 	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, translateError(err, "failed to find user", domain.ErrUserNotFound)
 	}
 
 	return user, nil
 }
 
-func (r *userRepository) FindAll() ([]domain.User, error) {
+func (r *userRepository) FindAll(ctx context.Context) ([]domain.User, error) {
 	query := `
-		SELECT id, username, created_at, updated_at
+		SELECT id, username, COALESCE(profile_photo, ''), role, disabled_at, created_at, updated_at
 		FROM users
 		ORDER BY username ASC
 	`
 
-	rows, err := r.db.Query(context.Background(), query)
+	rows, err := r.db.Query(ctx, query)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query users: %w", err)
+		return nil, translateError(err, "failed to query users", nil)
 	}
 	defer rows.Close()
 
@@ -109,11 +125,14 @@ func (r *userRepository) FindAll() ([]domain.User, error) {
 		err := rows.Scan(
 			&u.ID,
 			&u.Username,
+			&u.ProfilePhoto,
+			&u.Role,
+			&u.DisabledAt,
 			&u.CreatedAt,
 			&u.UpdatedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan user: %w", err)
+			return nil, translateError(err, "failed to scan user", nil)
 		}
 		users = append(users, u)
 	}
@@ -121,7 +140,7 @@ func (r *userRepository) FindAll() ([]domain.User, error) {
 	return users, nil
 }
 
-func (r *userRepository) Update(user *domain.User) error {
+func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 	query := `
 		UPDATE users
 		SET username = $1, password = $2, profile_photo = $3, updated_at = $4
@@ -130,8 +149,8 @@ func (r *userRepository) Update(user *domain.User) error {
 
 	user.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(
-		context.Background(),
+	tag, err := r.db.Exec(
+		ctx,
 		query,
 		user.Username,
 		user.Password,
@@ -141,7 +160,41 @@ func (r *userRepository) Update(user *domain.User) error {
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		err = translateError(err, "failed to update user", nil)
+		if errors.Is(err, domain.ErrConflict) {
+			return domain.NewConflictError("username_taken", "Username already exists")
+		}
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetRole(ctx context.Context, id int, role string) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET role = $1, updated_at = $2 WHERE id = $3`, role, time.Now(), id)
+	if err != nil {
+		return translateError(err, "failed to set user role", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetDisabled(ctx context.Context, id int, disabledAt *time.Time) error {
+	tag, err := r.db.Exec(ctx, `UPDATE users SET disabled_at = $1, updated_at = $2 WHERE id = $3`, disabledAt, time.Now(), id)
+	if err != nil {
+		return translateError(err, "failed to set user disabled", nil)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
 	}
 
 	return nil
@@ -0,0 +1,284 @@
+// Package seed fills a database with realistic demo data for trying out the board and
+// for load testing. The same Options always produce the same users, tickets and
+// activity history, only the ids and password hashes depend on the database.
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/utils"
+	"math/rand/v2"
+	"time"
+)
+
+// Options controls the generated volume
+type Options struct {
+	// Seed selects the dataset, the same seed and Now give the same data
+	Seed uint64
+	// Users is the number of accounts, the first one is an admin called "admin"
+	Users int
+	// Tickets is the number of tickets, they are written in batches of BatchSize
+	Tickets   int
+	BatchSize int
+	// Days is how far back the history goes
+	Days int
+	// Now is the reference time for history and due dates
+	Now time.Time
+	// Password is shared by every seeded account
+	Password string
+	// Progress is called after each batch with the number of tickets written so far
+	Progress func(tickets int)
+}
+
+// DefaultOptions returns a small dataset anchored at midnight UTC today
+func DefaultOptions() Options {
+	now := time.Now().UTC()
+	return Options{
+		Seed:      1,
+		Users:     25,
+		Tickets:   1000,
+		BatchSize: 1000,
+		Days:      180,
+		Now:       time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC),
+		Password:  "password",
+	}
+}
+
+// Result counts what was written
+type Result struct {
+	Users        int `json:"users"`
+	CreatedUsers int `json:"created_users"`
+	Tickets      int `json:"tickets"`
+	ActivityLogs int `json:"activity_logs"`
+}
+
+// Run creates the users, reusing accounts that already exist under the same name, then
+// writes the tickets with their history through domain.TicketRepository.Import
+func Run(ctx context.Context, users domain.UserRepository, tickets domain.TicketRepository, opts Options) (*Result, error) {
+	if opts.Users < 1 {
+		return nil, errors.New("seed: at least one user is required")
+	}
+	if opts.Tickets < 0 || opts.Days < 1 || opts.BatchSize < 1 {
+		return nil, errors.New("seed: tickets, days and batch size must be positive")
+	}
+	if len(opts.Password) < 6 || len(opts.Password) > 72 {
+		return nil, errors.New("seed: password must be between 6 and 72 characters")
+	}
+
+	result := &Result{}
+	accounts, err := createUsers(ctx, users, opts, result)
+	if err != nil {
+		return nil, err
+	}
+
+	g := newGenerator(opts, accounts)
+	batch := make([]domain.TicketImport, 0, opts.BatchSize)
+	for result.Tickets < opts.Tickets {
+		batch = batch[:0]
+		for len(batch) < opts.BatchSize && result.Tickets+len(batch) < opts.Tickets {
+			batch = append(batch, g.ticket())
+		}
+
+		if err := tickets.Import(ctx, batch); err != nil {
+			return nil, fmt.Errorf("seed: failed to import tickets: %w", err)
+		}
+
+		result.Tickets += len(batch)
+		for _, item := range batch {
+			result.ActivityLogs += len(item.History)
+		}
+		if opts.Progress != nil {
+			opts.Progress(result.Tickets)
+		}
+	}
+
+	return result, nil
+}
+
+// createUsers hashes the password once, bcrypt would otherwise dominate the run time
+func createUsers(ctx context.Context, repo domain.UserRepository, opts Options, result *Result) ([]*domain.User, error) {
+	hash, err := utils.HashPassword(opts.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]*domain.User, opts.Users)
+	for i := range accounts {
+		name := username(i)
+
+		existing, err := repo.FindByUsername(ctx, name)
+		if err == nil {
+			accounts[i] = existing
+			continue
+		}
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			return nil, err
+		}
+
+		user := &domain.User{Username: name, Password: hash, Role: domain.RoleUser}
+		if i == 0 {
+			user.Role = domain.RoleAdmin
+		}
+		if err := repo.Create(ctx, user); err != nil {
+			return nil, fmt.Errorf("seed: failed to create user %q: %w", name, err)
+		}
+		accounts[i] = user
+		result.CreatedUsers++
+	}
+	result.Users = len(accounts)
+
+	return accounts, nil
+}
+
+// username is "admin" for the first account, then first names with a number once
+// the list runs out
+func username(i int) string {
+	if i == 0 {
+		return "admin"
+	}
+	i--
+	name := firstNames[i%len(firstNames)]
+	if round := i / len(firstNames); round > 0 {
+		name = fmt.Sprintf("%s%d", name, round+1)
+	}
+	return name
+}
+
+// generator draws every ticket from a single PCG stream, whose output is stable
+// across Go releases
+type generator struct {
+	rng   *rand.Rand
+	opts  Options
+	users []*domain.User
+	span  time.Duration
+}
+
+func newGenerator(opts Options, users []*domain.User) *generator {
+	return &generator{
+		rng:   rand.New(rand.NewPCG(opts.Seed, 0x5eed)),
+		opts:  opts,
+		users: users,
+		span:  time.Duration(opts.Days) * 24 * time.Hour,
+	}
+}
+
+// ticket generates the next ticket. Older tickets are more likely to be done, and the
+// history walks the board from Backlog to the current status.
+func (g *generator) ticket() domain.TicketImport {
+	r := g.rng
+	now := g.opts.Now
+
+	age := time.Duration(r.Int64N(int64(g.span)))
+	created := now.Add(-age).Truncate(time.Second)
+	f := float64(age) / float64(g.span)
+
+	statusIdx := pick(r, []float64{25 - 15*f, 20 - 10*f, 15, 10 + 60*f})
+	status := domain.TicketStatuses[statusIdx]
+
+	creator := g.user()
+	ticket := &domain.Ticket{
+		Title:       g.title(),
+		Description: g.description(),
+		Status:      status,
+		Priority:    domain.TicketPriorities[pick(r, []float64{30, 50, 20})],
+		CreatorID:   creator.ID,
+		CreatedAt:   created,
+	}
+
+	// most work in flight has an owner, the backlog often does not
+	assigned := r.Float64() < 0.9
+	if status == domain.StatusBacklog {
+		assigned = r.Float64() < 0.4
+	}
+	actor := creator
+	if assigned {
+		actor = g.user()
+		ticket.AssigneeID = &actor.ID
+	}
+
+	hasDue := r.Float64() < 0.7
+	if status == domain.StatusBacklog {
+		hasDue = r.Float64() < 0.25
+	}
+	if hasDue {
+		day := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+		due := day.AddDate(0, 0, 2+r.IntN(44))
+		ticket.DueDate = &due
+	}
+
+	// every step lands somewhere between the previous one and now
+	at := created
+	step := func(remaining int) time.Time {
+		gap := float64(now.Sub(at)) / float64(remaining+1)
+		at = at.Add(time.Duration(gap * (0.2 + 0.8*r.Float64()))).Truncate(time.Second)
+		return at
+	}
+
+	history := []*domain.ActivityLog{{UserID: creator.ID, Action: "created ticket: " + ticket.Title, CreatedAt: created}}
+	if r.Float64() < 0.3 {
+		history = append(history, &domain.ActivityLog{UserID: creator.ID, Action: "updated ticket: " + ticket.Title, CreatedAt: step(statusIdx + 1)})
+	}
+	for i := 1; i <= statusIdx; i++ {
+		history = append(history, &domain.ActivityLog{
+			UserID:    actor.ID,
+			Action:    "changed status to " + domain.TicketStatuses[i],
+			CreatedAt: step(statusIdx - i),
+		})
+	}
+
+	// a few recent deletions so the trash is not empty
+	if r.Float64() < 0.02 {
+		deleted := step(0)
+		ticket.DeletedAt = &deleted
+		history = append(history, &domain.ActivityLog{UserID: creator.ID, Action: "moved ticket to trash: " + ticket.Title, CreatedAt: deleted})
+	}
+
+	ticket.UpdatedAt = history[len(history)-1].CreatedAt
+	return domain.TicketImport{Ticket: ticket, History: history}
+}
+
+func (g *generator) user() *domain.User {
+	return g.users[g.rng.IntN(len(g.users))]
+}
+
+func (g *generator) title() string {
+	r := g.rng
+	title := verbs[r.IntN(len(verbs))] + " " + subjects[r.IntN(len(subjects))]
+	if r.Float64() < 0.5 {
+		title += " " + areas[r.IntN(len(areas))]
+	}
+	return title
+}
+
+func (g *generator) description() string {
+	r := g.rng
+	switch r.IntN(4) {
+	case 0:
+		return ""
+	case 1:
+		return sentences[r.IntN(len(sentences))]
+	case 2:
+		return sentences[r.IntN(len(sentences))] + " " + sentences[r.IntN(len(sentences))]
+	default:
+		return sentences[r.IntN(len(sentences))] + "\n\nSteps to reproduce:\n1. " +
+			steps[r.IntN(len(steps))] + "\n2. " + steps[r.IntN(len(steps))] + "\n3. See the error"
+	}
+}
+
+// pick returns an index with probability proportional to its weight
+func pick(r *rand.Rand, weights []float64) int {
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	x := r.Float64() * total
+	for i, w := range weights {
+		if x < w {
+			return i
+		}
+		x -= w
+	}
+	return len(weights) - 1
+}
@@ -0,0 +1,189 @@
+package seed_test
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/repository/memory"
+	"go-todolist/internal/seed"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type repos struct {
+	users   domain.UserRepository
+	tickets domain.TicketRepository
+	logs    domain.ActivityLogRepository
+}
+
+func newRepos() repos {
+	store := memory.NewStore()
+	return repos{
+		users:   memory.NewUserRepository(store),
+		tickets: memory.NewTicketRepository(store),
+		logs:    memory.NewActivityLogRepository(store),
+	}
+}
+
+func testOptions() seed.Options {
+	opts := seed.DefaultOptions()
+	opts.Users = 8
+	opts.Tickets = 600
+	opts.BatchSize = 64
+	opts.Now = time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	return opts
+}
+
+// snapshot leaves out ids and anything else the store decides
+type snapshot struct {
+	Title, Description, Status, Priority string
+	Creator, Assignee                    string
+	Due, Deleted                         *time.Time
+	Created, Updated                     time.Time
+}
+
+func load(t *testing.T, r repos) []snapshot {
+	t.Helper()
+	live, err := r.tickets.FindAll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	trashed, err := r.tickets.FindTrashed(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out []snapshot
+	for _, tk := range append(live, trashed...) {
+		s := snapshot{
+			Title: tk.Title, Description: tk.Description, Status: tk.Status, Priority: tk.Priority,
+			Creator: tk.CreatorUsername, Due: tk.DueDate, Deleted: tk.DeletedAt,
+			Created: tk.CreatedAt, Updated: tk.UpdatedAt,
+		}
+		if tk.AssigneeUsername != nil {
+			s.Assignee = *tk.AssigneeUsername
+		}
+		out = append(out, s)
+	}
+	return out
+}
+
+func TestRunIsDeterministic(t *testing.T) {
+	ctx := context.Background()
+	opts := testOptions()
+
+	var batches []int
+	opts.Progress = func(n int) { batches = append(batches, n) }
+
+	first := newRepos()
+	result, err := seed.Run(ctx, first.users, first.tickets, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Users != 8 || result.CreatedUsers != 8 || result.Tickets != 600 || result.ActivityLogs < 600 {
+		t.Fatalf("got result %+v", result)
+	}
+	if len(batches) != 10 || batches[len(batches)-1] != 600 {
+		t.Fatalf("progress reported %v", batches)
+	}
+
+	second := newRepos()
+	if _, err := seed.Run(ctx, second.users, second.tickets, opts); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(load(t, first), load(t, second)) {
+		t.Fatal("same seed produced different data")
+	}
+
+	opts.Seed = 2
+	other := newRepos()
+	if _, err := seed.Run(ctx, other.users, other.tickets, opts); err != nil {
+		t.Fatal(err)
+	}
+	if reflect.DeepEqual(load(t, first), load(t, other)) {
+		t.Fatal("different seeds produced the same data")
+	}
+}
+
+func TestRunProducesRealisticData(t *testing.T) {
+	ctx := context.Background()
+	opts := testOptions()
+	r := newRepos()
+
+	if _, err := seed.Run(ctx, r.users, r.tickets, opts); err != nil {
+		t.Fatal(err)
+	}
+
+	admin, err := r.users.FindByUsername(ctx, "admin")
+	if err != nil || admin.Role != domain.RoleAdmin {
+		t.Fatalf("admin account: %+v, %v", admin, err)
+	}
+
+	statuses := map[string]int{}
+	priorities := map[string]int{}
+	var assigned, due, trashed int
+	earliest := opts.Now.AddDate(0, 0, -opts.Days)
+	for _, s := range load(t, r) {
+		statuses[s.Status]++
+		priorities[s.Priority]++
+		if s.Assignee != "" {
+			assigned++
+		}
+		if s.Due != nil {
+			due++
+		}
+		if s.Deleted != nil {
+			trashed++
+		}
+		if s.Created.Before(earliest) || s.Updated.Before(s.Created) || s.Updated.After(opts.Now) {
+			t.Fatalf("timestamps out of range: %+v", s)
+		}
+	}
+	for _, status := range domain.TicketStatuses {
+		if statuses[status] == 0 {
+			t.Errorf("no %s tickets in %v", status, statuses)
+		}
+	}
+	for _, priority := range domain.TicketPriorities {
+		if priorities[priority] == 0 {
+			t.Errorf("no %s tickets in %v", priority, priorities)
+		}
+	}
+	if assigned == 0 || assigned == 600 || due == 0 || due == 600 || trashed == 0 {
+		t.Errorf("assigned %d, with due date %d, trashed %d of 600", assigned, due, trashed)
+	}
+
+	logs, err := r.logs.FindAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < len(logs); i++ {
+		if logs[i].CreatedAt.After(logs[i-1].CreatedAt) || logs[i].CreatedAt.After(opts.Now) {
+			t.Fatalf("activity out of order at %d: %+v", i, logs[i])
+		}
+	}
+}
+
+func TestRunReusesExistingUsers(t *testing.T) {
+	ctx := context.Background()
+	opts := testOptions()
+	opts.Tickets = 10
+	r := newRepos()
+
+	if _, err := seed.Run(ctx, r.users, r.tickets, opts); err != nil {
+		t.Fatal(err)
+	}
+	result, err := seed.Run(ctx, r.users, r.tickets, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Users != 8 || result.CreatedUsers != 0 {
+		t.Fatalf("got result %+v", result)
+	}
+
+	all, _ := r.tickets.FindAll(ctx)
+	trashed, _ := r.tickets.FindTrashed(ctx)
+	if len(all)+len(trashed) != 20 {
+		t.Fatalf("got %d tickets, want 20", len(all)+len(trashed))
+	}
+}
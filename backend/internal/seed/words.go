@@ -0,0 +1,51 @@
+package seed
+
+var firstNames = []string{
+	"alice", "bob", "carol", "dave", "erin", "frank", "grace", "heidi", "ivan", "judy",
+	"karl", "laura", "mallory", "nina", "oscar", "peggy", "quinn", "rupert", "sybil", "trent",
+	"ursula", "victor", "wendy", "xavier", "yvonne", "zack",
+}
+
+var verbs = []string{
+	"Fix", "Investigate", "Add", "Improve", "Refactor", "Document", "Remove", "Update",
+	"Speed up", "Test", "Redesign", "Migrate",
+}
+
+// a few subjects carry quotes and commas, they are what exports have to escape
+var subjects = []string{
+	"login redirect", "CSV export", "session timeout", "dark mode toggle", "rate limiter",
+	"search ranking", "email notifications", "password reset flow", "audit log",
+	"board drag and drop", "due date picker", "profile photo upload", "ticket filters",
+	`the "Save as..." dialog`, "errors, warnings and notices", "bulk status change",
+	"API pagination", "onboarding checklist", "billing page", "release notes",
+	"keyboard shortcuts", "mobile layout", "trash retention", "health checks",
+}
+
+var areas = []string{
+	"on mobile", "for admins", "in the API", "after deploy", "on Safari", "in the command palette",
+	"for new users", "behind the proxy", "in staging",
+}
+
+var sentences = []string{
+	"Reported by two customers this week.",
+	"Happens intermittently, roughly one request in fifty.",
+	"We agreed on the approach in Tuesday's planning.",
+	"Blocked until the design review is done.",
+	"The current behaviour is confusing, see the support thread.",
+	"Should be a small change but needs a migration.",
+	"Product wants this before the end of the quarter.",
+	"Logs show a timeout after 30s, nothing else stands out.",
+	`Users see "Something went wrong" with no further detail.`,
+	"Low risk, covered by the existing integration tests.",
+}
+
+var steps = []string{
+	"Log in as a regular user",
+	"Open the board",
+	"Drag a ticket to Done",
+	"Open the profile page",
+	"Filter by High priority",
+	"Refresh the page",
+	"Switch to the mobile layout",
+	"Export the current view",
+}
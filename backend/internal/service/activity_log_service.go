@@ -1,6 +1,13 @@
 package service
 
-import "go-todolist/internal/domain"
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+)
+
+// maxActivityPage caps FindAfter, matching the 100 entries FindAll returns
+const maxActivityPage = 100
 
 type ActivityLogService struct {
 	activityLogRepo domain.ActivityLogRepository
@@ -12,15 +19,41 @@ func NewActivityLogService(activityLogRepo domain.ActivityLogRepository) *Activi
 	}
 }
 
-func (s *ActivityLogService) FindAll() ([]domain.ActivityLog, error) {
-	return s.activityLogRepo.FindAll()
+func (s *ActivityLogService) FindAll(ctx context.Context) (_ []domain.ActivityLog, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityLogService.FindAll")
+	defer tracing.End(span, &err)
+
+	return s.activityLogRepo.FindAll(ctx)
+}
+
+// FindAfter returns up to limit entries with an id above afterID, oldest first. A
+// limit outside 1..100 is clamped like the search limit.
+func (s *ActivityLogService) FindAfter(ctx context.Context, afterID, limit int) (_ []domain.ActivityLog, err error) {
+	ctx, span := tracing.Start(ctx, "ActivityLogService.FindAfter")
+	defer tracing.End(span, &err)
+
+	if limit <= 0 || limit > maxActivityPage {
+		limit = maxActivityPage
+	}
+	return s.activityLogRepo.FindAfter(ctx, afterID, limit)
+}
+
+// ExportLogs calls fn for every activity log matching filter without collecting them
+func (s *ActivityLogService) ExportLogs(ctx context.Context, filter domain.ActivityLogFilter, fn func(*domain.ActivityLog) error) (err error) {
+	ctx, span := tracing.Start(ctx, "ActivityLogService.ExportLogs")
+	defer tracing.End(span, &err)
+
+	return s.activityLogRepo.Stream(ctx, filter, fn)
 }
 
-func (s *ActivityLogService) CreateLog(ticketID *int, userID int, action string) error {
+func (s *ActivityLogService) CreateLog(ctx context.Context, ticketID *int, userID int, action string) (err error) {
+	ctx, span := tracing.Start(ctx, "ActivityLogService.CreateLog")
+	defer tracing.End(span, &err)
+
 	log := &domain.ActivityLog{
 		TicketID: ticketID,
 		UserID:   userID,
 		Action:   action,
 	}
-	return s.activityLogRepo.Create(log)
+	return s.activityLogRepo.Create(ctx, log)
 }
@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+	"strings"
+	"time"
+)
+
+var (
+	errInvalidRole      = domain.NewValidationError("invalid_role", "Role must be one of: "+strings.Join(domain.UserRoles, ", "))
+	errPasswordLength   = domain.NewValidationError("invalid_password", "Password must be between 6 and 72 characters")
+	errReassignSameUser = domain.NewValidationError("same_user", "Tickets are already assigned to that user")
+	errAssigneeDisabled = domain.NewValidationError("user_disabled", "Cannot assign tickets to a disabled account")
+)
+
+// AdminService backs the operator commands of the server binary, it is not exposed
+// over HTTP
+type AdminService struct {
+	userRepo    domain.UserRepository
+	ticketRepo  domain.TicketRepository
+	authService *AuthService
+}
+
+// AdminStats summarizes the stored data
+type AdminStats struct {
+	Users           int            `json:"users"`
+	Admins          int            `json:"admins"`
+	DisabledUsers   int            `json:"disabled_users"`
+	Tickets         int            `json:"tickets"`
+	TicketsByStatus map[string]int `json:"tickets_by_status"`
+	TrashedTickets  int            `json:"trashed_tickets"`
+}
+
+func NewAdminService(userRepo domain.UserRepository, ticketRepo domain.TicketRepository, authService *AuthService) *AdminService {
+	return &AdminService{
+		userRepo:    userRepo,
+		ticketRepo:  ticketRepo,
+		authService: authService,
+	}
+}
+
+// CreateUser registers an account with the same rules as the signup endpoint
+func (s *AdminService) CreateUser(ctx context.Context, username, password, role string) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.CreateUser")
+	defer tracing.End(span, &err)
+
+	if !domain.IsValidUserRole(role) {
+		return nil, errInvalidRole
+	}
+	if len(password) < 6 || len(password) > 72 {
+		return nil, errPasswordLength
+	}
+
+	return s.authService.register(ctx, username, password, role)
+}
+
+// ResetPassword replaces the password of a user
+func (s *AdminService) ResetPassword(ctx context.Context, username, password string) (err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.ResetPassword")
+	defer tracing.End(span, &err)
+
+	if len(password) < 6 || len(password) > 72 {
+		return errPasswordLength
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return err
+	}
+	return s.authService.UpdatePassword(ctx, user.ID, password)
+}
+
+// SetRole promotes or demotes a user
+func (s *AdminService) SetRole(ctx context.Context, username, role string) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.SetRole")
+	defer tracing.End(span, &err)
+
+	if !domain.IsValidUserRole(role) {
+		return nil, errInvalidRole
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err := s.userRepo.SetRole(ctx, user.ID, role); err != nil {
+		return nil, err
+	}
+	user.Role = role
+	return user, nil
+}
+
+// SetDisabled disables or re-enables an account. Disabled users cannot log in and
+// the tokens they hold are refused from the next request on.
+func (s *AdminService) SetDisabled(ctx context.Context, username string, disabled bool) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.SetDisabled")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	if user.Disabled() == disabled {
+		return user, nil
+	}
+
+	var disabledAt *time.Time
+	if disabled {
+		now := time.Now().UTC()
+		disabledAt = &now
+	}
+	if err := s.userRepo.SetDisabled(ctx, user.ID, disabledAt); err != nil {
+		return nil, err
+	}
+	user.DisabledAt = disabledAt
+	return user, nil
+}
+
+// ReassignTickets moves every live ticket assigned to one user to another in a
+// single transaction. The activity entries are attributed to actorUsername, the
+// account of the operator running the command.
+func (s *AdminService) ReassignTickets(ctx context.Context, actorUsername, fromUsername, toUsername string) (_ int, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.ReassignTickets")
+	defer tracing.End(span, &err)
+
+	actor, err := s.userRepo.FindByUsername(ctx, actorUsername)
+	if err != nil {
+		return 0, err
+	}
+	from, err := s.userRepo.FindByUsername(ctx, fromUsername)
+	if err != nil {
+		return 0, err
+	}
+	to, err := s.userRepo.FindByUsername(ctx, toUsername)
+	if err != nil {
+		return 0, err
+	}
+	if from.ID == to.ID {
+		return 0, errReassignSameUser
+	}
+	if to.Disabled() {
+		return 0, errAssigneeDisabled
+	}
+
+	tickets, err := s.ticketRepo.FindByFilter(ctx, domain.TicketFilter{AssigneeID: &from.ID})
+	if err != nil {
+		return 0, err
+	}
+	if len(tickets) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int, len(tickets))
+	logs := make([]*domain.ActivityLog, len(tickets))
+	for i := range tickets {
+		t := &tickets[i]
+		ids[i] = t.ID
+		logs[i] = &domain.ActivityLog{
+			TicketID: &t.ID,
+			UserID:   actor.ID,
+			Action:   fmt.Sprintf("reassigned ticket from %s to %s: %s", from.Username, to.Username, t.Title),
+		}
+	}
+
+	changes := domain.TicketChanges{AssigneeID: domain.NullableChange[int]{Set: true, Value: &to.ID}}
+	if err := s.ticketRepo.PatchMany(ctx, ids, changes, logs, domain.AnyEditor); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+// Stats counts users and tickets
+func (s *AdminService) Stats(ctx context.Context) (_ *AdminStats, err error) {
+	ctx, span := tracing.Start(ctx, "AdminService.Stats")
+	defer tracing.End(span, &err)
+
+	users, err := s.userRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byStatus, err := s.ticketRepo.CountByStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+	trashed, err := s.ticketRepo.CountTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &AdminStats{
+		Users:           len(users),
+		TicketsByStatus: byStatus,
+		TrashedTickets:  trashed,
+	}
+	for _, u := range users {
+		if u.Role == domain.RoleAdmin {
+			stats.Admins++
+		}
+		if u.Disabled() {
+			stats.DisabledUsers++
+		}
+	}
+	for _, n := range byStatus {
+		stats.Tickets += n
+	}
+	return stats, nil
+}
@@ -1,11 +1,19 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
 	"go-todolist/internal/utils"
 )
 
+var (
+	errInvalidCredentials = domain.NewUnauthorizedError("invalid_credentials", "Invalid username or password")
+	errAccountDisabled    = domain.NewForbiddenError("account_disabled", "This account has been disabled")
+)
+
 type AuthService struct {
 	userRepo domain.UserRepository
 }
@@ -17,13 +25,13 @@ func NewAuthService(userRepo domain.UserRepository) *AuthService {
 }
 
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
 }
 
 type SignUpRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" binding:"required,min=3,max=255"`
+	Password string `json:"password" binding:"required,min=6,max=72"`
 }
 
 type AuthResponse struct {
@@ -31,21 +39,32 @@ type AuthResponse struct {
 	User  domain.User `json:"user"`
 }
 
-func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req LoginRequest) (_ *AuthResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer tracing.End(span, &err)
+
 	// validate input
 	if req.Username == "" || req.Password == "" {
-		return nil, fmt.Errorf("username and password are required")
+		return nil, domain.NewValidationError("credentials_required", "Username and password are required")
 	}
 
 	// find user by username
-	user, err := s.userRepo.FindByUsername(req.Username)
+	user, err := s.userRepo.FindByUsername(ctx, req.Username)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, errInvalidCredentials
+	}
 	if err != nil {
-		return nil, fmt.Errorf("invalid username or password")
+		return nil, err
 	}
 
 	// check password
 	if err := utils.CheckPassword(user.Password, req.Password); err != nil {
-		return nil, fmt.Errorf("invalid username or password")
+		return nil, errInvalidCredentials
+	}
+
+	// disabled accounts keep their data but can no longer log in
+	if user.Disabled() {
+		return nil, errAccountDisabled
 	}
 
 	// generate JWT token
@@ -60,64 +79,86 @@ func (s *AuthService) Login(req LoginRequest) (*AuthResponse, error) {
 	}, nil
 }
 
-func (s *AuthService) Signup(req SignUpRequest) (*AuthResponse, error) {
+func (s *AuthService) Signup(ctx context.Context, req SignUpRequest) (_ *AuthResponse, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Signup")
+	defer tracing.End(span, &err)
+
+	user, err := s.register(ctx, req.Username, req.Password, domain.RoleUser)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate JWT token
+	token, err := utils.GenerateToken(user.ID, user.Username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return &AuthResponse{
+		Token: token,
+		User:  *user,
+	}, nil
+}
+
+// register stores a new account with role after the signup checks, the role is
+// written with the account so no user ever exists with the wrong one
+func (s *AuthService) register(ctx context.Context, username, password, role string) (*domain.User, error) {
 	// Validate input
-	if req.Username == "" || req.Password == "" {
-		return nil, fmt.Errorf("username and password are required")
+	if username == "" || password == "" {
+		return nil, domain.NewValidationError("credentials_required", "Username and password are required")
 	}
 
-	if len(req.Password) < 6 {
-		return nil, fmt.Errorf("password must be at least 6 characters")
+	if len(password) < 6 {
+		return nil, domain.NewValidationError("password_too_short", "Password must be at least 6 characters")
 	}
 
 	// Checks if username already exists
-	existingUser, _ := s.userRepo.FindByUsername(req.Username)
+	existingUser, err := s.userRepo.FindByUsername(ctx, username)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
 	if existingUser != nil {
-		return nil, fmt.Errorf("username already exists")
+		return nil, domain.NewConflictError("username_taken", "Username already exists")
 	}
 
 	// Hash password
-	hashedPassword, err := utils.HashPassword(req.Password)
+	hashedPassword, err := utils.HashPassword(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Create user
 	user := &domain.User{
-		Username: req.Username,
+		Username: username,
 		Password: hashedPassword,
+		Role:     role,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, fmt.Errorf("failed to create user: %w", err)
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		return nil, err
 	}
+	return user, nil
+}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Username)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate token: %w", err)
-	}
+func (s *AuthService) GetUserByID(ctx context.Context, userID int) (_ *domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.GetUserByID")
+	defer tracing.End(span, &err)
 
-	return &AuthResponse{
-		Token: token,
-		User:  *user,
-	}, nil
+	return s.userRepo.FindByID(ctx, userID)
 }
 
-func (s *AuthService) GetUserByID(userID int) (*domain.User, error) {
-	user, err := s.userRepo.FindByID(userID)
-	if err != nil {
-		return nil, fmt.Errorf("user not found: %w", err)
-	}
-	return user, nil
-}
+func (s *AuthService) FindAllUsers(ctx context.Context) (_ []domain.User, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.FindAllUsers")
+	defer tracing.End(span, &err)
 
-func (s *AuthService) FindAllUsers() ([]domain.User, error) {
-	return s.userRepo.FindAll()
+	return s.userRepo.FindAll(ctx)
 }
 
-func (s *AuthService) UpdateProfile(userID int, username string, profilePhoto string) error {
-	user, err := s.userRepo.FindByID(userID)
+func (s *AuthService) UpdateProfile(ctx context.Context, userID int, username string, profilePhoto string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdateProfile")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -125,11 +166,14 @@ func (s *AuthService) UpdateProfile(userID int, username string, profilePhoto st
 	user.Username = username
 	user.ProfilePhoto = profilePhoto
 
-	return s.userRepo.Update(user)
+	return s.userRepo.Update(ctx, user)
 }
 
-func (s *AuthService) UpdatePassword(userID int, newPassword string) error {
-	user, err := s.userRepo.FindByID(userID)
+func (s *AuthService) UpdatePassword(ctx context.Context, userID int, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.UpdatePassword")
+	defer tracing.End(span, &err)
+
+	user, err := s.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return err
 	}
@@ -141,5 +185,5 @@ func (s *AuthService) UpdatePassword(userID int, newPassword string) error {
 
 	user.Password = hashedPassword
 
-	return s.userRepo.Update(user)
+	return s.userRepo.Update(ctx, user)
 }
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+	"strings"
+)
+
+// BulkTicketRequest lists the tickets to change, the binding caps it at 500
+type BulkTicketRequest struct {
+	TicketIDs []int             `json:"ticket_ids" binding:"required,min=1,max=500,dive,gt=0"`
+	Changes   BulkTicketChanges `json:"changes"`
+}
+
+// BulkTicketChanges is the change set applied to every ticket, nil fields are left untouched
+type BulkTicketChanges struct {
+	Status     *string `json:"status" binding:"omitempty,ticket_status"`
+	Priority   *string `json:"priority" binding:"omitempty,ticket_priority"`
+	AssigneeID *int    `json:"assignee_id" binding:"omitempty,gt=0"`
+	Unassign   bool    `json:"unassign"`
+}
+
+type BulkTicketResult struct {
+	TicketID int    `json:"ticket_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+type BulkTicketResponse struct {
+	Updated int                `json:"updated"`
+	Failed  int                `json:"failed"`
+	Results []BulkTicketResult `json:"results"`
+}
+
+func (c BulkTicketChanges) isEmpty() bool {
+	return c.Status == nil && c.Priority == nil && c.AssigneeID == nil && !c.Unassign
+}
+
+// describe renders the change set for the activity log, e.g. "status to Done, priority to High"
+func (c BulkTicketChanges) describe() string {
+	var parts []string
+	if c.Status != nil {
+		parts = append(parts, "status to "+*c.Status)
+	}
+	if c.Priority != nil {
+		parts = append(parts, "priority to "+*c.Priority)
+	}
+	if c.AssigneeID != nil {
+		parts = append(parts, fmt.Sprintf("assignee to user %d", *c.AssigneeID))
+	}
+	if c.Unassign {
+		parts = append(parts, "removed assignee")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BulkUpdate applies one change set to many tickets. Tickets the user may not modify
+// or that do not exist are reported per item, the rest are written in one transaction
+// that checks the permission again, so a ticket reassigned or trashed in the meantime
+// fails the whole request instead of being changed.
+func (s *TicketService) BulkUpdate(ctx context.Context, req BulkTicketRequest, userID int) (_ *BulkTicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.BulkUpdate")
+	defer tracing.End(span, &err)
+
+	if req.Changes.isEmpty() {
+		return nil, domain.NewValidationError("changes_required", "Changes must set at least one field")
+	}
+	if req.Changes.AssigneeID != nil && req.Changes.Unassign {
+		return nil, domain.NewValidationError("conflicting_changes", "assignee_id and unassign cannot be combined")
+	}
+
+	response := &BulkTicketResponse{Results: make([]BulkTicketResult, 0, len(req.TicketIDs))}
+	var ids []int
+	var logs []*domain.ActivityLog
+	seen := make(map[int]bool, len(req.TicketIDs))
+	action := "bulk updated " + req.Changes.describe()
+
+	for _, id := range req.TicketIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		t, err := s.ticketRepo.FindByID(ctx, id)
+		if errors.Is(err, domain.ErrNotFound) {
+			response.Results = append(response.Results, BulkTicketResult{TicketID: id, Error: "ticket not found"})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if !t.EditableBy(userID) {
+			response.Results = append(response.Results, BulkTicketResult{TicketID: id, Error: "not allowed to modify this ticket"})
+			continue
+		}
+
+		ids = append(ids, t.ID)
+		logs = append(logs, &domain.ActivityLog{
+			TicketID: &t.ID,
+			UserID:   userID,
+			Action:   action,
+		})
+		response.Results = append(response.Results, BulkTicketResult{TicketID: id, Success: true})
+	}
+
+	// only the columns in the change set are written, so concurrent edits of the
+	// other fields are kept
+	if len(ids) > 0 {
+		if err := s.ticketRepo.PatchMany(ctx, ids, req.Changes.ticketChanges(), logs, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, r := range response.Results {
+		if r.Success {
+			response.Updated++
+		} else {
+			response.Failed++
+		}
+	}
+
+	return response, nil
+}
+
+func (c BulkTicketChanges) ticketChanges() domain.TicketChanges {
+	changes := domain.TicketChanges{Status: c.Status, Priority: c.Priority}
+	if c.AssigneeID != nil {
+		changes.AssigneeID = domain.NullableChange[int]{Set: true, Value: c.AssigneeID}
+	}
+	if c.Unassign {
+		changes.AssigneeID = domain.NullableChange[int]{Set: true}
+	}
+	return changes
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+	"strings"
+)
+
+var (
+	errSavedFilterNameRequired = domain.NewValidationError("name_required", "Filter name is required")
+	errSavedFilterNotOwner     = domain.NewForbiddenError("saved_filter_not_owner", "Only the owner can modify a saved filter")
+)
+
+type SavedFilterService struct {
+	savedFilterRepo domain.SavedFilterRepository
+	ticketService   *TicketService
+}
+
+type SavedFilterRequest struct {
+	Name     string              `json:"name" binding:"required,max=255"`
+	Criteria domain.TicketFilter `json:"criteria"`
+	Shared   bool                `json:"shared"`
+}
+
+func NewSavedFilterService(savedFilterRepo domain.SavedFilterRepository, ticketService *TicketService) *SavedFilterService {
+	return &SavedFilterService{
+		savedFilterRepo: savedFilterRepo,
+		ticketService:   ticketService,
+	}
+}
+
+func (s *SavedFilterService) Create(ctx context.Context, userID int, req SavedFilterRequest) (_ *domain.SavedFilter, err error) {
+	ctx, span := tracing.Start(ctx, "SavedFilterService.Create")
+	defer tracing.End(span, &err)
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errSavedFilterNameRequired
+	}
+
+	filter := &domain.SavedFilter{
+		UserID:   userID,
+		Name:     name,
+		Criteria: req.Criteria,
+		Shared:   req.Shared,
+	}
+
+	if err := s.savedFilterRepo.Create(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return s.savedFilterRepo.FindByID(ctx, filter.ID)
+}
+
+// FindVisible lists the user's own filters together with filters shared by others
+func (s *SavedFilterService) FindVisible(ctx context.Context, userID int) (_ []domain.SavedFilter, err error) {
+	ctx, span := tracing.Start(ctx, "SavedFilterService.FindVisible")
+	defer tracing.End(span, &err)
+
+	return s.savedFilterRepo.FindVisibleTo(ctx, userID)
+}
+
+// FindByID returns a filter owned by or shared with the user, filters the user
+// cannot see are reported as not found
+func (s *SavedFilterService) FindByID(ctx context.Context, id int, userID int) (_ *domain.SavedFilter, err error) {
+	ctx, span := tracing.Start(ctx, "SavedFilterService.FindByID")
+	defer tracing.End(span, &err)
+
+	filter, err := s.savedFilterRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.UserID != userID && !filter.Shared {
+		return nil, domain.ErrSavedFilterNotFound
+	}
+
+	return filter, nil
+}
+
+func (s *SavedFilterService) Update(ctx context.Context, id int, userID int, req SavedFilterRequest) (_ *domain.SavedFilter, err error) {
+	ctx, span := tracing.Start(ctx, "SavedFilterService.Update")
+	defer tracing.End(span, &err)
+
+	filter, err := s.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if filter.UserID != userID {
+		return nil, errSavedFilterNotOwner
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, errSavedFilterNameRequired
+	}
+
+	filter.Name = name
+	filter.Criteria = req.Criteria
+	filter.Shared = req.Shared
+
+	if err := s.savedFilterRepo.Update(ctx, filter); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+func (s *SavedFilterService) Delete(ctx context.Context, id int, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "SavedFilterService.Delete")
+	defer tracing.End(span, &err)
+
+	filter, err := s.FindByID(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if filter.UserID != userID {
+		return errSavedFilterNotOwner
+	}
+
+	return s.savedFilterRepo.Delete(ctx, id)
+}
+
+// Execute runs the saved criteria against the ticket listing on behalf of the user,
+// so relative criteria like "assigned to me" apply to whoever runs a shared filter
+func (s *SavedFilterService) Execute(ctx context.Context, id int, userID int) (_ []TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "SavedFilterService.Execute")
+	defer tracing.End(span, &err)
+
+	filter, err := s.FindByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.ticketService.FindTickets(ctx, filter.Criteria, userID)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+	"strings"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+type SearchService struct {
+	ticketRepo domain.TicketRepository
+}
+
+type SearchRequest struct {
+	Query      string
+	Status     string
+	AssigneeID *int
+	Limit      int
+}
+
+type SearchResultResponse struct {
+	TicketResponse
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+func NewSearchService(ticketRepo domain.TicketRepository) *SearchService {
+	return &SearchService{
+		ticketRepo: ticketRepo,
+	}
+}
+
+func (s *SearchService) SearchTickets(ctx context.Context, req SearchRequest) (_ []SearchResultResponse, err error) {
+	ctx, span := tracing.Start(ctx, "SearchService.SearchTickets")
+	defer tracing.End(span, &err)
+
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, domain.NewValidationError("query_required", "Search query is required")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	results, err := s.ticketRepo.Search(ctx, domain.TicketSearchParams{
+		Query:      query,
+		Status:     req.Status,
+		AssigneeID: req.AssigneeID,
+		Limit:      limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]SearchResultResponse, 0, len(results))
+	for _, r := range results {
+		responses = append(responses, SearchResultResponse{
+			TicketResponse: *newTicketResponse(&r.Ticket),
+			Rank:           r.Rank,
+			TitleHighlight: r.TitleHighlight,
+			Snippet:        r.Snippet,
+		})
+	}
+
+	return responses, nil
+}
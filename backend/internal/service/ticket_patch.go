@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+	"strings"
+	"time"
+)
+
+// PatchField tracks whether a member was present in a JSON Merge Patch document.
+// Set is false when the member is absent, Value is nil when it was explicitly null.
+type PatchField[T any] struct {
+	Set   bool
+	Value *T
+}
+
+func (f *PatchField[T]) UnmarshalJSON(data []byte) error {
+	f.Set = true
+	if string(data) == "null" {
+		f.Value = nil
+		return nil
+	}
+
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	f.Value = &v
+	return nil
+}
+
+// TicketPatch is an RFC 7396 merge patch for a ticket, only the members present in
+// the document are applied
+type TicketPatch struct {
+	Title       PatchField[string]    `json:"title"`
+	Description PatchField[string]    `json:"description"`
+	Status      PatchField[string]    `json:"status"`
+	Priority    PatchField[string]    `json:"priority"`
+	DueDate     PatchField[time.Time] `json:"due_date"`
+	AssigneeID  PatchField[int]       `json:"assignee_id"`
+}
+
+// changes turns the patch into the changes to store and the names of the fields it
+// touches. A null title, status or priority is rejected, a null description empties it.
+func (p TicketPatch) changes() (domain.TicketChanges, []string, error) {
+	var changes domain.TicketChanges
+	var fields []string
+
+	if p.Title.Set {
+		if p.Title.Value == nil || strings.TrimSpace(*p.Title.Value) == "" {
+			return changes, nil, domain.NewValidationError("title_required", "Title cannot be empty")
+		}
+		changes.Title = p.Title.Value
+		fields = append(fields, "title")
+	}
+	if p.Description.Set {
+		description := ""
+		if p.Description.Value != nil {
+			description = *p.Description.Value
+		}
+		changes.Description = &description
+		fields = append(fields, "description")
+	}
+	if p.Status.Set {
+		if p.Status.Value == nil {
+			return changes, nil, domain.NewValidationError("status_required", "Status cannot be null")
+		}
+		changes.Status = p.Status.Value
+		fields = append(fields, "status")
+	}
+	if p.Priority.Set {
+		if p.Priority.Value == nil {
+			return changes, nil, domain.NewValidationError("priority_required", "Priority cannot be null")
+		}
+		changes.Priority = p.Priority.Value
+		fields = append(fields, "priority")
+	}
+	if p.DueDate.Set {
+		changes.DueDate = domain.NullableChange[time.Time]{Set: true, Value: p.DueDate.Value}
+		fields = append(fields, "due_date")
+	}
+	if p.AssigneeID.Set {
+		changes.AssigneeID = domain.NullableChange[int]{Set: true, Value: p.AssigneeID.Value}
+		fields = append(fields, "assignee_id")
+	}
+
+	return changes, fields, nil
+}
+
+// PatchTicket applies a merge patch to a ticket. Only the supplied fields are written,
+// so concurrent updates of the others are kept, and only they are recorded in the
+// activity log.
+func (s *TicketService) PatchTicket(ctx context.Context, id int, patch TicketPatch, userID int) (_ *TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.PatchTicket")
+	defer tracing.End(span, &err)
+
+	changes, fields, err := patch.changes()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) > 0 {
+		if err := s.ticketRepo.Patch(ctx, id, changes, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.ticketRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) > 0 {
+		// Log activity
+		s.logActivity(ctx, &domain.ActivityLog{
+			TicketID: &id,
+			UserID:   userID,
+			Action:   "updated " + strings.Join(fields, ", ") + " on ticket: " + updated.Title,
+		})
+	}
+
+	return newTicketResponse(updated), nil
+}
@@ -1,7 +1,11 @@
 package service
 
 import (
+	"context"
 	"go-todolist/internal/domain"
+	"go-todolist/internal/logging"
+	"go-todolist/internal/tracing"
+	"log/slog"
 	"time"
 )
 
@@ -11,13 +15,13 @@ type TicketService struct {
 }
 
 type TicketRequest struct {
-	Title       string     `json:"title"`
+	Title       string     `json:"title" binding:"required,max=255"`
 	Description string     `json:"description"`
-	Status      string     `json:"status"`
-	Priority    string     `json:"priority"`
+	Status      string     `json:"status" binding:"omitempty,ticket_status"`
+	Priority    string     `json:"priority" binding:"omitempty,ticket_priority"`
 	DueDate     *time.Time `json:"due_date"`
 	CreatorID   int        `json:"creator_id"`
-	AssigneeID  *int       `json:"assignee_id"`
+	AssigneeID  *int       `json:"assignee_id" binding:"omitempty,gt=0"`
 }
 
 type TicketResponse struct {
@@ -31,6 +35,7 @@ type TicketResponse struct {
 	CreatorUsername  string     `json:"creator_username"`
 	AssigneeID       *int       `json:"assignee_id"`
 	AssigneeUsername *string    `json:"assignee_username,omitempty"`
+	DeletedAt        *time.Time `json:"deleted_at,omitempty"`
 }
 
 func NewTicketService(ticketRepo domain.TicketRepository, activityLogRepo domain.ActivityLogRepository) *TicketService {
@@ -40,7 +45,10 @@ func NewTicketService(ticketRepo domain.TicketRepository, activityLogRepo domain
 	}
 }
 
-func (s *TicketService) CreateTicket(req TicketRequest) (*TicketResponse, error) {
+func (s *TicketService) CreateTicket(ctx context.Context, req TicketRequest) (_ *TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.CreateTicket")
+	defer tracing.End(span, &err)
+
 	ticket := &domain.Ticket{
 		Title:       req.Title,
 		Description: req.Description,
@@ -51,12 +59,12 @@ func (s *TicketService) CreateTicket(req TicketRequest) (*TicketResponse, error)
 		AssigneeID:  req.AssigneeID,
 	}
 
-	if err := s.ticketRepo.Create(ticket); err != nil {
+	if err := s.ticketRepo.Create(ctx, ticket); err != nil {
 		return nil, err
 	}
 
 	// Log activity
-	s.activityLogRepo.Create(&domain.ActivityLog{
+	s.logActivity(ctx, &domain.ActivityLog{
 		TicketID: &ticket.ID,
 		UserID:   ticket.CreatorID,
 		Action:   "created ticket: " + ticket.Title,
@@ -64,126 +72,215 @@ func (s *TicketService) CreateTicket(req TicketRequest) (*TicketResponse, error)
 
 	// For create, we might need a fresh fetch to get the usernames if the repo doesn't return them
 	// but let's assume the repo FindByID will be used or returned
-	t, err := s.ticketRepo.FindByID(ticket.ID)
+	t, err := s.ticketRepo.FindByID(ctx, ticket.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TicketResponse{
-		ID:               t.ID,
-		Title:            t.Title,
-		Description:      t.Description,
-		Status:           t.Status,
-		Priority:         t.Priority,
-		DueDate:          t.DueDate,
-		CreatorID:        t.CreatorID,
-		CreatorUsername:  t.CreatorUsername,
-		AssigneeID:       t.AssigneeID,
-		AssigneeUsername: t.AssigneeUsername,
-	}, nil
+	return newTicketResponse(t), nil
 }
 
-func (s *TicketService) FindAll() ([]TicketResponse, error) {
-	tickets, err := s.ticketRepo.FindAll()
+func (s *TicketService) FindAll(ctx context.Context) (_ []TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.FindAll")
+	defer tracing.End(span, &err)
+
+	tickets, err := s.ticketRepo.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	var responses []TicketResponse
 	for _, t := range tickets {
-		responses = append(responses, TicketResponse{
-			ID:               t.ID,
-			Title:            t.Title,
-			Description:      t.Description,
-			Status:           t.Status,
-			Priority:         t.Priority,
-			DueDate:          t.DueDate,
-			CreatorID:        t.CreatorID,
-			CreatorUsername:  t.CreatorUsername,
-			AssigneeID:       t.AssigneeID,
-			AssigneeUsername: t.AssigneeUsername,
-		})
+		responses = append(responses, *newTicketResponse(&t))
 	}
 
 	return responses, nil
 }
 
-func (s *TicketService) FindByID(id int) (*TicketResponse, error) {
-	t, err := s.ticketRepo.FindByID(id)
+// FindTickets lists tickets matching filter, relative criteria are resolved against
+// the requesting user and the current date
+func (s *TicketService) FindTickets(ctx context.Context, filter domain.TicketFilter, userID int) (_ []TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.FindTickets")
+	defer tracing.End(span, &err)
+
+	tickets, err := s.ticketRepo.FindByFilter(ctx, resolveTicketFilter(filter, userID, time.Now().UTC()))
 	if err != nil {
 		return nil, err
 	}
 
-	return &TicketResponse{
-		ID:               t.ID,
-		Title:            t.Title,
-		Description:      t.Description,
-		Status:           t.Status,
-		Priority:         t.Priority,
-		DueDate:          t.DueDate,
-		CreatorID:        t.CreatorID,
-		CreatorUsername:  t.CreatorUsername,
-		AssigneeID:       t.AssigneeID,
-		AssigneeUsername: t.AssigneeUsername,
-	}, nil
+	var responses []TicketResponse
+	for _, t := range tickets {
+		responses = append(responses, *newTicketResponse(&t))
+	}
+
+	return responses, nil
 }
 
-func (s *TicketService) UpdateTicket(id int, req TicketRequest) (*TicketResponse, error) {
-	t, err := s.ticketRepo.FindByID(id)
+// ExportTickets calls fn for every ticket matching filter without collecting them,
+// relative criteria are resolved like in FindTickets
+func (s *TicketService) ExportTickets(ctx context.Context, filter domain.TicketFilter, userID int, fn func(*domain.Ticket) error) (err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.ExportTickets")
+	defer tracing.End(span, &err)
+
+	return s.ticketRepo.StreamByFilter(ctx, resolveTicketFilter(filter, userID, time.Now().UTC()), fn)
+}
+
+func (s *TicketService) FindByID(ctx context.Context, id int) (_ *TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.FindByID")
+	defer tracing.End(span, &err)
+
+	t, err := s.ticketRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTicketResponse(t), nil
+}
+
+func (s *TicketService) UpdateTicket(ctx context.Context, id int, req TicketRequest, userID int) (_ *TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.UpdateTicket")
+	defer tracing.End(span, &err)
+
+	t, err := s.ticketRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	t.Title = req.Title
 	t.Description = req.Description
-	t.Status = req.Status
-	t.Priority = req.Priority
 	t.DueDate = req.DueDate
 	t.AssigneeID = req.AssigneeID
+	// status and priority are optional in the request, leaving one out keeps the
+	// stored value instead of blanking it
+	if req.Status != "" {
+		t.Status = req.Status
+	}
+	if req.Priority != "" {
+		t.Priority = req.Priority
+	}
 
-	if err := s.ticketRepo.Update(t); err != nil {
+	if err := s.ticketRepo.Update(ctx, t, userID); err != nil {
 		return nil, err
 	}
 
-	// Log activity (assuming we have a user context here - usually from request)
-	// For now, let's just use creator_id or standard log
-	s.activityLogRepo.Create(&domain.ActivityLog{
+	s.logActivity(ctx, &domain.ActivityLog{
 		TicketID: &id,
-		UserID:   t.CreatorID, // In real world, this should be the current user
+		UserID:   userID,
 		Action:   "updated ticket: " + t.Title,
 	})
 
 	// Fetch again to get usernames
-	updated, err := s.ticketRepo.FindByID(id)
+	updated, err := s.ticketRepo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	return &TicketResponse{
-		ID:               updated.ID,
-		Title:            updated.Title,
-		Description:      updated.Description,
-		Status:           updated.Status,
-		Priority:         updated.Priority,
-		DueDate:          updated.DueDate,
-		CreatorID:        updated.CreatorID,
-		CreatorUsername:  updated.CreatorUsername,
-		AssigneeID:       updated.AssigneeID,
-		AssigneeUsername: updated.AssigneeUsername,
-	}, nil
+	return newTicketResponse(updated), nil
 }
 
-func (s *TicketService) DeleteTicket(id int) error {
-	return s.ticketRepo.Delete(id)
+func (s *TicketService) DeleteTicket(ctx context.Context, id int, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.DeleteTicket")
+	defer tracing.End(span, &err)
+
+	t, err := s.ticketRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.ticketRepo.Delete(ctx, id, userID); err != nil {
+		return err
+	}
+
+	// Log activity, the history stays attached while the ticket is in the trash
+	s.logActivity(ctx, &domain.ActivityLog{
+		TicketID: &id,
+		UserID:   userID,
+		Action:   "moved ticket to trash: " + t.Title,
+	})
+
+	return nil
 }
 
-func (s *TicketService) UpdateStatus(id int, status string, userID int) error {
-	if err := s.ticketRepo.UpdateStatus(id, status); err != nil {
+func (s *TicketService) FindTrashed(ctx context.Context) (_ []TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.FindTrashed")
+	defer tracing.End(span, &err)
+
+	tickets, err := s.ticketRepo.FindTrashed(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var responses []TicketResponse
+	for _, t := range tickets {
+		responses = append(responses, *newTicketResponse(&t))
+	}
+
+	return responses, nil
+}
+
+func (s *TicketService) RestoreTicket(ctx context.Context, id int, userID int) (_ *TicketResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.RestoreTicket")
+	defer tracing.End(span, &err)
+
+	if err := s.ticketRepo.Restore(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	t, err := s.ticketRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Log activity
+	s.logActivity(ctx, &domain.ActivityLog{
+		TicketID: &id,
+		UserID:   userID,
+		Action:   "restored ticket: " + t.Title,
+	})
+
+	return newTicketResponse(t), nil
+}
+
+// PurgeTrash permanently removes tickets that have been in the trash longer than retention
+func (s *TicketService) PurgeTrash(ctx context.Context, retention time.Duration) (_ int64, err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.PurgeTrash")
+	defer tracing.End(span, &err)
+
+	return s.ticketRepo.Purge(ctx, time.Now().UTC().Add(-retention))
+}
+
+// RunTrashPurger purges expired tickets from the trash every interval until ctx is
+// cancelled, it is meant to be started in its own goroutine
+func (s *TicketService) RunTrashPurger(ctx context.Context, retention, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeTrash(ctx, retention)
+		if err != nil {
+			slog.Error("failed to purge trash", "error", err)
+		} else if purged > 0 {
+			slog.Info("purged tickets from trash", "count", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TicketService) UpdateStatus(ctx context.Context, id int, status string, userID int) (err error) {
+	ctx, span := tracing.Start(ctx, "TicketService.UpdateStatus")
+	defer tracing.End(span, &err)
+
+	if err := s.ticketRepo.UpdateStatus(ctx, id, status, userID); err != nil {
 		return err
 	}
 
 	// Log activity
-	s.activityLogRepo.Create(&domain.ActivityLog{
+	s.logActivity(ctx, &domain.ActivityLog{
 		TicketID: &id,
 		UserID:   userID,
 		Action:   "changed status to " + status,
@@ -191,3 +288,48 @@ func (s *TicketService) UpdateStatus(id int, status string, userID int) error {
 
 	return nil
 }
+
+// logActivity records log without failing the request, the ticket change it
+// describes is already saved. A failed write is logged with the request ID.
+func (s *TicketService) logActivity(ctx context.Context, log *domain.ActivityLog) {
+	if err := s.activityLogRepo.Create(ctx, log); err != nil {
+		logging.FromContext(ctx).Error("failed to record activity", "action", log.Action, "error", err)
+	}
+}
+
+func newTicketResponse(t *domain.Ticket) *TicketResponse {
+	return &TicketResponse{
+		ID:               t.ID,
+		Title:            t.Title,
+		Description:      t.Description,
+		Status:           t.Status,
+		Priority:         t.Priority,
+		DueDate:          t.DueDate,
+		CreatorID:        t.CreatorID,
+		CreatorUsername:  t.CreatorUsername,
+		AssigneeID:       t.AssigneeID,
+		AssigneeUsername: t.AssigneeUsername,
+		DeletedAt:        t.DeletedAt,
+	}
+}
+
+func resolveTicketFilter(filter domain.TicketFilter, userID int, now time.Time) domain.TicketFilter {
+	if filter.AssignedToMe {
+		filter.AssigneeID = &userID
+		filter.AssignedToMe = false
+	}
+
+	if filter.DueWithinDays != nil {
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		end := today.AddDate(0, 0, *filter.DueWithinDays+1)
+		if filter.DueAfter == nil {
+			filter.DueAfter = &today
+		}
+		if filter.DueBefore == nil || end.Before(*filter.DueBefore) {
+			filter.DueBefore = &end
+		}
+		filter.DueWithinDays = nil
+	}
+
+	return filter
+}
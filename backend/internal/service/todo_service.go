@@ -1,6 +1,10 @@
 package service
 
-import "go-todolist/internal/domain"
+import (
+	"context"
+	"go-todolist/internal/domain"
+	"go-todolist/internal/tracing"
+)
 
 type TodoService struct {
 	todoRepository domain.TodoRepository
@@ -25,14 +29,17 @@ func NewTodoService(todoRepository domain.TodoRepository) *TodoService {
 	}
 }
 
-func (s *TodoService) CreateTodo(req TodoRequest) (*TodoResponse, error) {
+func (s *TodoService) CreateTodo(ctx context.Context, req TodoRequest) (_ *TodoResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TodoService.CreateTodo")
+	defer tracing.End(span, &err)
+
 	todo := &domain.Todo{
 		Title:       req.Title,
 		Description: req.Description,
 		Status:      req.Status,
 	}
 
-	if err := s.todoRepository.Create(todo); err != nil {
+	if err := s.todoRepository.Create(ctx, todo); err != nil {
 		return nil, err
 	}
 
@@ -44,8 +51,11 @@ func (s *TodoService) CreateTodo(req TodoRequest) (*TodoResponse, error) {
 	}, nil
 }
 
-func (s *TodoService) FindAll() ([]TodoResponse, error) {
-	todos, err := s.todoRepository.FindAll()
+func (s *TodoService) FindAll(ctx context.Context) (_ []TodoResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TodoService.FindAll")
+	defer tracing.End(span, &err)
+
+	todos, err := s.todoRepository.FindAll(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -63,8 +73,11 @@ func (s *TodoService) FindAll() ([]TodoResponse, error) {
 	return todoResponses, nil
 }
 
-func (s *TodoService) FindById(id int) (*TodoResponse, error) {
-	todo, err := s.todoRepository.FindById(id)
+func (s *TodoService) FindById(ctx context.Context, id int) (_ *TodoResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TodoService.FindById")
+	defer tracing.End(span, &err)
+
+	todo, err := s.todoRepository.FindById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -77,8 +90,11 @@ func (s *TodoService) FindById(id int) (*TodoResponse, error) {
 	}, nil
 }
 
-func (s *TodoService) UpdateTodo(id int, req TodoRequest) (*TodoResponse, error) {
-	todo, err := s.todoRepository.FindById(id)
+func (s *TodoService) UpdateTodo(ctx context.Context, id int, req TodoRequest) (_ *TodoResponse, err error) {
+	ctx, span := tracing.Start(ctx, "TodoService.UpdateTodo")
+	defer tracing.End(span, &err)
+
+	todo, err := s.todoRepository.FindById(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -87,7 +103,7 @@ func (s *TodoService) UpdateTodo(id int, req TodoRequest) (*TodoResponse, error)
 	todo.Description = req.Description
 	todo.Status = req.Status
 
-	if err := s.todoRepository.Update(todo); err != nil {
+	if err := s.todoRepository.Update(ctx, todo); err != nil {
 		return nil, err
 	}
 
@@ -99,8 +115,11 @@ func (s *TodoService) UpdateTodo(id int, req TodoRequest) (*TodoResponse, error)
 	}, nil
 }
 
-func (s *TodoService) DeleteTodo(id int) error {
-	if err := s.todoRepository.Delete(id); err != nil {
+func (s *TodoService) DeleteTodo(ctx context.Context, id int) (err error) {
+	ctx, span := tracing.Start(ctx, "TodoService.DeleteTodo")
+	defer tracing.End(span, &err)
+
+	if err := s.todoRepository.Delete(ctx, id); err != nil {
 		return err
 	}
 	return nil
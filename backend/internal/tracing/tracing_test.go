@@ -0,0 +1,177 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// record installs a provider that keeps finished spans in memory for the test
+func record(t *testing.T) (*tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := NewProvider(recorder, "test")
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder, provider
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestEndRecordsReturnedError(t *testing.T) {
+	recorder, _ := record(t)
+
+	failing := func(ctx context.Context) (err error) {
+		_, span := Start(ctx, "Service.Fail")
+		defer End(span, &err)
+		return errors.New("boom")
+	}
+	working := func(ctx context.Context) (err error) {
+		_, span := Start(ctx, "Service.Work")
+		defer End(span, &err)
+		return nil
+	}
+	failing(context.Background())
+	working(context.Background())
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	if s := spans[0]; s.Status().Code != codes.Error || s.Status().Description != "boom" || len(s.Events()) != 1 || s.Events()[0].Name != "exception" {
+		t.Fatalf("failed span: status %+v, events %+v", s.Status(), s.Events())
+	}
+	if s := spans[1]; s.Status().Code != codes.Unset || len(s.Events()) != 0 {
+		t.Fatalf("working span: status %+v, events %+v", s.Status(), s.Events())
+	}
+}
+
+func TestSamplingFollowsIncomingTraceparent(t *testing.T) {
+	recorder, _ := record(t)
+
+	for _, tc := range []struct {
+		traceparent string
+		sampled     bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", false},
+		{"", true},
+	} {
+		header := http.Header{}
+		if tc.traceparent != "" {
+			header.Set("traceparent", tc.traceparent)
+		}
+		ctx := Propagator.Extract(context.Background(), propagation.HeaderCarrier(header))
+
+		_, span := Start(ctx, "request")
+		span.End()
+		if got := span.SpanContext().IsSampled(); got != tc.sampled {
+			t.Errorf("traceparent %q: sampled = %v, want %v", tc.traceparent, got, tc.sampled)
+		}
+		if tc.traceparent != "" && span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Errorf("traceparent %q: trace not continued, got %s", tc.traceparent, span.SpanContext().TraceID())
+		}
+	}
+
+	if n := len(recorder.Ended()); n != 2 {
+		t.Fatalf("exported %d spans, want the 2 sampled ones", n)
+	}
+}
+
+func TestPgxTracer(t *testing.T) {
+	recorder, _ := record(t)
+	tracer := PgxTracer{}
+
+	ctx, parent := Start(context.Background(), "TicketService.FindByID")
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "select *\n  from tickets where id = $1", Args: []any{"secret"}})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: pgx.ErrNoRows})
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "UPDATE tickets SET title = $1"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("UPDATE 3")})
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "DELETE FROM tickets"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("permission denied")})
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 4 {
+		t.Fatalf("got %d spans", len(spans))
+	}
+	for _, s := range spans[:3] {
+		if s.Parent().SpanID() != parent.SpanContext().SpanID() || s.SpanKind() != trace.SpanKindClient {
+			t.Fatalf("%s: parent %s, kind %s", s.Name(), s.Parent().SpanID(), s.SpanKind())
+		}
+	}
+
+	selectSpan := attributes(spans[0])
+	if spans[0].Name() != "db.query SELECT" || selectSpan["db.query.text"].AsString() != "select * from tickets where id = $1" || selectSpan["db.system.name"].AsString() != "postgresql" {
+		t.Fatalf("select span %s: %v", spans[0].Name(), selectSpan)
+	}
+	if spans[0].Status().Code == codes.Error {
+		t.Fatal("no rows recorded as a failure")
+	}
+	for _, kv := range spans[0].Attributes() {
+		if kv.Value.Emit() == "secret" {
+			t.Fatal("query argument recorded")
+		}
+	}
+	if got := attributes(spans[1])["db.rows_affected"].AsInt64(); got != 3 {
+		t.Fatalf("rows affected = %d", got)
+	}
+	if spans[2].Status().Code != codes.Error {
+		t.Fatalf("failed query status %+v", spans[2].Status())
+	}
+}
+
+func TestSpansEndedAfterShutdownAreDropped(t *testing.T) {
+	recorder, provider := record(t)
+
+	_, span := Start(context.Background(), "in flight")
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	span.End()
+
+	_, late := Start(context.Background(), "late")
+	late.End()
+
+	if n := len(recorder.Ended()); n != 0 {
+		t.Fatalf("exported %d spans after shutdown", n)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	for _, exporter := range []string{"none", "stdout", "otlp"} {
+		shutdown, err := Setup(context.Background(), exporter, "http://127.0.0.1:1", "test")
+		if err != nil {
+			t.Fatalf("%s: %v", exporter, err)
+		}
+		if err := shutdown(context.Background()); err != nil {
+			t.Fatalf("%s shutdown: %v", exporter, err)
+		}
+	}
+
+	if _, err := Setup(context.Background(), "zipkin", "", "test"); err == nil {
+		t.Fatal("unknown exporter accepted")
+	}
+}
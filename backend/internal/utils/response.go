@@ -1,16 +1,19 @@
 package utils
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
+	Success bool         `json:"success"`
+	Message string       `json:"message,omitempty"`
+	Data    interface{}  `json:"data,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Code    string       `json:"code,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
 }
 
 // SuccessResponse sends a successful JSON response
@@ -30,11 +33,46 @@ func ErrorResponse(c *gin.Context, statusCode int, message string) {
 	})
 }
 
+// CodedErrorResponse sends an error JSON response with a stable machine-readable code
+func CodedErrorResponse(c *gin.Context, statusCode int, code string, message string) {
+	c.JSON(statusCode, Response{
+		Success: false,
+		Error:   message,
+		Code:    code,
+	})
+}
+
 // ValidationErroResponse sends a validation error response
 func ValidationErrorResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusBadRequest, message)
 }
 
+// ValidationErrorsResponse sends a validation error response listing every invalid field
+func ValidationErrorsResponse(c *gin.Context, errs []FieldError) {
+	c.JSON(http.StatusBadRequest, Response{
+		Success: false,
+		Error:   "Validation failed",
+		Code:    "validation_failed",
+		Errors:  errs,
+	})
+}
+
+// BindingErrorResponse sends the field errors of a failed ShouldBindJSON call
+func BindingErrorResponse(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, Response{
+			Success: false,
+			Error:   "Request body too large",
+			Code:    "request_too_large",
+			Errors:  BindingErrors(err),
+		})
+		return
+	}
+
+	ValidationErrorsResponse(c, BindingErrors(err))
+}
+
 // UnauthorizedResponse sends an unauthorized response
 func UnauthorizedResponse(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusUnauthorized, message)
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-todolist/internal/domain"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes one invalid field of a request body
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+var registerOnce sync.Once
+
+// RegisterValidators adds the custom binding rules used by request types
+// (ticket_status, ticket_priority) and reports fields by their JSON name
+func RegisterValidators() {
+	registerOnce.Do(func() {
+		v, ok := binding.Validator.Engine().(*validator.Validate)
+		if !ok {
+			return
+		}
+
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+			if name == "-" {
+				return ""
+			}
+			if name == "" {
+				return field.Name
+			}
+			return name
+		})
+
+		v.RegisterValidation("ticket_status", func(fl validator.FieldLevel) bool {
+			return domain.IsValidTicketStatus(fl.Field().String())
+		})
+		v.RegisterValidation("ticket_priority", func(fl validator.FieldLevel) bool {
+			return domain.IsValidTicketPriority(fl.Field().String())
+		})
+	})
+}
+
+// BindingErrors converts an error returned by ShouldBindJSON into field errors
+func BindingErrors(err error) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fieldErrs := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fieldErrs = append(fieldErrs, newFieldError(fe))
+		}
+		return fieldErrs
+	}
+
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return []FieldError{{
+			Field:   "body",
+			Code:    "too_large",
+			Message: fmt.Sprintf("request body must be at most %d bytes", maxBytesErr.Limit),
+		}}
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return []FieldError{{
+			Field:   typeErr.Field,
+			Code:    "invalid_type",
+			Message: fmt.Sprintf("must be of type %s", typeErr.Type.String()),
+		}}
+	}
+
+	// encoding/json has no error type for members rejected by DisallowUnknownFields
+	if field, ok := strings.CutPrefix(err.Error(), `json: unknown field "`); ok {
+		return []FieldError{{
+			Field:   strings.TrimSuffix(field, `"`),
+			Code:    "unknown_field",
+			Message: "is not a known field",
+		}}
+	}
+
+	return []FieldError{{
+		Field:   "body",
+		Code:    "invalid_json",
+		Message: "request body must be valid JSON",
+	}}
+}
+
+func newFieldError(fe validator.FieldError) FieldError {
+	field := fieldPath(fe.Namespace())
+
+	switch fe.Tag() {
+	case "required":
+		return FieldError{Field: field, Code: "required", Message: "is required"}
+	case "min":
+		if fe.Kind() == reflect.String {
+			return FieldError{Field: field, Code: "too_short", Message: fmt.Sprintf("must be at least %s characters", fe.Param())}
+		}
+		return FieldError{Field: field, Code: "too_small", Message: fmt.Sprintf("must contain at least %s items", fe.Param())}
+	case "max":
+		if fe.Kind() == reflect.String {
+			return FieldError{Field: field, Code: "too_long", Message: fmt.Sprintf("must be at most %s characters", fe.Param())}
+		}
+		return FieldError{Field: field, Code: "too_large", Message: fmt.Sprintf("must contain at most %s items", fe.Param())}
+	case "gt":
+		return FieldError{Field: field, Code: "out_of_range", Message: fmt.Sprintf("must be greater than %s", fe.Param())}
+	case "gte":
+		return FieldError{Field: field, Code: "out_of_range", Message: fmt.Sprintf("must be at least %s", fe.Param())}
+	case "ticket_status":
+		return FieldError{Field: field, Code: "invalid_choice", Message: "must be one of: " + strings.Join(domain.TicketStatuses, ", ")}
+	case "ticket_priority":
+		return FieldError{Field: field, Code: "invalid_choice", Message: "must be one of: " + strings.Join(domain.TicketPriorities, ", ")}
+	default:
+		return FieldError{Field: field, Code: fe.Tag(), Message: "is invalid"}
+	}
+}
+
+// fieldPath drops the request type name from a namespace like
+// "TicketRequest.title" or "BulkTicketRequest.changes.status"
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
@@ -1,11 +1,14 @@
 import * as React from "react"
+import { useQuery } from "@tanstack/react-query"
 import {
+  FileText,
   Plus,
   Search,
   Settings,
   User,
 } from "lucide-react"
 
+import api from "@/lib/api"
 import {
   CommandDialog,
   CommandEmpty,
@@ -17,8 +20,49 @@ import {
   CommandShortcut,
 } from "@/components/ui/command"
 
+interface SearchResult {
+  id: number
+  title: string
+  status: string
+  priority: string
+  title_highlight: string
+  snippet: string
+}
+
+// Renders a ts_headline fragment, only the <mark> tags are turned into elements
+// so ticket text is never injected as HTML
+function Highlighted({ text }: { text: string }) {
+  const parts = text.split(/(<mark>.*?<\/mark>)/g)
+  return (
+    <>
+      {parts.map((part, i) =>
+        part.startsWith("<mark>") ? (
+          <mark key={i} className="bg-yellow-200 dark:bg-yellow-700 rounded-sm px-0.5">
+            {part.slice(6, -7)}
+          </mark>
+        ) : (
+          <React.Fragment key={i}>{part}</React.Fragment>
+        )
+      )}
+    </>
+  )
+}
+
+function useDebouncedValue<T>(value: T, delay: number) {
+  const [debounced, setDebounced] = React.useState(value)
+
+  React.useEffect(() => {
+    const timer = setTimeout(() => setDebounced(value), delay)
+    return () => clearTimeout(timer)
+  }, [value, delay])
+
+  return debounced
+}
+
 export default function CommandPalette() {
   const [open, setOpen] = React.useState(false)
+  const [query, setQuery] = React.useState("")
+  const debouncedQuery = useDebouncedValue(query.trim(), 200)
 
   React.useEffect(() => {
     const down = (e: KeyboardEvent) => {
@@ -32,38 +76,85 @@ export default function CommandPalette() {
     return () => document.removeEventListener("keydown", down)
   }, [])
 
+  const { data: results, isFetching } = useQuery({
+    queryKey: ["search", debouncedQuery],
+    queryFn: async () => {
+      const response = await api.get("/search", { params: { q: debouncedQuery, limit: 10 } })
+      return (response.data.data ?? []) as SearchResult[]
+    },
+    enabled: open && debouncedQuery.length >= 2,
+    staleTime: 30_000,
+  })
+
+  const isSearching = query.trim().length >= 2
+
   return (
-    <CommandDialog open={open} onOpenChange={setOpen}>
-      <CommandInput placeholder="Type a command or search..." />
+    <CommandDialog open={open} onOpenChange={setOpen} shouldFilter={!isSearching}>
+      <CommandInput
+        placeholder="Type a command or search..."
+        value={query}
+        onValueChange={setQuery}
+      />
       <CommandList>
-        <CommandEmpty>No results found.</CommandEmpty>
-        <CommandGroup heading="Suggestions">
-          <CommandItem onSelect={() => {
-            setOpen(false)
-            // trigger create ticket logic
-          }}>
-            <Plus className="mr-2 h-4 w-4" />
-            <span>New Issue</span>
-            <CommandShortcut>⌘N</CommandShortcut>
-          </CommandItem>
-          <CommandItem>
-            <Search className="mr-2 h-4 w-4" />
-            <span>Search Issues</span>
-          </CommandItem>
-        </CommandGroup>
-        <CommandSeparator />
-        <CommandGroup heading="Settings">
-          <CommandItem>
-            <User className="mr-2 h-4 w-4" />
-            <span>Profile</span>
-            <CommandShortcut>⌘P</CommandShortcut>
-          </CommandItem>
-          <CommandItem>
-            <Settings className="mr-2 h-4 w-4" />
-            <span>Settings</span>
-            <CommandShortcut>⌘S</CommandShortcut>
-          </CommandItem>
-        </CommandGroup>
+        <CommandEmpty>{isFetching ? "Searching..." : "No results found."}</CommandEmpty>
+        {isSearching && results && results.length > 0 && (
+          <>
+            <CommandGroup heading="Tickets">
+              {results.map((ticket) => (
+                <CommandItem
+                  key={ticket.id}
+                  value={`ticket-${ticket.id}`}
+                  onSelect={() => setOpen(false)}
+                >
+                  <FileText className="mr-2 h-4 w-4" />
+                  <div className="flex flex-col overflow-hidden">
+                    <span className="truncate">
+                      <Highlighted text={ticket.title_highlight} />
+                    </span>
+                    {ticket.snippet && (
+                      <span className="truncate text-xs text-muted-foreground">
+                        <Highlighted text={ticket.snippet} />
+                      </span>
+                    )}
+                  </div>
+                  <CommandShortcut>{ticket.status}</CommandShortcut>
+                </CommandItem>
+              ))}
+            </CommandGroup>
+            <CommandSeparator />
+          </>
+        )}
+        {!isSearching && (
+          <>
+            <CommandGroup heading="Suggestions">
+              <CommandItem onSelect={() => {
+                setOpen(false)
+                // trigger create ticket logic
+              }}>
+                <Plus className="mr-2 h-4 w-4" />
+                <span>New Issue</span>
+                <CommandShortcut>⌘N</CommandShortcut>
+              </CommandItem>
+              <CommandItem>
+                <Search className="mr-2 h-4 w-4" />
+                <span>Search Issues</span>
+              </CommandItem>
+            </CommandGroup>
+            <CommandSeparator />
+            <CommandGroup heading="Settings">
+              <CommandItem>
+                <User className="mr-2 h-4 w-4" />
+                <span>Profile</span>
+                <CommandShortcut>⌘P</CommandShortcut>
+              </CommandItem>
+              <CommandItem>
+                <Settings className="mr-2 h-4 w-4" />
+                <span>Settings</span>
+                <CommandShortcut>⌘S</CommandShortcut>
+              </CommandItem>
+            </CommandGroup>
+          </>
+        )}
       </CommandList>
     </CommandDialog>
   )